@@ -0,0 +1,147 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrTenantNotBound is returned when a collection is queried before it was
+	// bound to a tenant with `ForTenant` or opted out with `AllTenants`.
+	ErrTenantNotBound = errors.New("collection is not bound to a tenant")
+
+	// ErrTenantMismatch is returned when writing a document which belongs to
+	// a different tenant than the one the collection is bound to.
+	ErrTenantMismatch = errors.New("document belongs to a different tenant")
+)
+
+// TenantCollection wraps a collection so every query, update and delete it
+// runs is constrained to the `tenant_id` it was bound to. A collection which
+// has not been bound refuses to run anything until either `ForTenant` or the
+// explicit `AllTenants` opt-out is called. The underlying collection is not
+// exposed, only the operations below which apply the tenant are, so add a
+// scoped wrapper here before using any other operation.
+type TenantCollection struct {
+	coll       *mongo.Collection
+	tenantID   primitive.ObjectID
+	allTenants bool
+}
+
+func NewTenantCollection(coll *mongo.Collection) *TenantCollection {
+	return &TenantCollection{coll: coll}
+}
+
+// ForTenant returns a copy of the collection constrained to the tenant.
+func (tc *TenantCollection) ForTenant(tenantID primitive.ObjectID) *TenantCollection {
+	return &TenantCollection{coll: tc.coll, tenantID: tenantID}
+}
+
+// AllTenants returns a copy of the collection which is not constrained to any
+// tenant. Only use this for tooling which must operate across tenants.
+func (tc *TenantCollection) AllTenants() *TenantCollection {
+	return &TenantCollection{coll: tc.coll, allTenants: true}
+}
+
+// TenantID returns the tenant the collection is bound to, if any.
+func (tc *TenantCollection) TenantID() primitive.ObjectID {
+	return tc.tenantID
+}
+
+// Name returns the name of the collection.
+func (tc *TenantCollection) Name() string {
+	return tc.coll.Name()
+}
+
+// scope returns the filter constrained to the bound tenant.
+func (tc *TenantCollection) scope(filter interface{}) (interface{}, error) {
+	if tc.allTenants {
+		return filter, nil
+	}
+	if tc.tenantID.IsZero() {
+		return nil, ErrTenantNotBound
+	}
+	if filter == nil {
+		return bson.M{"tenant_id": tc.tenantID}, nil
+	}
+	return bson.M{"$and": bson.A{filter, bson.M{"tenant_id": tc.tenantID}}}, nil
+}
+
+// checkTenantOf returns an error if the document at `path` inside `doc` has
+// a `tenant_id` which does not match the bound tenant.
+func (tc *TenantCollection) checkTenantOf(doc interface{}, path ...string) error {
+	if tc.allTenants {
+		return nil
+	}
+	if tc.tenantID.IsZero() {
+		return ErrTenantNotBound
+	}
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	v, err := bson.Raw(raw).LookupErr(append(path, "tenant_id")...)
+	if err != nil {
+		// The document does not set the tenant so there is nothing to check.
+		return nil
+	}
+	if id, ok := v.ObjectIDOK(); !ok || id != tc.tenantID {
+		return ErrTenantMismatch
+	}
+	return nil
+}
+
+func (tc *TenantCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	scoped, err := tc.scope(filter)
+	if err != nil {
+		return nil, err
+	}
+	return tc.coll.Find(ctx, scoped, opts...)
+}
+
+func (tc *TenantCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	scoped, err := tc.scope(filter)
+	if err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return tc.coll.FindOne(ctx, scoped, opts...)
+}
+
+func (tc *TenantCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	scoped, err := tc.scope(filter)
+	if err != nil {
+		return 0, err
+	}
+	return tc.coll.CountDocuments(ctx, scoped, opts...)
+}
+
+func (tc *TenantCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	if err := tc.checkTenantOf(document); err != nil {
+		return nil, err
+	}
+	return tc.coll.InsertOne(ctx, document, opts...)
+}
+
+func (tc *TenantCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	scoped, err := tc.scope(filter)
+	if err != nil {
+		return nil, err
+	}
+	// Prevent moving a document into another tenant.
+	if err := tc.checkTenantOf(update, "$set"); err != nil {
+		return nil, err
+	}
+	return tc.coll.UpdateOne(ctx, scoped, update, opts...)
+}
+
+func (tc *TenantCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	scoped, err := tc.scope(filter)
+	if err != nil {
+		return nil, err
+	}
+	return tc.coll.DeleteOne(ctx, scoped, opts...)
+}
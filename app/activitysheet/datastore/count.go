@@ -10,7 +10,7 @@ import (
 )
 
 func (impl ActivitySheetStorerImpl) CountByFilter(ctx context.Context, f *ActivitySheetPaginationListFilter) (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Create the filter based on the cursor
@@ -39,6 +39,11 @@ func (impl ActivitySheetStorerImpl) CountByFilter(ctx context.Context, f *Activi
 	impl.Logger.Debug("counting w/ filter:",
 		slog.Any("filter", filter))
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Use the CountDocuments method to count the matching documents.
 	count, err := impl.Collection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -49,7 +54,7 @@ func (impl ActivitySheetStorerImpl) CountByFilter(ctx context.Context, f *Activi
 }
 
 func (impl ActivitySheetStorerImpl) CountByLast30DaysForAssociateID(ctx context.Context, associateID primitive.ObjectID) (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Calculate the date for 30 days ago
@@ -64,6 +69,9 @@ func (impl ActivitySheetStorerImpl) CountByLast30DaysForAssociateID(ctx context.
 	impl.Logger.Debug("counting w/ filter:",
 		slog.Any("filter", filter))
 
+	// Exclude documents in the trash.
+	filter["deleted_at"] = bson.M{"$exists": false}
+
 	// Use the CountDocuments method to count the matching documents.
 	count, err := impl.Collection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -10,6 +10,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 	c "github.com/over55/workery-cli/config"
 )
 
@@ -49,6 +51,9 @@ type ActivitySheet struct {
 	OrderTenantIDWithWJID     string             `bson:"order_tenant_id_with_wjid" json:"order_tenant_id_with_wjid"` // TenantIDWithWJID is a combination of `tenancy_id` and `wjid` values written in the following structure `%v_%v`.
 	PublicID                  uint64             `bson:"public_id" json:"public_id"`
 	// OngoingOrderID        primitive.ObjectID `bson:"ongoing_order_id" json:"ongoing_order_id"`
+	DeletedAt         time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedByUserID   primitive.ObjectID `bson:"deleted_by_user_id,omitempty" json:"deleted_by_user_id,omitempty"`
+	DeletedByUserName string             `bson:"deleted_by_user_name,omitempty" json:"deleted_by_user_name,omitempty"`
 }
 
 type ActivitySheetListFilter struct {
@@ -66,6 +71,9 @@ type ActivitySheetListFilter struct {
 	Status          int8
 	ExcludeArchived bool
 	SearchText      string
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 type ActivitySheetListResult struct {
@@ -94,16 +102,24 @@ type ActivitySheetStorer interface {
 	ListAsSelectOptionByFilter(ctx context.Context, f *ActivitySheetPaginationListFilter) ([]*ActivitySheetAsSelectOption, error)
 	ListByOrderID(ctx context.Context, orderID primitive.ObjectID) (*ActivitySheetPaginationListResult, error)
 	ListByOrderWJID(ctx context.Context, orderWJID uint64) (*ActivitySheetPaginationListResult, error)
+	ListByAssociateID(ctx context.Context, associateID primitive.ObjectID) (*ActivitySheetPaginationListResult, error)
+	ListByAssociateIDWithDeleted(ctx context.Context, associateID primitive.ObjectID) (*ActivitySheetPaginationListResult, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
-	PermanentlyDeleteAllByAssociateID(ctx context.Context, associateID primitive.ObjectID) error
+	PermanentlyDeleteByID(ctx context.Context, id primitive.ObjectID) error
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	ListDeletedBefore(ctx context.Context, deletedBefore time.Time) ([]*ActivitySheet, error)
+	DeleteAllByAssociateID(ctx context.Context, associateID primitive.ObjectID) error
 	CountByFilter(ctx context.Context, f *ActivitySheetPaginationListFilter) (int64, error)
 	CountByLast30DaysForAssociateID(ctx context.Context, associateID primitive.ObjectID) (int64, error)
+	ForTenant(tenantID primitive.ObjectID) ActivitySheetStorer
+	AllTenants() ActivitySheetStorer
 }
 
 type ActivitySheetStorerImpl struct {
-	Logger     *slog.Logger
-	DbClient   *mongo.Client
-	Collection *mongo.Collection
+	Logger         *slog.Logger
+	DbClient       *mongo.Client
+	Collection     *mongodb.TenantCollection
+	AuditLogStorer auditlog_ds.AuditLogStorer
 }
 
 func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) ActivitySheetStorer {
@@ -111,6 +127,7 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Ac
 	uc := client.Database(appCfg.DB.Name).Collection("activity_sheets")
 
 	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
 		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
 		{Keys: bson.D{{Key: "public_id", Value: -1}}},
 		{Keys: bson.D{{Key: "status", Value: 1}}},
@@ -127,9 +144,10 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Ac
 	}
 
 	s := &ActivitySheetStorerImpl{
-		Logger:     loggerp,
-		DbClient:   client,
-		Collection: uc,
+		Logger:         loggerp,
+		DbClient:       client,
+		Collection:     mongodb.NewTenantCollection(uc),
+		AuditLogStorer: auditlog_ds.NewDatastore(appCfg, loggerp, client),
 	}
 	return s
 }
@@ -3,20 +3,70 @@ package datastore
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 )
 
 func (impl ActivitySheetStorerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
-	_, err := impl.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	// Get the previous state of the document so we can audit the change.
+	prev, err := impl.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if prev == nil || !prev.DeletedAt.IsZero() {
+		return nil // Nothing to delete.
+	}
+
+	// DEVELOPERS NOTE: Documents are moved to the trash and can be restored
+	// until they are purged with `PermanentlyDeleteByID`.
+	userID, userName, _ := auditlog_ds.GetActor(ctx)
+	curr := *prev
+	curr.DeletedAt = time.Now()
+	curr.DeletedByUserID = userID
+	curr.DeletedByUserName = userName
+
+	update := bson.M{"$set": bson.M{
+		"deleted_at":           curr.DeletedAt,
+		"deleted_by_user_id":   curr.DeletedByUserID,
+		"deleted_by_user_name": curr.DeletedByUserName,
+	}}
+	_, err = impl.Collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+
+	if err := impl.AuditLogStorer.RecordChange(ctx, impl.Collection.Name(), auditlog_ds.AuditLogActionDelete, prev.TenantID, id, prev, &curr); err != nil {
+		impl.Logger.Warn("failed recording audit log", slog.Any("error", err))
+	}
+	return nil
+}
+
+func (impl ActivitySheetStorerImpl) PermanentlyDeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	// Get the previous state of the document so we can audit the change.
+	prev, err := impl.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
+	if prev == nil {
+		return nil // Nothing to delete.
+	}
+
+	_, err = impl.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if err := impl.AuditLogStorer.RecordChange(ctx, impl.Collection.Name(), auditlog_ds.AuditLogActionPurge, prev.TenantID, id, prev, nil); err != nil {
+		impl.Logger.Warn("failed recording audit log", slog.Any("error", err))
+	}
 	return nil
 }
 
-func (impl *ActivitySheetStorerImpl) PermanentlyDeleteAllByAssociateID(ctx context.Context, associateID primitive.ObjectID) error {
+func (impl *ActivitySheetStorerImpl) DeleteAllByAssociateID(ctx context.Context, associateID primitive.ObjectID) error {
 	f := &ActivitySheetPaginationListFilter{
 		PageSize:    1_000_000,
 		SortField:   "_id",
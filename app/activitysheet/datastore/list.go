@@ -12,7 +12,7 @@ import (
 )
 
 func (impl ActivitySheetStorerImpl) ListByFilter(ctx context.Context, f *ActivitySheetPaginationListFilter) (*ActivitySheetPaginationListResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	filter, err := impl.newPaginationFilter(f)
@@ -56,6 +56,11 @@ func (impl ActivitySheetStorerImpl) ListByFilter(ctx context.Context, f *Activit
 		options.SetSort(bson.D{{"score", bson.M{"$meta": "textScore"}}})
 	}
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Execute the query
 	cursor, err := impl.Collection.Find(ctx, filter, options)
 	if err != nil {
@@ -101,7 +106,7 @@ func (impl ActivitySheetStorerImpl) ListByFilter(ctx context.Context, f *Activit
 }
 
 func (impl ActivitySheetStorerImpl) ListAsSelectOptionByFilter(ctx context.Context, f *ActivitySheetPaginationListFilter) ([]*ActivitySheetAsSelectOption, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Get a reference to the collection
@@ -152,6 +157,11 @@ func (impl ActivitySheetStorerImpl) ListAsSelectOptionByFilter(ctx context.Conte
 
 	options.SetSort(bson.D{{sortField, 1}}) // Sort in ascending order based on the specified field
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		query["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Retrieve the list of items from the collection
 	cursor, err := collection.Find(ctx, query, options)
 	if err != nil {
@@ -31,7 +31,7 @@ type ActivitySheetLiteListResult struct {
 }
 
 func (impl ActivitySheetStorerImpl) LiteListByFilter(ctx context.Context, f *ActivitySheetPaginationListFilter) (*ActivitySheetPaginationLiteListResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	filter, err := impl.newPaginationFilter(f)
@@ -75,6 +75,11 @@ func (impl ActivitySheetStorerImpl) LiteListByFilter(ctx context.Context, f *Act
 		options.SetSort(bson.D{{"score", bson.M{"$meta": "textScore"}}})
 	}
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Execute the query
 	cursor, err := impl.Collection.Find(ctx, filter, options)
 	if err != nil {
@@ -31,6 +31,9 @@ type ActivitySheetPaginationListFilter struct {
 	Status          int8
 	ExcludeArchived bool
 	SearchText      string
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 // ActivitySheetPaginationListResult represents the paginated list results for
@@ -35,3 +35,36 @@ func (impl ActivitySheetStorerImpl) ListByOrderWJID(ctx context.Context, orderWJ
 	}
 	return res, nil
 }
+
+func (impl ActivitySheetStorerImpl) ListByAssociateID(ctx context.Context, associateID primitive.ObjectID) (*ActivitySheetPaginationListResult, error) {
+	f := &ActivitySheetPaginationListFilter{
+		Cursor:      "",
+		PageSize:    1_000_00,
+		SortField:   "", // Setting this empty to ignore any sorting.
+		SortOrder:   SortOrderAscending,
+		AssociateID: associateID,
+	}
+	res, err := impl.ListByFilter(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListByAssociateIDWithDeleted does the same as `ListByAssociateID` along
+// with the activity sheets in the trash.
+func (impl ActivitySheetStorerImpl) ListByAssociateIDWithDeleted(ctx context.Context, associateID primitive.ObjectID) (*ActivitySheetPaginationListResult, error) {
+	f := &ActivitySheetPaginationListFilter{
+		Cursor:         "",
+		PageSize:       1_000_00,
+		SortField:      "", // Setting this empty to ignore any sorting.
+		SortOrder:      SortOrderAscending,
+		AssociateID:    associateID,
+		IncludeDeleted: true,
+	}
+	res, err := impl.ListByFilter(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,370 @@
+package address
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	CountryCanada = "Canada"
+
+	ProblemInvalidPostalCode = "invalid postal code"
+	ProblemMissingPostalCode = "missing postal code"
+	ProblemUnknownProvince   = "unknown province"
+	ProblemProvinceMismatch  = "postal code does not match province"
+	ProblemMissingStreet     = "missing street address"
+	ProblemMissingCity       = "missing city"
+	googleMapsPlaceURLPrefix = "https://www.google.com/maps/place/"
+)
+
+var ErrInvalidPostalCode = errors.New("invalid postal code")
+
+// provinceCodes maps the English and French names of the provinces and
+// territories, along with their common abbreviations, to their two letter
+// Canada Post code.
+var provinceCodes = map[string]string{
+	"ab": "AB", "alberta": "AB", "alta": "AB",
+	"bc": "BC", "british columbia": "BC", "colombie britannique": "BC",
+	"mb": "MB", "manitoba": "MB", "man": "MB",
+	"nb": "NB", "new brunswick": "NB", "nouveau brunswick": "NB",
+	"nl": "NL", "newfoundland and labrador": "NL", "newfoundland": "NL", "terre neuve et labrador": "NL", "nfld": "NL", "nf": "NL",
+	"ns": "NS", "nova scotia": "NS", "nouvelle ecosse": "NS", "nouvelle écosse": "NS",
+	"nt": "NT", "northwest territories": "NT", "territoires du nord ouest": "NT", "nwt": "NT",
+	"nu": "NU", "nunavut": "NU",
+	"on": "ON", "ontario": "ON", "ont": "ON",
+	"pe": "PE", "prince edward island": "PE", "ile du prince edouard": "PE", "île du prince édouard": "PE", "pei": "PE",
+	"qc": "QC", "quebec": "QC", "québec": "QC", "que": "QC", "pq": "QC",
+	"sk": "SK", "saskatchewan": "SK", "sask": "SK",
+	"yt": "YT", "yukon": "YT", "yukon territory": "YT", "yk": "YT",
+}
+
+// postalDistricts maps the first letter of a postal code to the provinces
+// and territories it is used in.
+var postalDistricts = map[byte][]string{
+	'A': {"NL"},
+	'B': {"NS"},
+	'C': {"PE"},
+	'E': {"NB"},
+	'G': {"QC"}, 'H': {"QC"}, 'J': {"QC"},
+	'K': {"ON"}, 'L': {"ON"}, 'M': {"ON"}, 'N': {"ON"}, 'P': {"ON"},
+	'R': {"MB"},
+	'S': {"SK"},
+	'T': {"AB"},
+	'V': {"BC"},
+	'X': {"NT", "NU"},
+	'Y': {"YT"},
+}
+
+// streetTypes maps the street types to their Canada Post abbreviation.
+var streetTypes = map[string]string{
+	"avenue": "Ave", "av": "Ave",
+	"boulevard": "Blvd", "blvd.": "Blvd",
+	"circle": "Cir",
+	"court":  "Crt", "ct": "Crt",
+	"crescent": "Cres",
+	"drive":    "Dr",
+	"highway":  "Hwy",
+	"lane":     "Lane",
+	"parkway":  "Pky",
+	"place":    "Pl",
+	"road":     "Rd",
+	"square":   "Sq",
+	"street":   "St",
+	"terrace":  "Terr",
+}
+
+// directions maps the directions which may follow the street type to their
+// Canada Post abbreviation.
+var directions = map[string]string{
+	"north": "N", "south": "S", "east": "E", "west": "W",
+}
+
+var (
+	postalCodePattern = regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY][0-9][ABCEGHJ-NPRSTV-Z][0-9][ABCEGHJ-NPRSTV-Z][0-9]$`)
+
+	unitDesignator = `(?:unit|apt\.?|apartment|suite|ste\.?|#)`
+	leadingUnit    = regexp.MustCompile(`(?i)^` + unitDesignator + `\s*#?\s*([0-9a-z]+)\s*[,\s-]\s*(.+)$`)
+	trailingUnit   = regexp.MustCompile(`(?i)^(.+?)[,\s]+` + unitDesignator + `\s*#?\s*([0-9a-z]+)$`)
+	dashedUnit     = regexp.MustCompile(`(?i)^([0-9a-z]+)\s*-\s*([0-9]+[a-z]?\s+.+)$`)
+	onlyUnit       = regexp.MustCompile(`(?i)^` + unitDesignator + `\s*#?\s*([0-9a-z]+)$`)
+	poBox          = regexp.MustCompile(`(?i)^(?:p\.?\s*o\.?\s*box|post office box|box|c\.?\s*p\.?|case postale)\s*#?\s*([0-9]+)$`)
+)
+
+// Address is a mailing address split into the parts Canada Post formats
+// addresses with.
+type Address struct {
+	Street     string // Civic number and street name, without the unit.
+	Unit       string
+	Line2      string
+	POBox      string
+	City       string
+	Region     string // Two letter province or territory code when known.
+	PostalCode string
+	Country    string
+
+	// Problems lists what is wrong or missing in the address.
+	Problems []string
+}
+
+// Parse normalizes the address fields as they are stored on customers,
+// associates and staff: the unit is split out of the street lines, the post
+// office box is reduced to its number, the province is replaced by its code
+// and Canadian postal codes are formatted as `A1A 1A1`.
+func Parse(line1, line2, poBoxNumber, city, region, postalCode, country string) *Address {
+	a := &Address{
+		City:    cleanCity(city),
+		Country: NormalizeCountry(country),
+	}
+
+	line1, line2 = clean(line1), clean(line2)
+	if n := matchPOBox(poBoxNumber); n != "" {
+		a.POBox = n
+	} else {
+		a.POBox = clean(poBoxNumber)
+	}
+	if n := matchPOBox(line1); n != "" {
+		if a.POBox == "" {
+			a.POBox = n
+		}
+		line1 = ""
+	}
+	if n := matchPOBox(line2); n != "" {
+		if a.POBox == "" {
+			a.POBox = n
+		}
+		line2 = ""
+	}
+	if line1 == "" {
+		line1, line2 = line2, ""
+	}
+
+	a.Street, a.Unit = splitUnit(line1)
+	if m := onlyUnit.FindStringSubmatch(line2); m != nil && a.Unit == "" {
+		a.Unit = strings.ToUpper(m[1])
+		line2 = ""
+	}
+	a.Line2 = line2
+	a.Street = abbreviateStreetType(a.Street)
+
+	if code, ok := ProvinceCode(region); ok {
+		a.Region = code
+	} else {
+		a.Region = clean(region)
+	}
+
+	pc, err := NormalizePostalCode(postalCode)
+	isCanada := a.Country == CountryCanada || a.Country == "" && err == nil
+	if isCanada && a.Country == "" {
+		a.Country = CountryCanada
+	}
+	switch {
+	case !isCanada:
+		a.PostalCode = clean(postalCode)
+	case err == nil:
+		a.PostalCode = pc
+	default:
+		a.PostalCode = clean(postalCode)
+		if a.PostalCode == "" {
+			a.Problems = append(a.Problems, ProblemMissingPostalCode)
+		} else {
+			a.Problems = append(a.Problems, ProblemInvalidPostalCode)
+		}
+	}
+
+	if isCanada {
+		if _, ok := ProvinceCode(a.Region); !ok {
+			a.Problems = append(a.Problems, ProblemUnknownProvince)
+		} else if err == nil && !inDistrict(a.PostalCode, a.Region) {
+			a.Problems = append(a.Problems, ProblemProvinceMismatch)
+		}
+	}
+	if a.Street == "" && a.POBox == "" {
+		a.Problems = append(a.Problems, ProblemMissingStreet)
+	}
+	if a.City == "" {
+		a.Problems = append(a.Problems, ProblemMissingCity)
+	}
+	return a
+}
+
+// StreetLine returns the civic address the way Canada Post writes it, ex:
+// `5-123 Main St` for unit 5.
+func (a *Address) StreetLine() string {
+	if a.Unit != "" && a.Street != "" {
+		return a.Unit + "-" + a.Street
+	}
+	return a.Street
+}
+
+// Line returns the street lines of the address, or `PO Box 42` when there is
+// no street.
+func (a *Address) Line() string {
+	line := a.StreetLine()
+	if a.Line2 != "" {
+		line = strings.TrimSpace(line + " " + a.Line2)
+	}
+	if line == "" && a.POBox != "" {
+		line = "PO Box " + a.POBox
+	}
+	return line
+}
+
+// WithoutPostalCode returns the one line address without the postal code,
+// the format the import commands compiled into `FullAddressWithoutPostalCode`.
+func (a *Address) WithoutPostalCode() string {
+	parts := make([]string, 0, 4)
+	for _, p := range []string{a.Line(), a.City, a.Region, a.Country} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WithPostalCode returns the one line address with the postal code, or `-`
+// when there is no postal code as the import commands did.
+func (a *Address) WithPostalCode() string {
+	if a.PostalCode == "" {
+		return "-"
+	}
+	return a.WithoutPostalCode() + ", " + a.PostalCode
+}
+
+// URL returns the Google Maps link of the address.
+func (a *Address) URL() string {
+	place := a.WithoutPostalCode()
+	if a.PostalCode != "" {
+		place = place + ", " + a.PostalCode
+	}
+	return googleMapsPlaceURLPrefix + url.PathEscape(place)
+}
+
+// NormalizePostalCode formats a Canadian postal code as `A1A 1A1`.
+func NormalizePostalCode(s string) (string, error) {
+	s = strings.ToUpper(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' {
+			return -1
+		}
+		return r
+	}, s))
+	if !postalCodePattern.MatchString(s) {
+		return "", ErrInvalidPostalCode
+	}
+	return s[:3] + " " + s[3:], nil
+}
+
+// ProvinceCode returns the two letter code of the Canadian province or
+// territory, given either its name in English or French, or its code.
+func ProvinceCode(s string) (string, bool) {
+	key := strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}), " ")
+	code, ok := provinceCodes[key]
+	return code, ok
+}
+
+// NormalizeCountry replaces the ways Canada is written with its name and
+// tidies the spacing of any other country.
+func NormalizeCountry(s string) string {
+	switch strings.ToLower(strings.Trim(clean(s), ".")) {
+	case "ca", "can", "canada":
+		return CountryCanada
+	}
+	return clean(s)
+}
+
+func inDistrict(postalCode string, region string) bool {
+	for _, code := range postalDistricts[postalCode[0]] {
+		if code == region {
+			return true
+		}
+	}
+	return false
+}
+
+func splitUnit(line string) (string, string) {
+	if m := leadingUnit.FindStringSubmatch(line); m != nil {
+		return m[2], strings.ToUpper(m[1])
+	}
+	if m := trailingUnit.FindStringSubmatch(line); m != nil {
+		return m[1], strings.ToUpper(m[2])
+	}
+	if m := dashedUnit.FindStringSubmatch(line); m != nil {
+		return m[2], strings.ToUpper(m[1])
+	}
+	return line, ""
+}
+
+func matchPOBox(s string) string {
+	if m := poBox.FindStringSubmatch(clean(s)); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// abbreviateStreetType abbreviates the street type and the direction which
+// may follow it, ex: `123 Main Street North` becomes `123 Main St N`. Only
+// the last word, or the word before a trailing direction, is taken as the
+// street type so names such as `West Street` and `Avenue Road` are kept.
+func abbreviateStreetType(street string) string {
+	words := strings.Fields(street)
+	// A word matches either the full name or the abbreviation itself.
+	lookup := func(m map[string]string, i int) (string, bool) {
+		w := strings.ToLower(strings.TrimSuffix(words[i], "."))
+		if abbr, ok := m[w]; ok {
+			return abbr, true
+		}
+		for _, abbr := range m {
+			if strings.ToLower(abbr) == w {
+				return abbr, true
+			}
+		}
+		return "", false
+	}
+
+	last := len(words) - 1
+	if last >= 2 {
+		if dir, ok := lookup(directions, last); ok {
+			if abbr, ok := lookup(streetTypes, last-1); ok {
+				words[last-1], words[last] = abbr, dir
+				return strings.Join(words, " ")
+			}
+		}
+	}
+	if last >= 1 {
+		if abbr, ok := lookup(streetTypes, last); ok {
+			words[last] = abbr
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// cleanCity tidies the spacing of the city and title cases it when it was
+// typed all in upper or lower case.
+func cleanCity(s string) string {
+	s = clean(s)
+	if s != strings.ToUpper(s) && s != strings.ToLower(s) {
+		return s
+	}
+	words := strings.Fields(strings.ToLower(s))
+	for i, w := range words {
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+	return strings.Join(words, " ")
+}
+
+// clean collapses the whitespace and drops the trailing punctuation and the
+// `-` the legacy system used for blank values.
+func clean(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = strings.TrimRight(s, ",;")
+	if s == "-" {
+		return ""
+	}
+	return s
+}
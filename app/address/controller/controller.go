@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	resync_c "github.com/over55/workery-cli/app/resync/controller"
+	c "github.com/over55/workery-cli/config"
+	"github.com/over55/workery-cli/provider/geocoder"
+)
+
+const (
+	KindCustomer  = "customer"
+	KindAssociate = "associate"
+)
+
+// GeocodeOptions selects which records are normalized and geocoded.
+type GeocodeOptions struct {
+	Customers  bool
+	Associates bool
+	// Refresh geocodes the records which already have coordinates again.
+	Refresh bool
+	// Normalize rewrites the stored address fields in their normalized
+	// form, otherwise only the coordinates are filled in.
+	Normalize bool
+	DryRun    bool
+}
+
+// Change is a record whose address fields were (or, on a dry run, would be)
+// updated.
+type Change struct {
+	Kind      string             `json:"kind"`
+	ID        primitive.ObjectID `json:"id"`
+	PublicID  uint64             `json:"public_id"`
+	Name      string             `json:"name"`
+	Fields    []string           `json:"fields"`
+	Latitude  float64            `json:"latitude,omitempty"`
+	Longitude float64            `json:"longitude,omitempty"`
+	Accuracy  string             `json:"accuracy,omitempty"`
+}
+
+// Unresolved is a record whose address could not be geocoded.
+type Unresolved struct {
+	Kind     string             `json:"kind"`
+	ID       primitive.ObjectID `json:"id"`
+	PublicID uint64             `json:"public_id"`
+	Name     string             `json:"name"`
+	Address  string             `json:"address"`
+	Problems []string           `json:"problems"`
+}
+
+// GeocodeReport summarizes a geocode run.
+type GeocodeReport struct {
+	DryRun          bool          `json:"dry_run"`
+	Checked         int           `json:"checked"`
+	Geocoded        int           `json:"geocoded"`
+	RefreshedCopies int           `json:"refreshed_copies"`
+	Changes         []*Change     `json:"changes"`
+	Unresolved      []*Unresolved `json:"unresolved"`
+}
+
+// AddressController Interface for normalizing the addresses of customers and
+// associates and filling in their coordinates.
+type AddressController interface {
+	Geocode(ctx context.Context, opts *GeocodeOptions) (*GeocodeReport, error)
+}
+
+type AddressControllerImpl struct {
+	Config          *c.Conf
+	Logger          *slog.Logger
+	Geocoder        geocoder.Provider
+	CustomerStorer  c_ds.CustomerStorer
+	AssociateStorer a_ds.AssociateStorer
+	Resync          resync_c.ResyncController
+}
+
+func NewController(
+	appCfg *c.Conf,
+	loggerp *slog.Logger,
+	geo geocoder.Provider,
+	cStorer c_ds.CustomerStorer,
+	aStorer a_ds.AssociateStorer,
+	resync resync_c.ResyncController,
+) AddressController {
+	s := &AddressControllerImpl{
+		Config:          appCfg,
+		Logger:          loggerp,
+		Geocoder:        geo,
+		CustomerStorer:  cStorer,
+		AssociateStorer: aStorer,
+		Resync:          resync,
+	}
+	return s
+}
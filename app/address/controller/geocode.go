@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/over55/workery-cli/app/address"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	"github.com/over55/workery-cli/provider/geocoder"
+)
+
+// addressFields points at the address fields of a customer or associate so
+// both are normalized the same way.
+type addressFields struct {
+	line1, line2, poBox, city, region, postalCode, country *string
+	withoutPostalCode, withPostalCode, url                 *string
+	latitude, longitude                                    *float64
+}
+
+// Geocode fills in the coordinates of the active customers and associates
+// which have none and reports the addresses which could not be resolved.
+// With `Normalize` their addresses are also rewritten in normalized form
+// and their full address fields recompiled, and the copies of the changed
+// addresses held by orders and task items are refreshed.
+func (impl *AddressControllerImpl) Geocode(ctx context.Context, opts *GeocodeOptions) (*GeocodeReport, error) {
+	report := &GeocodeReport{
+		DryRun:     opts.DryRun,
+		Changes:    []*Change{},
+		Unresolved: []*Unresolved{},
+	}
+
+	if opts.Customers {
+		res, err := impl.CustomerStorer.ListByFilter(ctx, &c_ds.CustomerPaginationListFilter{
+			Cursor:    "",
+			PageSize:  1_000_000,
+			SortField: "",
+			Status:    c_ds.CustomerStatusActive,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, cu := range res.Results {
+			change, err := impl.apply(ctx, report, opts, KindCustomer, cu.ID, cu.PublicID, cu.Name, &addressFields{
+				line1: &cu.AddressLine1, line2: &cu.AddressLine2, poBox: &cu.PostOfficeBoxNumber,
+				city: &cu.City, region: &cu.Region, postalCode: &cu.PostalCode, country: &cu.Country,
+				withoutPostalCode: &cu.FullAddressWithoutPostalCode, withPostalCode: &cu.FullAddressWithPostalCode, url: &cu.FullAddressURL,
+				latitude: &cu.Latitude, longitude: &cu.Longitude,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if change == nil || opts.DryRun {
+				continue
+			}
+			if err := impl.CustomerStorer.UpdateByID(ctx, cu); err != nil {
+				return nil, err
+			}
+			if hasAddressChange(change) {
+				resync, err := impl.Resync.ResyncCustomer(ctx, cu.ID, false)
+				if err != nil {
+					return nil, err
+				}
+				report.RefreshedCopies += len(resync.Drifts)
+			}
+		}
+	}
+
+	if opts.Associates {
+		res, err := impl.AssociateStorer.ListByFilter(ctx, &a_ds.AssociatePaginationListFilter{
+			Cursor:    "",
+			PageSize:  1_000_000,
+			SortField: "",
+			Status:    a_ds.AssociateStatusActive,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range res.Results {
+			change, err := impl.apply(ctx, report, opts, KindAssociate, a.ID, a.PublicID, a.Name, &addressFields{
+				line1: &a.AddressLine1, line2: &a.AddressLine2, poBox: &a.PostOfficeBoxNumber,
+				city: &a.City, region: &a.Region, postalCode: &a.PostalCode, country: &a.Country,
+				withoutPostalCode: &a.FullAddressWithoutPostalCode, withPostalCode: &a.FullAddressWithPostalCode, url: &a.FullAddressURL,
+				latitude: &a.Latitude, longitude: &a.Longitude,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if change == nil || opts.DryRun {
+				continue
+			}
+			if err := impl.AssociateStorer.UpdateByID(ctx, a); err != nil {
+				return nil, err
+			}
+			if hasAddressChange(change) {
+				resync, err := impl.Resync.ResyncAssociate(ctx, a.ID, false)
+				if err != nil {
+					return nil, err
+				}
+				report.RefreshedCopies += len(resync.Drifts)
+			}
+		}
+	}
+	return report, nil
+}
+
+// apply geocodes, and when asked normalizes, the address in place and
+// returns what changed, or nil when nothing did. The normalized address is
+// what is geocoded either way.
+func (impl *AddressControllerImpl) apply(ctx context.Context, report *GeocodeReport, opts *GeocodeOptions, kind string, id primitive.ObjectID, publicID uint64, name string, f *addressFields) (*Change, error) {
+	report.Checked++
+	addr := address.Parse(*f.line1, *f.line2, *f.poBox, *f.city, *f.region, *f.postalCode, *f.country)
+
+	change := &Change{Kind: kind, ID: id, PublicID: publicID, Name: name, Fields: []string{}}
+	set := func(field string, p *string, v string) {
+		if *p != v {
+			*p = v
+			change.Fields = append(change.Fields, field)
+		}
+	}
+	if opts.Normalize {
+		set("address_line1", f.line1, addr.StreetLine())
+		set("address_line2", f.line2, addr.Line2)
+		set("post_office_box_number", f.poBox, addr.POBox)
+		set("city", f.city, addr.City)
+		set("region", f.region, addr.Region)
+		set("postal_code", f.postalCode, addr.PostalCode)
+		set("country", f.country, addr.Country)
+		set("full_address_without_postal_code", f.withoutPostalCode, addr.WithoutPostalCode())
+		set("full_address_with_postal_code", f.withPostalCode, addr.WithPostalCode())
+		set("full_address_url", f.url, addr.URL())
+	}
+
+	hasCoordinates := *f.latitude != 0 || *f.longitude != 0
+	if !hasCoordinates || opts.Refresh {
+		res, err := impl.Geocoder.Geocode(ctx, &geocoder.Query{
+			Line:       addr.Line(),
+			City:       addr.City,
+			Region:     addr.Region,
+			PostalCode: addr.PostalCode,
+			Country:    addr.Country,
+		})
+		switch {
+		case errors.Is(err, geocoder.ErrNotFound):
+			problems := addr.Problems
+			if len(problems) == 0 {
+				problems = []string{geocoder.ErrNotFound.Error()}
+			}
+			report.Unresolved = append(report.Unresolved, &Unresolved{
+				Kind:     kind,
+				ID:       id,
+				PublicID: publicID,
+				Name:     name,
+				Address:  addr.WithPostalCode(),
+				Problems: problems,
+			})
+		case err != nil:
+			return nil, err
+		default:
+			report.Geocoded++
+			change.Accuracy = res.Accuracy
+			if *f.latitude != res.Latitude || *f.longitude != res.Longitude {
+				*f.latitude, *f.longitude = res.Latitude, res.Longitude
+				change.Fields = append(change.Fields, "latitude", "longitude")
+			}
+		}
+	}
+	change.Latitude, change.Longitude = *f.latitude, *f.longitude
+
+	if len(change.Fields) == 0 {
+		return nil, nil
+	}
+	report.Changes = append(report.Changes, change)
+	return change, nil
+}
+
+// hasAddressChange returns true when the change touched a field which is
+// copied into other records, ex: the full address held by orders.
+func hasAddressChange(change *Change) bool {
+	for _, field := range change.Fields {
+		if field != "latitude" && field != "longitude" {
+			return true
+		}
+	}
+	return false
+}
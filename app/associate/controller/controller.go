@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	as_ds "github.com/over55/workery-cli/app/activitysheet/datastore"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+	att_ds "github.com/over55/workery-cli/app/attachment/datastore"
+	com_ds "github.com/over55/workery-cli/app/comment/datastore"
+	"github.com/over55/workery-cli/app/dedupe"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	resync_c "github.com/over55/workery-cli/app/resync/controller"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	c "github.com/over55/workery-cli/config"
+)
+
+var (
+	ErrAssociateNotFound = errors.New("associate does not exist")
+	ErrSameAssociate     = errors.New("cannot merge an associate into itself")
+	ErrAlreadyArchived   = errors.New("associate to drop is already archived")
+	ErrKeepArchived      = errors.New("associate to keep is archived")
+)
+
+// MergeReport counts what was (or, on a dry run, would be) moved from the
+// dropped associate onto the kept one.
+type MergeReport struct {
+	DryRun                bool               `json:"dry_run"`
+	KeepID                primitive.ObjectID `json:"keep_id"`
+	KeepPublicID          uint64             `json:"keep_public_id"`
+	DropID                primitive.ObjectID `json:"drop_id"`
+	DropPublicID          uint64             `json:"drop_public_id"`
+	Orders                int                `json:"orders"`
+	TaskItems             int                `json:"task_items"`
+	ActivitySheets        int                `json:"activity_sheets"`
+	AwayLogs              int                `json:"away_logs"`
+	Comments              int                `json:"comments"`
+	Attachments           int                `json:"attachments"`
+	SkillSets             int                `json:"skill_sets"`
+	InsuranceRequirements int                `json:"insurance_requirements"`
+	VehicleTypes          int                `json:"vehicle_types"`
+	Tags                  int                `json:"tags"`
+	FilledFields          []string           `json:"filled_fields"`
+	RelinkedUserID        primitive.ObjectID `json:"relinked_user_id,omitempty"`
+	ArchivedUserID        primitive.ObjectID `json:"archived_user_id,omitempty"`
+	RefreshedCopies       int                `json:"refreshed_copies"`
+}
+
+// AssociateController Interface for finding and merging duplicate associates.
+type AssociateController interface {
+	FindDuplicates(ctx context.Context, threshold float64) ([]*dedupe.Pair, error)
+	Merge(ctx context.Context, keepRef string, dropRef string, dryRun bool) (*MergeReport, error)
+}
+
+type AssociateControllerImpl struct {
+	Config                 *c.Conf
+	Logger                 *slog.Logger
+	DbClient               *mongo.Client
+	AssociateStorer        a_ds.AssociateStorer
+	OrderStorer            o_ds.OrderStorer
+	TaskItemStorer         ti_ds.TaskItemStorer
+	ActivitySheetStorer    as_ds.ActivitySheetStorer
+	AssociateAwayLogStorer aal_ds.AssociateAwayLogStorer
+	CommentStorer          com_ds.CommentStorer
+	AttachmentStorer       att_ds.AttachmentStorer
+	UserStorer             user_ds.UserStorer
+	Resync                 resync_c.ResyncController
+}
+
+func NewController(
+	appCfg *c.Conf,
+	loggerp *slog.Logger,
+	client *mongo.Client,
+	aStorer a_ds.AssociateStorer,
+	oStorer o_ds.OrderStorer,
+	tiStorer ti_ds.TaskItemStorer,
+	asStorer as_ds.ActivitySheetStorer,
+	aalStorer aal_ds.AssociateAwayLogStorer,
+	comStorer com_ds.CommentStorer,
+	attStorer att_ds.AttachmentStorer,
+	uStorer user_ds.UserStorer,
+	resync resync_c.ResyncController,
+) AssociateController {
+	s := &AssociateControllerImpl{
+		Config:                 appCfg,
+		Logger:                 loggerp,
+		DbClient:               client,
+		AssociateStorer:        aStorer,
+		OrderStorer:            oStorer,
+		TaskItemStorer:         tiStorer,
+		ActivitySheetStorer:    asStorer,
+		AssociateAwayLogStorer: aalStorer,
+		CommentStorer:          comStorer,
+		AttachmentStorer:       attStorer,
+		UserStorer:             uStorer,
+		Resync:                 resync,
+	}
+	return s
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"context"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	"github.com/over55/workery-cli/app/dedupe"
+)
+
+// FindDuplicates returns the pairs of active associates which are likely the
+// same person, best first.
+func (impl *AssociateControllerImpl) FindDuplicates(ctx context.Context, threshold float64) ([]*dedupe.Pair, error) {
+	res, err := impl.AssociateStorer.ListByFilter(ctx, &a_ds.AssociatePaginationListFilter{
+		Cursor:    "",
+		PageSize:  1_000_000,
+		SortField: "",
+		Status:    a_ds.AssociateStatusActive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*dedupe.Record, 0, len(res.Results))
+	for _, a := range res.Results {
+		records = append(records, toRecord(a))
+	}
+	return dedupe.Find(records, threshold), nil
+}
+
+func toRecord(a *a_ds.Associate) *dedupe.Record {
+	return &dedupe.Record{
+		ID:           a.ID,
+		PublicID:     a.PublicID,
+		Name:         a.Name,
+		FirstName:    a.FirstName,
+		LastName:     a.LastName,
+		Emails:       []string{a.Email, a.PersonalEmail},
+		Phones:       []string{a.Phone, a.OtherPhone},
+		AddressLine1: a.AddressLine1,
+		PostalCode:   a.PostalCode,
+		CreatedAt:    a.CreatedAt,
+	}
+}
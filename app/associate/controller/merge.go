@@ -0,0 +1,369 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+	att_ds "github.com/over55/workery-cli/app/attachment/datastore"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+)
+
+// Merge moves every order, task item, activity sheet, away log, comment and
+// attachment of the `drop` associate onto the `keep` associate, unions their
+// skill sets, insurance requirements, vehicle types and tags, keeps the
+// latest compliance dates, relinks the login account and archives the
+// dropped record. Either reference may be the hex ID or the public ID of the
+// associate. Documents in the trash are moved as well. The changes are made in
+// one transaction so a failed merge leaves both associates as they were.
+func (impl *AssociateControllerImpl) Merge(ctx context.Context, keepRef string, dropRef string, dryRun bool) (*MergeReport, error) {
+	if dryRun {
+		return impl.merge(ctx, keepRef, dropRef, true)
+	}
+
+	session, err := impl.DbClient.StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	// The associates are looked up inside the transaction function as it is
+	// run again if the transaction is retried.
+	res, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return impl.merge(sessCtx, keepRef, dropRef, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*MergeReport), nil
+}
+
+func (impl *AssociateControllerImpl) merge(ctx context.Context, keepRef string, dropRef string, dryRun bool) (*MergeReport, error) {
+	keep, err := impl.get(ctx, keepRef)
+	if err != nil {
+		return nil, err
+	}
+	drop, err := impl.get(ctx, dropRef)
+	if err != nil {
+		return nil, err
+	}
+	if keep.ID == drop.ID {
+		return nil, ErrSameAssociate
+	}
+	if drop.Status == a_ds.AssociateStatusArchived {
+		return nil, ErrAlreadyArchived
+	}
+	if keep.Status == a_ds.AssociateStatusArchived {
+		return nil, ErrKeepArchived
+	}
+
+	report := &MergeReport{
+		DryRun:       dryRun,
+		KeepID:       keep.ID,
+		KeepPublicID: keep.PublicID,
+		DropID:       drop.ID,
+		DropPublicID: drop.PublicID,
+		FilledFields: []string{},
+	}
+
+	if err := impl.repoint(ctx, report, keep, drop, dryRun); err != nil {
+		return nil, err
+	}
+
+	keep.SkillSets, report.SkillSets = mergeByID(keep.SkillSets, drop.SkillSets,
+		func(ss *a_ds.AssociateSkillSet) primitive.ObjectID { return ss.ID })
+	keep.InsuranceRequirements, report.InsuranceRequirements = mergeByID(keep.InsuranceRequirements, drop.InsuranceRequirements,
+		func(ir *a_ds.AssociateInsuranceRequirement) primitive.ObjectID { return ir.ID })
+	keep.VehicleTypes, report.VehicleTypes = mergeByID(keep.VehicleTypes, drop.VehicleTypes,
+		func(vt *a_ds.AssociateVehicleType) primitive.ObjectID { return vt.ID })
+	keep.Tags, report.Tags = mergeByID(keep.Tags, drop.Tags,
+		func(t *a_ds.AssociateTag) primitive.ObjectID { return t.ID })
+	keep.Comments = append(keep.Comments, drop.Comments...)
+	keep.AwayLogs = append(keep.AwayLogs, drop.AwayLogs...)
+	for _, aal := range keep.AwayLogs {
+		aal.AssociateID = keep.ID
+		aal.AssociateName = keep.Name
+		aal.AssociateLexicalName = keep.LexicalName
+	}
+	report.FilledFields = append(report.FilledFields, mergeComplianceDates(keep, drop)...)
+	report.FilledFields = append(report.FilledFields, mergeContactDetails(keep, drop)...)
+
+	// Only one login may reference the kept associate; if it has none then
+	// the dropped associate's login is relinked, otherwise it is archived.
+	if !drop.UserID.IsZero() {
+		u, err := impl.UserStorer.GetByID(ctx, drop.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if u != nil {
+			if keep.UserID.IsZero() {
+				report.RelinkedUserID = u.ID
+				u.ReferenceID = keep.ID
+				keep.UserID = u.ID
+			} else {
+				report.ArchivedUserID = u.ID
+				u.Status = user_ds.UserStatusArchived
+			}
+			if !dryRun {
+				if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	userID, userName, ipAddress := auditlog_ds.GetActor(ctx)
+	now := time.Now()
+
+	keep.ModifiedAt = now
+	keep.ModifiedByUserID = userID
+	keep.ModifiedByUserName = userName
+	keep.ModifiedFromIPAddress = ipAddress
+	if err := impl.AssociateStorer.UpdateByID(ctx, keep); err != nil {
+		return nil, err
+	}
+
+	drop.Status = a_ds.AssociateStatusArchived
+	drop.DeactivationReason = a_ds.AssociateDeactivationReasonOther
+	drop.DeactivationReasonOther = fmt.Sprintf("Merged into associate %d", keep.PublicID)
+	drop.UserID = primitive.NilObjectID
+	drop.Tags = []*a_ds.AssociateTag{}
+	drop.AwayLogs = []*a_ds.AssociateAwayLog{}
+	drop.ModifiedAt = now
+	drop.ModifiedByUserID = userID
+	drop.ModifiedByUserName = userName
+	drop.ModifiedFromIPAddress = ipAddress
+	if err := impl.AssociateStorer.UpdateByID(ctx, drop); err != nil {
+		return nil, err
+	}
+
+	// The repointed orders, task items, activity sheets and comments still
+	// hold the dropped associate's name, contact details and skill sets, so
+	// refresh their copies.
+	resync, err := impl.Resync.ResyncAssociate(ctx, keep.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	report.RefreshedCopies = len(resync.Drifts)
+
+	impl.Logger.Debug("merged associate",
+		slog.String("keep_id", keep.ID.Hex()),
+		slog.String("drop_id", drop.ID.Hex()))
+	return report, nil
+}
+
+// repoint moves every document which references the dropped associate onto
+// the kept associate, including the ones in the trash so restoring one does
+// not bring back a reference to the archived associate.
+func (impl *AssociateControllerImpl) repoint(ctx context.Context, report *MergeReport, keep *a_ds.Associate, drop *a_ds.Associate, dryRun bool) error {
+	orders, err := impl.OrderStorer.ListByAssociateIDWithDeleted(ctx, drop.ID)
+	if err != nil {
+		return err
+	}
+	for _, o := range orders.Results {
+		report.Orders++
+		if dryRun {
+			continue
+		}
+		o.AssociateID = keep.ID
+		if err := impl.OrderStorer.UpdateByID(ctx, o); err != nil {
+			return err
+		}
+	}
+
+	taskItems, err := impl.TaskItemStorer.ListByAssociateIDWithDeleted(ctx, drop.ID)
+	if err != nil {
+		return err
+	}
+	for _, ti := range taskItems.Results {
+		report.TaskItems++
+		if dryRun {
+			continue
+		}
+		ti.AssociateID = keep.ID
+		if err := impl.TaskItemStorer.UpdateByID(ctx, ti); err != nil {
+			return err
+		}
+	}
+
+	sheets, err := impl.ActivitySheetStorer.ListByAssociateIDWithDeleted(ctx, drop.ID)
+	if err != nil {
+		return err
+	}
+	for _, as := range sheets.Results {
+		report.ActivitySheets++
+		if dryRun {
+			continue
+		}
+		as.AssociateID = keep.ID
+		if err := impl.ActivitySheetStorer.UpdateByID(ctx, as); err != nil {
+			return err
+		}
+	}
+
+	awayLogs, err := impl.AssociateAwayLogStorer.ListByFilter(ctx, &aal_ds.AssociateAwayLogPaginationListFilter{
+		PageSize:       1_000_000,
+		SortField:      "", // Forget sorting, we don't need it here.
+		SortOrder:      1,
+		AssociateID:    drop.ID,
+		IncludeDeleted: true,
+	})
+	if err != nil {
+		return err
+	}
+	for _, aal := range awayLogs.Results {
+		report.AwayLogs++
+		if dryRun {
+			continue
+		}
+		aal.AssociateID = keep.ID
+		aal.AssociateName = keep.Name
+		aal.AssociateLexicalName = keep.LexicalName
+		if err := impl.AssociateAwayLogStorer.UpdateByID(ctx, aal); err != nil {
+			return err
+		}
+	}
+
+	comments, err := impl.CommentStorer.ListByAssociateIDWithDeleted(ctx, drop.ID)
+	if err != nil {
+		return err
+	}
+	for _, com := range comments.Results {
+		report.Comments++
+		if dryRun {
+			continue
+		}
+		com.AssociateID = keep.ID
+		if err := impl.CommentStorer.UpdateByID(ctx, com); err != nil {
+			return err
+		}
+	}
+
+	attachments, err := impl.AttachmentStorer.ListByFilter(ctx, &att_ds.AttachmentListFilter{
+		PageSize:       1_000_000,
+		SortField:      "id",
+		SortOrder:      att_ds.OrderAscending,
+		AssociateID:    drop.ID,
+		IncludeDeleted: true,
+	})
+	if err != nil {
+		return err
+	}
+	for _, att := range attachments.Results {
+		report.Attachments++
+		if dryRun {
+			continue
+		}
+		att.AssociateID = keep.ID
+		att.AssociateName = keep.Name
+		if err := impl.AttachmentStorer.UpdateByID(ctx, att); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeByID appends the items of `drop` whose ID is not already in `keep`
+// and returns the merged list along with how many were added.
+func mergeByID[T any](keep []T, drop []T, id func(T) primitive.ObjectID) ([]T, int) {
+	has := make(map[primitive.ObjectID]bool, len(keep))
+	for _, v := range keep {
+		has[id(v)] = true
+	}
+	added := 0
+	for _, v := range drop {
+		if !has[id(v)] {
+			has[id(v)] = true
+			keep = append(keep, v)
+			added++
+		}
+	}
+	return keep, added
+}
+
+// mergeComplianceDates keeps the latest of each compliance date, along with
+// the WSIB number which belongs to the WSIB date, and returns the fields
+// taken from the dropped associate.
+func mergeComplianceDates(keep *a_ds.Associate, drop *a_ds.Associate) []string {
+	fields := []string{}
+	latest := func(field string, k *time.Time, d time.Time) {
+		if d.After(*k) {
+			*k = d
+			fields = append(fields, field)
+		}
+	}
+	latest("dues_date", &keep.DuesDate, drop.DuesDate)
+	latest("commercial_insurance_expiry_date", &keep.CommercialInsuranceExpiryDate, drop.CommercialInsuranceExpiryDate)
+	latest("auto_insurance_expiry_date", &keep.AutoInsuranceExpiryDate, drop.AutoInsuranceExpiryDate)
+	latest("police_check", &keep.PoliceCheck, drop.PoliceCheck)
+	if drop.WsibInsuranceDate.After(keep.WsibInsuranceDate) {
+		keep.WsibInsuranceDate = drop.WsibInsuranceDate
+		fields = append(fields, "wsib_insurance_date")
+		if drop.WsibNumber != "" {
+			keep.WsibNumber = drop.WsibNumber
+			fields = append(fields, "wsib_number")
+		}
+	}
+	return fields
+}
+
+// mergeContactDetails fills in the contact details the kept associate is
+// missing and returns the fields taken from the dropped associate.
+func mergeContactDetails(keep *a_ds.Associate, drop *a_ds.Associate) []string {
+	fields := []string{}
+	if keep.Email == "" && drop.Email != "" {
+		keep.Email = drop.Email
+		fields = append(fields, "email")
+	}
+	if keep.PersonalEmail == "" && drop.PersonalEmail != "" {
+		keep.PersonalEmail = drop.PersonalEmail
+		fields = append(fields, "personal_email")
+	}
+	if keep.Phone == "" && drop.Phone != "" {
+		keep.Phone = drop.Phone
+		keep.PhoneType = drop.PhoneType
+		keep.PhoneExtension = drop.PhoneExtension
+		fields = append(fields, "phone")
+	}
+	if keep.OtherPhone == "" && drop.OtherPhone != "" {
+		keep.OtherPhone = drop.OtherPhone
+		keep.OtherPhoneType = drop.OtherPhoneType
+		keep.OtherPhoneExtension = drop.OtherPhoneExtension
+		fields = append(fields, "other_phone")
+	}
+	if keep.TaxID == "" && drop.TaxID != "" {
+		keep.TaxID = drop.TaxID
+		fields = append(fields, "tax_id")
+	}
+	return fields
+}
+
+// get looks up the associate by hex ID first and then by public ID.
+func (impl *AssociateControllerImpl) get(ctx context.Context, ref string) (*a_ds.Associate, error) {
+	var a *a_ds.Associate
+	var err error
+	if id, hexErr := primitive.ObjectIDFromHex(ref); hexErr == nil {
+		a, err = impl.AssociateStorer.GetByID(ctx, id)
+	} else if publicID, parseErr := strconv.ParseUint(ref, 10, 64); parseErr == nil {
+		a, err = impl.AssociateStorer.GetByPublicID(ctx, publicID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAssociateNotFound, ref)
+	}
+	return a, nil
+}
@@ -64,10 +64,13 @@ type AssociateCountFilter struct {
 	// AllVehicleTypeIDs filter is used if you want to find all tag ids for
 	// the associate.
 	AllVehicleTypeIDs []primitive.ObjectID
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 func (impl AssociateStorerImpl) CountByFilter(ctx context.Context, f *AssociateCountFilter) (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Create the filter based on the cursor
@@ -167,6 +170,11 @@ func (impl AssociateStorerImpl) CountByFilter(ctx context.Context, f *AssociateC
 	impl.Logger.Debug("counting w/ filter:",
 		slog.Any("filter", filter))
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Use the CountDocuments method to count the matching documents.
 	count, err := impl.Collection.CountDocuments(ctx, filter)
 	if err != nil {
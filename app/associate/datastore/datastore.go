@@ -10,6 +10,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 	c "github.com/over55/workery-cli/config"
 )
 
@@ -128,6 +130,9 @@ type Associate struct {
 	IdentifyAs                           []int8                           `bson:"identify_as" json:"identify_as,omitempty"`
 	// ServiceFee            *WorkOrderServiceFee             `json:"invoice_service_fee,omitempty"` // Referenced value from 'work_order_service_fee'.
 	// Tags                  []*AssociateTag                  `json:"tags,omitempty"`
+	DeletedAt         time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedByUserID   primitive.ObjectID `bson:"deleted_by_user_id,omitempty" json:"deleted_by_user_id,omitempty"`
+	DeletedByUserName string             `bson:"deleted_by_user_name,omitempty" json:"deleted_by_user_name,omitempty"`
 }
 
 // SkillSetIDs is a convinience function which will return an array of skill
@@ -229,6 +234,9 @@ type AssociateListFilter struct {
 	Phone           string
 	CreatedAtGTE    time.Time
 	SkillSetIDs     []primitive.ObjectID
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 type AssociateListResult struct {
@@ -256,17 +264,26 @@ type AssociateStorer interface {
 	ListByFilter(ctx context.Context, f *AssociatePaginationListFilter) (*AssociatePaginationListResult, error)
 	ListByInsuranceRequirementID(ctx context.Context, irID primitive.ObjectID) (*AssociatePaginationListResult, error)
 	ListByHowDidYouHearAboutUsID(ctx context.Context, howDidYouHearAboutUsID primitive.ObjectID) (*AssociatePaginationListResult, error)
+	ListByServiceFeeID(ctx context.Context, serviceFeeID primitive.ObjectID) (*AssociatePaginationListResult, error)
+	ListBySkillSetID(ctx context.Context, skillSetID primitive.ObjectID) (*AssociatePaginationListResult, error)
+	ListByTagID(ctx context.Context, tagID primitive.ObjectID) (*AssociatePaginationListResult, error)
 	ListAsSelectOptionByFilter(ctx context.Context, f *AssociateListFilter) ([]*AssociateAsSelectOption, error)
 	LiteListByFilter(ctx context.Context, f *AssociatePaginationListFilter) (*AssociatePaginationLiteListResult, error)
 	ListAll(ctx context.Context) (*AssociatePaginationListResult, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	PermanentlyDeleteByID(ctx context.Context, id primitive.ObjectID) error
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	ListDeletedBefore(ctx context.Context, deletedBefore time.Time) ([]*Associate, error)
 	CountByFilter(ctx context.Context, f *AssociateCountFilter) (int64, error)
+	ForTenant(tenantID primitive.ObjectID) AssociateStorer
+	AllTenants() AssociateStorer
 }
 
 type AssociateStorerImpl struct {
-	Logger     *slog.Logger
-	DbClient   *mongo.Client
-	Collection *mongo.Collection
+	Logger         *slog.Logger
+	DbClient       *mongo.Client
+	Collection     *mongodb.TenantCollection
+	AuditLogStorer auditlog_ds.AuditLogStorer
 }
 
 func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) AssociateStorer {
@@ -284,6 +301,7 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) As
 	// }
 
 	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
 		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
 		{Keys: bson.D{{Key: "email", Value: 1}}},
 		{Keys: bson.D{{Key: "last_name", Value: 1}}},
@@ -314,9 +332,10 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) As
 	}
 
 	s := &AssociateStorerImpl{
-		Logger:     loggerp,
-		DbClient:   client,
-		Collection: uc,
+		Logger:         loggerp,
+		DbClient:       client,
+		Collection:     mongodb.NewTenantCollection(uc),
+		AuditLogStorer: auditlog_ds.NewDatastore(appCfg, loggerp, client),
 	}
 	return s
 }
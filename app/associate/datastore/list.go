@@ -11,7 +11,7 @@ import (
 )
 
 func (impl AssociateStorerImpl) ListByFilter(ctx context.Context, f *AssociatePaginationListFilter) (*AssociatePaginationListResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	filter, err := impl.newPaginationFilter(f)
@@ -26,6 +26,9 @@ func (impl AssociateStorerImpl) ListByFilter(ctx context.Context, f *AssociatePa
 	if !f.HowDidYouHearAboutUsID.IsZero() {
 		filter["how_did_you_hear_about_us_id"] = f.TenantID
 	}
+	if !f.ServiceFeeID.IsZero() {
+		filter["service_fee_id"] = f.ServiceFeeID
+	}
 	if f.Role > 0 {
 		filter["role"] = f.Role
 	}
@@ -126,6 +129,11 @@ func (impl AssociateStorerImpl) ListByFilter(ctx context.Context, f *AssociatePa
 		options.SetSort(bson.D{{"score", bson.M{"$meta": "textScore"}}})
 	}
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Execute the query
 	cursor, err := impl.Collection.Find(ctx, filter, options)
 	if err != nil {
@@ -47,7 +47,7 @@ type AssociateLite struct {
 }
 
 func (impl AssociateStorerImpl) LiteListByFilter(ctx context.Context, f *AssociatePaginationListFilter) (*AssociatePaginationLiteListResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	filter, err := impl.newPaginationFilter(f)
@@ -121,6 +121,11 @@ func (impl AssociateStorerImpl) LiteListByFilter(ctx context.Context, f *Associa
 		options.SetSort(bson.D{{"score", bson.M{"$meta": "textScore"}}})
 	}
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Execute the query
 	cursor, err := impl.Collection.Find(ctx, filter, options)
 	if err != nil {
@@ -27,6 +27,7 @@ type AssociatePaginationListFilter struct {
 	// Filter related.
 	TenantID               primitive.ObjectID
 	HowDidYouHearAboutUsID primitive.ObjectID
+	ServiceFeeID           primitive.ObjectID
 	Type                   int8
 	Role                   int8
 	Status                 int8
@@ -79,6 +80,9 @@ type AssociatePaginationListFilter struct {
 	// AllVehicleTypeIDs filter is used if you want to find all tag ids for
 	// the associate.
 	AllVehicleTypeIDs []primitive.ObjectID
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 // AssociatePaginationLiteListResult represents the paginated list results for
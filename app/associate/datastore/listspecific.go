@@ -37,3 +37,36 @@ func (impl AssociateStorerImpl) ListAll(ctx context.Context) (*AssociatePaginati
 	}
 	return impl.ListByFilter(ctx, f)
 }
+
+func (impl AssociateStorerImpl) ListByServiceFeeID(ctx context.Context, serviceFeeID primitive.ObjectID) (*AssociatePaginationListResult, error) {
+	f := &AssociatePaginationListFilter{
+		Cursor:       "",
+		PageSize:     1_000_000_000, // Max
+		SortField:    "",
+		SortOrder:    0,
+		ServiceFeeID: serviceFeeID,
+	}
+	return impl.ListByFilter(ctx, f)
+}
+
+func (impl AssociateStorerImpl) ListBySkillSetID(ctx context.Context, skillSetID primitive.ObjectID) (*AssociatePaginationListResult, error) {
+	f := &AssociatePaginationListFilter{
+		Cursor:        "",
+		PageSize:      1_000_000_000, // Max
+		SortField:     "",
+		SortOrder:     0,
+		InSkillSetIDs: []primitive.ObjectID{skillSetID},
+	}
+	return impl.ListByFilter(ctx, f)
+}
+
+func (impl AssociateStorerImpl) ListByTagID(ctx context.Context, tagID primitive.ObjectID) (*AssociatePaginationListResult, error) {
+	f := &AssociatePaginationListFilter{
+		Cursor:    "",
+		PageSize:  1_000_000_000, // Max
+		SortField: "",
+		SortOrder: 0,
+		InTagIDs:  []primitive.ObjectID{tagID},
+	}
+	return impl.ListByFilter(ctx, f)
+}
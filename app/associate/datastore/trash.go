@@ -0,0 +1,68 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+)
+
+func (impl AssociateStorerImpl) RestoreByID(ctx context.Context, id primitive.ObjectID) error {
+	// Get the previous state of the document so we can audit the change.
+	prev, err := impl.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if prev == nil || prev.DeletedAt.IsZero() {
+		return nil // Nothing to restore.
+	}
+
+	update := bson.M{"$unset": bson.M{
+		"deleted_at":           "",
+		"deleted_by_user_id":   "",
+		"deleted_by_user_name": "",
+	}}
+	_, err = impl.Collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		impl.Logger.Error("database restore by id error", slog.Any("error", err))
+		return err
+	}
+
+	curr := *prev
+	curr.DeletedAt = time.Time{}
+	curr.DeletedByUserID = primitive.NilObjectID
+	curr.DeletedByUserName = ""
+	if err := impl.AuditLogStorer.RecordChange(ctx, impl.Collection.Name(), auditlog_ds.AuditLogActionRestore, prev.TenantID, id, prev, &curr); err != nil {
+		impl.Logger.Warn("failed recording audit log", slog.Any("error", err))
+	}
+	return nil
+}
+
+// ListDeletedBefore returns the documents in the trash which were deleted
+// before the given time. A zero time returns everything in the trash.
+func (impl AssociateStorerImpl) ListDeletedBefore(ctx context.Context, deletedBefore time.Time) ([]*Associate, error) {
+	deletedAt := bson.M{"$exists": true}
+	if !deletedBefore.IsZero() {
+		deletedAt["$lt"] = deletedBefore
+	}
+	filter := bson.M{"deleted_at": deletedAt}
+	opts := options.Find().SetSort(bson.D{{"deleted_at", 1}})
+
+	cursor, err := impl.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		impl.Logger.Error("database list deleted error", slog.Any("error", err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []*Associate{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
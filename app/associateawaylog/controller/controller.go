@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+	c "github.com/over55/workery-cli/config"
+)
+
+// ScheduleReport lists the away logs whose status was (or, on a dry run,
+// would be) changed and every associate who is away afterwards.
+type ScheduleReport struct {
+	DryRun  bool            `json:"dry_run"`
+	Changes []*StatusChange `json:"changes"`
+
+	// SyncedAssociates is the number of associates whose embedded copy of
+	// their away logs was refreshed.
+	SyncedAssociates int              `json:"synced_associates"`
+	Away             []*AwayAssociate `json:"away"`
+}
+
+// StatusChange represents an away log moved to another status.
+type StatusChange struct {
+	AwayLogID     primitive.ObjectID `json:"away_log_id"`
+	AssociateID   primitive.ObjectID `json:"associate_id"`
+	AssociateName string             `json:"associate_name"`
+	From          int8               `json:"from"`
+	To            int8               `json:"to"`
+}
+
+// AwayAssociate represents an associate with an active away log.
+type AwayAssociate struct {
+	AssociateID        primitive.ObjectID `json:"associate_id"`
+	AssociateName      string             `json:"associate_name"`
+	Reason             int8               `json:"reason"`
+	ReasonOther        string             `json:"reason_other"`
+	StartDate          time.Time          `json:"start_date"`
+	UntilDate          time.Time          `json:"until_date"`
+	UntilFurtherNotice bool               `json:"until_further_notice"`
+}
+
+// AssociateAwayLogController Interface for moving away logs through their
+// statuses as their dates pass.
+type AssociateAwayLogController interface {
+	Schedule(ctx context.Context, dryRun bool) (*ScheduleReport, error)
+}
+
+type AssociateAwayLogControllerImpl struct {
+	Config                 *c.Conf
+	Logger                 *slog.Logger
+	AssociateStorer        a_ds.AssociateStorer
+	AssociateAwayLogStorer aal_ds.AssociateAwayLogStorer
+}
+
+func NewController(
+	appCfg *c.Conf,
+	loggerp *slog.Logger,
+	aStorer a_ds.AssociateStorer,
+	aalStorer aal_ds.AssociateAwayLogStorer,
+) AssociateAwayLogController {
+	s := &AssociateAwayLogControllerImpl{
+		Config:                 appCfg,
+		Logger:                 loggerp,
+		AssociateStorer:        aStorer,
+		AssociateAwayLogStorer: aalStorer,
+	}
+	return s
+}
@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+	"github.com/over55/workery-cli/app/associateawaylog/scheduler"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+)
+
+// Schedule activates the away logs whose start date has arrived, archives
+// the ones whose until date has passed and refreshes the copies embedded in
+// their associates. When `dryRun` is true nothing is saved.
+func (impl *AssociateAwayLogControllerImpl) Schedule(ctx context.Context, dryRun bool) (*ScheduleReport, error) {
+	report := &ScheduleReport{DryRun: dryRun, Changes: make([]*StatusChange, 0), Away: make([]*AwayAssociate, 0)}
+	now := time.Now()
+	userID, userName, ipAddress := auditlog_ds.GetActor(ctx)
+
+	logsByAssociate := map[primitive.ObjectID][]*aal_ds.AssociateAwayLog{}
+	for _, status := range []int8{aal_ds.AssociateAwayLogStatusActive, aal_ds.AssociateAwayLogStatusScheduled} {
+		res, err := impl.AssociateAwayLogStorer.ListByFilter(ctx, &aal_ds.AssociateAwayLogPaginationListFilter{
+			PageSize:  1_000_000,
+			SortField: "", // Forget sorting, we don't need it here.
+			SortOrder: 1,
+			Status:    status,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, l := range res.Results {
+			logsByAssociate[l.AssociateID] = append(logsByAssociate[l.AssociateID], l)
+
+			next := scheduler.Next(l, now)
+			if next == l.Status {
+				continue
+			}
+			report.Changes = append(report.Changes, &StatusChange{
+				AwayLogID:     l.ID,
+				AssociateID:   l.AssociateID,
+				AssociateName: l.AssociateName,
+				From:          l.Status,
+				To:            next,
+			})
+			l.Status = next
+			l.ModifiedAt = now
+			l.ModifiedByUserID = userID
+			l.ModifiedByUserName = userName
+			l.ModifiedFromIPAddress = ipAddress
+			if dryRun {
+				continue
+			}
+			if err := impl.AssociateAwayLogStorer.UpdateByID(ctx, l); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for associateID, logs := range logsByAssociate {
+		for _, l := range logs {
+			if l.Status == aal_ds.AssociateAwayLogStatusActive {
+				report.Away = append(report.Away, &AwayAssociate{
+					AssociateID:        l.AssociateID,
+					AssociateName:      l.AssociateName,
+					Reason:             l.Reason,
+					ReasonOther:        l.ReasonOther,
+					StartDate:          l.StartDate,
+					UntilDate:          l.UntilDate,
+					UntilFurtherNotice: l.UntilFurtherNotice == aal_ds.UntilFurtherNoticeYes,
+				})
+			}
+		}
+
+		synced, err := impl.syncAssociate(ctx, associateID, logs, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		if synced {
+			report.SyncedAssociates++
+		}
+	}
+
+	sort.SliceStable(report.Away, func(i, j int) bool {
+		return report.Away[i].AssociateName < report.Away[j].AssociateName
+	})
+	return report, nil
+}
+
+// syncAssociate refreshes the copies of the away logs embedded in the
+// associate. Imported copies were given their own ID so they are matched by
+// their public ID as well.
+func (impl *AssociateAwayLogControllerImpl) syncAssociate(ctx context.Context, associateID primitive.ObjectID, logs []*aal_ds.AssociateAwayLog, dryRun bool) (bool, error) {
+	a, err := impl.AssociateStorer.GetByID(ctx, associateID)
+	if err != nil {
+		return false, err
+	}
+	if a == nil {
+		impl.Logger.Warn("associate of away log does not exist",
+			slog.Any("associate_id", associateID))
+		return false, nil
+	}
+
+	var changed bool
+	for _, l := range logs {
+		copied := toAssociateAwayLog(l)
+		var found bool
+		for i, al := range a.AwayLogs {
+			if al.ID != l.ID && (l.PublicID == 0 || al.PublicID != l.PublicID) {
+				continue
+			}
+			found = true
+			if *al != *copied {
+				a.AwayLogs[i] = copied
+				changed = true
+			}
+			break
+		}
+		if !found {
+			a.AwayLogs = append(a.AwayLogs, copied)
+			changed = true
+		}
+	}
+	if !changed || dryRun {
+		return changed, nil
+	}
+	return true, impl.AssociateStorer.UpdateByID(ctx, a)
+}
+
+func toAssociateAwayLog(l *aal_ds.AssociateAwayLog) *a_ds.AssociateAwayLog {
+	return &a_ds.AssociateAwayLog{
+		ID:                    l.ID,
+		TenantID:              l.TenantID,
+		AssociateID:           l.AssociateID,
+		AssociateName:         l.AssociateName,
+		AssociateLexicalName:  l.AssociateLexicalName,
+		Reason:                l.Reason,
+		ReasonOther:           l.ReasonOther,
+		UntilFurtherNotice:    l.UntilFurtherNotice,
+		UntilDate:             l.UntilDate,
+		StartDate:             l.StartDate,
+		Status:                l.Status,
+		CreatedAt:             l.CreatedAt,
+		CreatedByUserID:       l.CreatedByUserID,
+		CreatedByUserName:     l.CreatedByUserName,
+		CreatedFromIPAddress:  l.CreatedFromIPAddress,
+		ModifiedAt:            l.ModifiedAt,
+		ModifiedByUserID:      l.ModifiedByUserID,
+		ModifiedByUserName:    l.ModifiedByUserName,
+		ModifiedFromIPAddress: l.ModifiedFromIPAddress,
+		PublicID:              l.PublicID,
+	}
+}
@@ -10,19 +10,28 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 	c "github.com/over55/workery-cli/config"
 )
 
 const (
-	AssociateAwayLogStatusActive   = 1
-	AssociateAwayLogStatusArchived = 2
-	UntilFurtherNoticeUnspecified  = 0
-	UntilFurtherNoticeYes          = 1
-	UntilFurtherNoticeNo           = 2
-	ReasonUnspecified              = 0
-	ReasonOther                    = 1
+	AssociateAwayLogStatusActive    = 1
+	AssociateAwayLogStatusArchived  = 2
+	AssociateAwayLogStatusScheduled = 3
+	UntilFurtherNoticeUnspecified   = 0
+	UntilFurtherNoticeYes           = 1
+	UntilFurtherNoticeNo            = 2
+	ReasonUnspecified               = 0
+	ReasonOther                     = 1
 )
 
+var AssociateAwayLogStatusLabels = map[int8]string{
+	AssociateAwayLogStatusActive:    "Active",
+	AssociateAwayLogStatusArchived:  "Archived",
+	AssociateAwayLogStatusScheduled: "Scheduled",
+}
+
 type AssociateAwayLog struct {
 	ID                    primitive.ObjectID `bson:"_id" json:"id"`
 	TenantID              primitive.ObjectID `bson:"tenant_id" json:"tenant_id,omitempty"`
@@ -44,6 +53,9 @@ type AssociateAwayLog struct {
 	ModifiedByUserName    string             `bson:"modified_by_user_name" json:"modified_by_user_name"`
 	ModifiedFromIPAddress string             `bson:"modified_from_ip_address" json:"modified_from_ip_address"`
 	PublicID              uint64             `bson:"public_id" json:"public_id"`
+	DeletedAt             time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedByUserID       primitive.ObjectID `bson:"deleted_by_user_id,omitempty" json:"deleted_by_user_id,omitempty"`
+	DeletedByUserName     string             `bson:"deleted_by_user_name,omitempty" json:"deleted_by_user_name,omitempty"`
 }
 
 type AssociateAwayLogListFilter struct {
@@ -59,6 +71,9 @@ type AssociateAwayLogListFilter struct {
 	Status          int8
 	ExcludeArchived bool
 	SearchText      string
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 type AssociateAwayLogListResult struct {
@@ -85,13 +100,19 @@ type AssociateAwayLogStorer interface {
 	ListByFilter(ctx context.Context, f *AssociateAwayLogPaginationListFilter) (*AssociateAwayLogPaginationListResult, error)
 	ListAsSelectOptionByFilter(ctx context.Context, f *AssociateAwayLogPaginationListFilter) ([]*AssociateAwayLogAsSelectOption, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	PermanentlyDeleteByID(ctx context.Context, id primitive.ObjectID) error
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	ListDeletedBefore(ctx context.Context, deletedBefore time.Time) ([]*AssociateAwayLog, error)
 	// //TODO: Add more...
+	ForTenant(tenantID primitive.ObjectID) AssociateAwayLogStorer
+	AllTenants() AssociateAwayLogStorer
 }
 
 type AssociateAwayLogStorerImpl struct {
-	Logger     *slog.Logger
-	DbClient   *mongo.Client
-	Collection *mongo.Collection
+	Logger         *slog.Logger
+	DbClient       *mongo.Client
+	Collection     *mongodb.TenantCollection
+	AuditLogStorer auditlog_ds.AuditLogStorer
 }
 
 func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) AssociateAwayLogStorer {
@@ -99,6 +120,7 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) As
 	uc := client.Database(appCfg.DB.Name).Collection("associate_away_log")
 
 	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
 		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
 		{Keys: bson.D{{Key: "public_id", Value: -1}}},
 		{Keys: bson.D{{Key: "status", Value: 1}}},
@@ -113,9 +135,10 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) As
 	}
 
 	s := &AssociateAwayLogStorerImpl{
-		Logger:     loggerp,
-		DbClient:   client,
-		Collection: uc,
+		Logger:         loggerp,
+		DbClient:       client,
+		Collection:     mongodb.NewTenantCollection(uc),
+		AuditLogStorer: auditlog_ds.NewDatastore(appCfg, loggerp, client),
 	}
 	return s
 }
@@ -12,7 +12,7 @@ import (
 )
 
 func (impl AssociateAwayLogStorerImpl) ListByFilter(ctx context.Context, f *AssociateAwayLogPaginationListFilter) (*AssociateAwayLogPaginationListResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Create the paginated filter based on the cursor
@@ -48,6 +48,11 @@ func (impl AssociateAwayLogStorerImpl) ListByFilter(ctx context.Context, f *Asso
 	// 	options.SetSort(bson.D{{"score", bson.M{"$meta": "textScore"}}})
 	// }
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Execute the query
 	cursor, err := impl.Collection.Find(ctx, filter, options)
 	if err != nil {
@@ -93,7 +98,7 @@ func (impl AssociateAwayLogStorerImpl) ListByFilter(ctx context.Context, f *Asso
 }
 
 func (impl AssociateAwayLogStorerImpl) ListAsSelectOptionByFilter(ctx context.Context, f *AssociateAwayLogPaginationListFilter) ([]*AssociateAwayLogAsSelectOption, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Get a reference to the collection
@@ -144,6 +149,11 @@ func (impl AssociateAwayLogStorerImpl) ListAsSelectOptionByFilter(ctx context.Co
 
 	options.SetSort(bson.D{{sortField, 1}}) // Sort in ascending order based on the specified field
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		query["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Retrieve the list of items from the collection
 	cursor, err := collection.Find(ctx, query, options)
 	if err != nil {
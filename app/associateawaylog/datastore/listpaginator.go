@@ -28,6 +28,9 @@ type AssociateAwayLogPaginationListFilter struct {
 	AssociateID primitive.ObjectID
 	Status      int8
 	SearchText  string
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 // AssociateAwayLogPaginationListResult represents the paginated list results for
@@ -6,9 +6,18 @@ import (
 	"log/slog"
 
 	"go.mongodb.org/mongo-driver/bson"
+
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 )
 
 func (impl AssociateAwayLogStorerImpl) UpdateByID(ctx context.Context, m *AssociateAwayLog) error {
+	// Get the previous state of the document so we can audit the change.
+	prev, err := impl.GetByID(ctx, m.ID)
+	if err != nil {
+		impl.Logger.Error("database get by id error", slog.Any("error", err))
+		return err
+	}
+
 	filter := bson.D{{"_id", m.ID}}
 
 	update := bson.M{ // DEVELOPERS NOTE: https://stackoverflow.com/a/60946010
@@ -16,9 +25,14 @@ func (impl AssociateAwayLogStorerImpl) UpdateByID(ctx context.Context, m *Associ
 	}
 
 	// execute the UpdateOne() function to update the first matching document
-	_, err := impl.Collection.UpdateOne(ctx, filter, update)
+	_, err = impl.Collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		impl.Logger.Error("database update by user id error", slog.Any("error", err))
+		return err
+	}
+
+	if err := impl.AuditLogStorer.RecordChange(ctx, impl.Collection.Name(), auditlog_ds.AuditLogActionUpdate, m.TenantID, m.ID, prev, m); err != nil {
+		impl.Logger.Warn("failed recording audit log", slog.Any("error", err))
 	}
 
 	return nil
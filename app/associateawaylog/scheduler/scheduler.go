@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"time"
+
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+)
+
+// Next returns the status the away log should have at the time. Logs become
+// active once their start date arrives and are archived once their until
+// date has passed, unless they last until further notice. Archived logs stay
+// archived as they may have been closed by hand.
+func Next(l *aal_ds.AssociateAwayLog, now time.Time) int8 {
+	if l.Status == aal_ds.AssociateAwayLogStatusArchived {
+		return l.Status
+	}
+	if HasEnded(l, now) {
+		return aal_ds.AssociateAwayLogStatusArchived
+	}
+	if !l.StartDate.IsZero() && l.StartDate.After(now) {
+		return aal_ds.AssociateAwayLogStatusScheduled
+	}
+	return aal_ds.AssociateAwayLogStatusActive
+}
+
+// HasEnded returns true if the until date of the away log has passed.
+func HasEnded(l *aal_ds.AssociateAwayLog, now time.Time) bool {
+	if l.UntilFurtherNotice == aal_ds.UntilFurtherNoticeYes || l.UntilDate.IsZero() {
+		return false
+	}
+	return !l.UntilDate.After(now)
+}
@@ -11,6 +11,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 	c "github.com/over55/workery-cli/config"
 )
 
@@ -53,6 +55,9 @@ type Attachment struct {
 	Status                int8               `bson:"status" json:"status"`                                       // 19
 	PublicID              uint64             `bson:"public_id" json:"public_id"`                                 // 20
 	Type                  int8               `bson:"type" json:"type"`                                           // 19
+	DeletedAt             time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedByUserID       primitive.ObjectID `bson:"deleted_by_user_id,omitempty" json:"deleted_by_user_id,omitempty"`
+	DeletedByUserName     string             `bson:"deleted_by_user_name,omitempty" json:"deleted_by_user_name,omitempty"`
 }
 
 type AttachmentListFilter struct {
@@ -73,6 +78,9 @@ type AttachmentListFilter struct {
 	Type            int8
 	ExcludeArchived bool
 	SearchText      string
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 type AttachmentListResult struct {
@@ -97,19 +105,27 @@ type AttachmentStorer interface {
 	UpdateByID(ctx context.Context, m *Attachment) error
 	ListByFilter(ctx context.Context, f *AttachmentListFilter) (*AttachmentListResult, error)
 	ListAsSelectOptionByFilter(ctx context.Context, f *AttachmentListFilter) ([]*AttachmentAsSelectOption, error)
+	ListByCustomerID(ctx context.Context, customerID primitive.ObjectID) (*AttachmentListResult, error)
+	ListByAssociateID(ctx context.Context, associateID primitive.ObjectID) (*AttachmentListResult, error)
 	ListByOrderID(ctx context.Context, orderID primitive.ObjectID) (*AttachmentListResult, error)
 	ListByOrderWJID(ctx context.Context, orderWJID uint64) (*AttachmentListResult, error)
 	ListByType(ctx context.Context, typeOf int8) (*AttachmentListResult, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
-	PermanentlyDeleteAllByCustomerID(ctx context.Context, customerID primitive.ObjectID) error
-	PermanentlyDeleteAllByAssociateID(ctx context.Context, associateID primitive.ObjectID) error
-	PermanentlyDeleteAllByStaffID(ctx context.Context, staffID primitive.ObjectID) error
+	PermanentlyDeleteByID(ctx context.Context, id primitive.ObjectID) error
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	ListDeletedBefore(ctx context.Context, deletedBefore time.Time) ([]*Attachment, error)
+	DeleteAllByCustomerID(ctx context.Context, customerID primitive.ObjectID) error
+	DeleteAllByAssociateID(ctx context.Context, associateID primitive.ObjectID) error
+	DeleteAllByStaffID(ctx context.Context, staffID primitive.ObjectID) error
+	ForTenant(tenantID primitive.ObjectID) AttachmentStorer
+	AllTenants() AttachmentStorer
 }
 
 type AttachmentStorerImpl struct {
-	Logger     *slog.Logger
-	DbClient   *mongo.Client
-	Collection *mongo.Collection
+	Logger         *slog.Logger
+	DbClient       *mongo.Client
+	Collection     *mongodb.TenantCollection
+	AuditLogStorer auditlog_ds.AuditLogStorer
 }
 
 func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) AttachmentStorer {
@@ -117,6 +133,7 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) At
 	uc := client.Database(appCfg.DB.Name).Collection("attachments")
 
 	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
 		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
 		{Keys: bson.D{{Key: "public_id", Value: -1}}},
 		{Keys: bson.D{{Key: "status", Value: 1}}},
@@ -132,9 +149,10 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) At
 	}
 
 	s := &AttachmentStorerImpl{
-		Logger:     loggerp,
-		DbClient:   client,
-		Collection: uc,
+		Logger:         loggerp,
+		DbClient:       client,
+		Collection:     mongodb.NewTenantCollection(uc),
+		AuditLogStorer: auditlog_ds.NewDatastore(appCfg, loggerp, client),
 	}
 	return s
 }
@@ -18,7 +18,7 @@ const (
 )
 
 func (impl AttachmentStorerImpl) ListByFilter(ctx context.Context, f *AttachmentListFilter) (*AttachmentListResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Create the filter based on the cursor
@@ -71,6 +71,11 @@ func (impl AttachmentStorerImpl) ListByFilter(ctx context.Context, f *Attachment
 		options.SetSort(bson.D{{"score", bson.M{"$meta": "textScore"}}})
 	}
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Execute the query
 	cursor, err := impl.Collection.Find(ctx, filter, options)
 	if err != nil {
@@ -117,7 +122,7 @@ func (impl AttachmentStorerImpl) ListByFilter(ctx context.Context, f *Attachment
 }
 
 func (impl AttachmentStorerImpl) ListAsSelectOptionByFilter(ctx context.Context, f *AttachmentListFilter) ([]*AttachmentAsSelectOption, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Get a reference to the collection
@@ -165,6 +170,11 @@ func (impl AttachmentStorerImpl) ListAsSelectOptionByFilter(ctx context.Context,
 
 	options.SetSort(bson.D{{sortField, 1}}) // Sort in ascending order based on the specified field
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		query["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Retrieve the list of items from the collection
 	cursor, err := collection.Find(ctx, query, options)
 	if err != nil {
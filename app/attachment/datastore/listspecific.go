@@ -38,3 +38,25 @@ func (impl AttachmentStorerImpl) ListByType(ctx context.Context, typeOf int8) (*
 	}
 	return impl.ListByFilter(ctx, f)
 }
+
+func (impl AttachmentStorerImpl) ListByCustomerID(ctx context.Context, customerID primitive.ObjectID) (*AttachmentListResult, error) {
+	f := &AttachmentListFilter{
+		Cursor:     primitive.NilObjectID,
+		PageSize:   1_000_000,
+		SortField:  "id",
+		SortOrder:  OrderAscending,
+		CustomerID: customerID,
+	}
+	return impl.ListByFilter(ctx, f)
+}
+
+func (impl AttachmentStorerImpl) ListByAssociateID(ctx context.Context, associateID primitive.ObjectID) (*AttachmentListResult, error) {
+	f := &AttachmentListFilter{
+		Cursor:      primitive.NilObjectID,
+		PageSize:    1_000_000,
+		SortField:   "id",
+		SortOrder:   OrderAscending,
+		AssociateID: associateID,
+	}
+	return impl.ListByFilter(ctx, f)
+}
@@ -0,0 +1,109 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	c "github.com/over55/workery-cli/config"
+)
+
+const (
+	AuditLogActionUpdate  = 1
+	AuditLogActionUpsert  = 2
+	AuditLogActionDelete  = 3
+	AuditLogActionRestore = 4
+	AuditLogActionPurge   = 5
+)
+
+var AuditLogActionLabels = map[int8]string{
+	AuditLogActionUpdate:  "Update",
+	AuditLogActionUpsert:  "Upsert",
+	AuditLogActionDelete:  "Delete",
+	AuditLogActionRestore: "Restore",
+	AuditLogActionPurge:   "Purge",
+}
+
+// AuditLog represents a single mutation performed against a document in one
+// of our collections along with who performed it and what changed.
+type AuditLog struct {
+	ID             primitive.ObjectID `bson:"_id" json:"id"`
+	TenantID       primitive.ObjectID `bson:"tenant_id" json:"tenant_id,omitempty"`
+	CollectionName string             `bson:"collection_name" json:"collection_name"`
+	DocumentID     primitive.ObjectID `bson:"document_id" json:"document_id"`
+	Action         int8               `bson:"action" json:"action"`
+	Changes        []*AuditLogChange  `bson:"changes" json:"changes"`
+	ActorUserID    primitive.ObjectID `bson:"actor_user_id" json:"actor_user_id,omitempty"`
+	ActorUserName  string             `bson:"actor_user_name" json:"actor_user_name"`
+	ActorIPAddress string             `bson:"actor_ip_address" json:"actor_ip_address"`
+	Command        string             `bson:"command" json:"command"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// AuditLogChange represents the before and after value of a single field.
+type AuditLogChange struct {
+	Field    string      `bson:"field" json:"field"`
+	OldValue interface{} `bson:"old_value" json:"old_value"`
+	NewValue interface{} `bson:"new_value" json:"new_value"`
+}
+
+type AuditLogListFilter struct {
+	// Pagination related.
+	PageSize  int64
+	SortOrder int8 // 1=ascending | -1=descending
+
+	// Filter related.
+	TenantID       primitive.ObjectID
+	CollectionName string
+	DocumentID     primitive.ObjectID
+	Action         int8
+	CreatedAtGTE   time.Time
+}
+
+type AuditLogListResult struct {
+	Results []*AuditLog `json:"results"`
+}
+
+// AuditLogStorer Interface for audit log.
+type AuditLogStorer interface {
+	Create(ctx context.Context, m *AuditLog) error
+	ListByFilter(ctx context.Context, f *AuditLogListFilter) (*AuditLogListResult, error)
+	ListByDocumentID(ctx context.Context, collectionName string, documentID primitive.ObjectID) (*AuditLogListResult, error)
+	RecordChange(ctx context.Context, collectionName string, action int8, tenantID primitive.ObjectID, documentID primitive.ObjectID, before interface{}, after interface{}) error
+	RedactByDocumentID(ctx context.Context, collectionName string, documentID primitive.ObjectID, fields []string) (int64, error)
+}
+
+type AuditLogStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) AuditLogStorer {
+	// ctx := context.Background()
+	uc := client.Database(appCfg.DB.Name).Collection("audit_logs")
+
+	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
+		{Keys: bson.D{{Key: "collection_name", Value: 1}, {Key: "document_id", Value: 1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "action", Value: 1}}},
+	})
+	if err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	s := &AuditLogStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+	return s
+}
@@ -0,0 +1,65 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (impl AuditLogStorerImpl) ListByFilter(ctx context.Context, f *AuditLogListFilter) (*AuditLogListResult, error) {
+	filter := bson.M{}
+
+	// Add filter conditions to the filter
+	if !f.TenantID.IsZero() {
+		filter["tenant_id"] = f.TenantID
+	}
+	if f.CollectionName != "" {
+		filter["collection_name"] = f.CollectionName
+	}
+	if !f.DocumentID.IsZero() {
+		filter["document_id"] = f.DocumentID
+	}
+	if f.Action != 0 {
+		filter["action"] = f.Action
+	}
+	if !f.CreatedAtGTE.IsZero() {
+		filter["created_at"] = bson.M{"$gte": f.CreatedAtGTE}
+	}
+
+	impl.Logger.Debug("listing filter:",
+		slog.Any("filter", filter))
+
+	sortOrder := f.SortOrder
+	if sortOrder == 0 {
+		sortOrder = 1
+	}
+	opts := options.Find().SetSort(bson.D{{"created_at", sortOrder}, {"_id", sortOrder}})
+	if f.PageSize > 0 {
+		opts.SetLimit(f.PageSize)
+	}
+
+	cursor, err := impl.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []*AuditLog{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	return &AuditLogListResult{Results: results}, nil
+}
+
+func (impl AuditLogStorerImpl) ListByDocumentID(ctx context.Context, collectionName string, documentID primitive.ObjectID) (*AuditLogListResult, error) {
+	f := &AuditLogListFilter{
+		SortOrder:      1,
+		CollectionName: collectionName,
+		DocumentID:     documentID,
+	}
+	return impl.ListByFilter(ctx, f)
+}
@@ -0,0 +1,188 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/user"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/over55/workery-cli/config/constants"
+)
+
+var (
+	defaultActorMu       sync.RWMutex
+	defaultActorUserName string
+	defaultActorCommand  string
+)
+
+// SetDefaultActor sets the actor name and command which will be attached to
+// every audit log entry when the context does not specify who is making the
+// change. The CLI calls this once on startup with the operating system user
+// and the command being executed.
+func SetDefaultActor(userName string, command string) {
+	defaultActorMu.Lock()
+	defer defaultActorMu.Unlock()
+	defaultActorUserName = userName
+	defaultActorCommand = command
+}
+
+// secretFields lists, per collection, the fields whose values must never be
+// copied into the audit log. A change to one of them is still recorded but
+// its old and new values are replaced with `RedactedValue`.
+var secretFields = map[string]map[string]struct{}{
+	"users": {
+		"password_hash":            {},
+		"otp_secret":               {},
+		"otp_auth_url":             {},
+		"otp_recovery_code_hashes": {},
+		"pr_access_code":           {},
+		"email_verification_code":  {},
+	},
+	"customers":  {"pr_access_code": {}},
+	"associates": {"pr_access_code": {}},
+	"staff":      {"pr_access_code": {}},
+}
+
+func getDefaultActor() (string, string) {
+	defaultActorMu.RLock()
+	defer defaultActorMu.RUnlock()
+
+	userName := defaultActorUserName
+	if userName == "" {
+		if u, err := user.Current(); err == nil {
+			userName = u.Username
+		}
+	}
+	command := defaultActorCommand
+	if command == "" && len(os.Args) > 0 {
+		command = os.Args[0]
+	}
+	return userName, command
+}
+
+// RecordChange will compare the `before` and `after` documents and save an
+// audit log entry of the fields which changed. If nothing changed on an update
+// then no entry is saved. Either `before` or `after` may be nil.
+func (impl AuditLogStorerImpl) RecordChange(ctx context.Context, collectionName string, action int8, tenantID primitive.ObjectID, documentID primitive.ObjectID, before interface{}, after interface{}) error {
+	changes, err := diffDocuments(before, after)
+	if err != nil {
+		impl.Logger.Error("failed diffing documents for audit log",
+			slog.String("collection", collectionName),
+			slog.Any("document_id", documentID),
+			slog.Any("error", err))
+		return err
+	}
+	if secrets, ok := secretFields[collectionName]; ok {
+		for _, c := range changes {
+			if _, ok := secrets[c.Field]; ok {
+				c.OldValue = RedactedValue
+				c.NewValue = RedactedValue
+			}
+		}
+	}
+	if len(changes) == 0 && (action == AuditLogActionUpdate || action == AuditLogActionUpsert) {
+		return nil
+	}
+
+	userID, userName, ipAddress := GetActor(ctx)
+	_, command := getDefaultActor()
+	m := &AuditLog{
+		ID:             primitive.NewObjectID(),
+		TenantID:       tenantID,
+		CollectionName: collectionName,
+		DocumentID:     documentID,
+		Action:         action,
+		Changes:        changes,
+		ActorUserID:    userID,
+		ActorUserName:  userName,
+		ActorIPAddress: ipAddress,
+		Command:        command,
+		CreatedAt:      time.Now(),
+	}
+
+	return impl.Create(ctx, m)
+}
+
+// GetActor returns who is responsible for changes made with this context. The
+// session values in the context take precedence over the process defaults.
+func GetActor(ctx context.Context) (primitive.ObjectID, string, string) {
+	userID := primitive.NilObjectID
+	userName, _ := getDefaultActor()
+	ipAddress := ""
+
+	if v, ok := ctx.Value(constants.SessionUserID).(primitive.ObjectID); ok {
+		userID = v
+	}
+	if v, ok := ctx.Value(constants.SessionUserName).(string); ok && v != "" {
+		userName = v
+	}
+	if v, ok := ctx.Value(constants.SessionIPAddress).(string); ok {
+		ipAddress = v
+	}
+	return userID, userName, ipAddress
+}
+
+// diffDocuments returns the top-level fields which differ between the two
+// documents once they have been encoded the same way they are stored.
+func diffDocuments(before interface{}, after interface{}) ([]*AuditLogChange, error) {
+	b, err := toBSONMap(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := toBSONMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]struct{}, len(b)+len(a))
+	for k := range b {
+		fields[k] = struct{}{}
+	}
+	for k := range a {
+		fields[k] = struct{}{}
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	changes := []*AuditLogChange{}
+	for _, k := range keys {
+		oldValue, newValue := b[k], a[k]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, &AuditLogChange{
+			Field:    k,
+			OldValue: oldValue,
+			NewValue: newValue,
+		})
+	}
+	return changes, nil
+}
+
+func toBSONMap(doc interface{}) (bson.M, error) {
+	if doc == nil {
+		return bson.M{}, nil
+	}
+	if rv := reflect.ValueOf(doc); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return bson.M{}, nil
+	}
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	m := bson.M{}
+	if err := bson.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
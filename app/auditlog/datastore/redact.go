@@ -0,0 +1,49 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RedactedValue replaces the old and new values of the redacted changes.
+const RedactedValue = "[redacted]"
+
+// RedactByDocumentID overwrites the old and new values of the given fields in
+// every audit log entry of the document, or of every field if none are given,
+// and returns how many entries were modified. The entries themselves are kept
+// so who changed the document and when stays on record.
+func (impl AuditLogStorerImpl) RedactByDocumentID(ctx context.Context, collectionName string, documentID primitive.ObjectID, fields []string) (int64, error) {
+	filter := bson.M{
+		"collection_name": collectionName,
+		"document_id":     documentID,
+	}
+	update := bson.M{"$set": bson.M{
+		"changes.$[].old_value": RedactedValue,
+		"changes.$[].new_value": RedactedValue,
+	}}
+	opts := options.Update()
+	if len(fields) > 0 {
+		filter["changes.field"] = bson.M{"$in": fields}
+		update = bson.M{"$set": bson.M{
+			"changes.$[c].old_value": RedactedValue,
+			"changes.$[c].new_value": RedactedValue,
+		}}
+		opts.SetArrayFilters(options.ArrayFilters{
+			Filters: []interface{}{bson.M{"c.field": bson.M{"$in": fields}}},
+		})
+	}
+
+	res, err := impl.Collection.UpdateMany(ctx, filter, update, opts)
+	if err != nil {
+		impl.Logger.Error("database redact audit logs error",
+			slog.String("collection", collectionName),
+			slog.Any("document_id", documentID),
+			slog.Any("error", err))
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
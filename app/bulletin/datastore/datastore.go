@@ -10,6 +10,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 	c "github.com/over55/workery-cli/config"
 )
 
@@ -32,6 +34,9 @@ type Bulletin struct {
 	Text                  string             `bson:"text" json:"text"`
 	Status                int8               `bson:"status" json:"status"`
 	PublicID              uint64             `bson:"public_id" json:"public_id"`
+	DeletedAt             time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedByUserID       primitive.ObjectID `bson:"deleted_by_user_id,omitempty" json:"deleted_by_user_id,omitempty"`
+	DeletedByUserName     string             `bson:"deleted_by_user_name,omitempty" json:"deleted_by_user_name,omitempty"`
 }
 
 type BulletinListFilter struct {
@@ -46,6 +51,9 @@ type BulletinListFilter struct {
 	Status          int8
 	ExcludeArchived bool
 	SearchText      string
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 type BulletinListResult struct {
@@ -72,12 +80,18 @@ type BulletinStorer interface {
 	ListByFilter(ctx context.Context, f *BulletinPaginationListFilter) (*BulletinPaginationListResult, error)
 	ListAsSelectOptionByFilter(ctx context.Context, f *BulletinListFilter) ([]*BulletinAsSelectOption, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	PermanentlyDeleteByID(ctx context.Context, id primitive.ObjectID) error
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	ListDeletedBefore(ctx context.Context, deletedBefore time.Time) ([]*Bulletin, error)
+	ForTenant(tenantID primitive.ObjectID) BulletinStorer
+	AllTenants() BulletinStorer
 }
 
 type BulletinStorerImpl struct {
-	Logger     *slog.Logger
-	DbClient   *mongo.Client
-	Collection *mongo.Collection
+	Logger         *slog.Logger
+	DbClient       *mongo.Client
+	Collection     *mongodb.TenantCollection
+	AuditLogStorer auditlog_ds.AuditLogStorer
 }
 
 func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) BulletinStorer {
@@ -85,6 +99,7 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Bu
 	uc := client.Database(appCfg.DB.Name).Collection("bulletins")
 
 	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
 		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
 		{Keys: bson.D{{Key: "public_id", Value: -1}}},
 		{Keys: bson.D{{Key: "status", Value: 1}}},
@@ -99,9 +114,10 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Bu
 	}
 
 	s := &BulletinStorerImpl{
-		Logger:     loggerp,
-		DbClient:   client,
-		Collection: uc,
+		Logger:         loggerp,
+		DbClient:       client,
+		Collection:     mongodb.NewTenantCollection(uc),
+		AuditLogStorer: auditlog_ds.NewDatastore(appCfg, loggerp, client),
 	}
 	return s
 }
@@ -12,7 +12,7 @@ import (
 )
 
 func (impl BulletinStorerImpl) ListByFilter(ctx context.Context, f *BulletinPaginationListFilter) (*BulletinPaginationListResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Create the paginated filter based on the cursor
@@ -49,6 +49,11 @@ func (impl BulletinStorerImpl) ListByFilter(ctx context.Context, f *BulletinPagi
 	// 	options.SetSort(bson.D{{"score", bson.M{"$meta": "textScore"}}})
 	// }
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Execute the query
 	cursor, err := impl.Collection.Find(ctx, filter, options)
 	if err != nil {
@@ -89,7 +94,7 @@ func (impl BulletinStorerImpl) ListByFilter(ctx context.Context, f *BulletinPagi
 }
 
 func (impl BulletinStorerImpl) ListAsSelectOptionByFilter(ctx context.Context, f *BulletinListFilter) ([]*BulletinAsSelectOption, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Get a reference to the collection
@@ -137,6 +142,11 @@ func (impl BulletinStorerImpl) ListAsSelectOptionByFilter(ctx context.Context, f
 
 	options.SetSort(bson.D{{sortField, 1}}) // Sort in ascending order based on the specified field
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		query["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Retrieve the list of items from the collection
 	cursor, err := collection.Find(ctx, query, options)
 	if err != nil {
@@ -11,6 +11,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 	c "github.com/over55/workery-cli/config"
 )
 
@@ -47,6 +49,9 @@ type Comment struct {
 	Content               string             `bson:"content" json:"content"`
 	Status                int8               `bson:"status" json:"status"`
 	PublicID              uint64             `bson:"public_id" json:"public_id"`
+	DeletedAt             time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedByUserID       primitive.ObjectID `bson:"deleted_by_user_id,omitempty" json:"deleted_by_user_id,omitempty"`
+	DeletedByUserName     string             `bson:"deleted_by_user_name,omitempty" json:"deleted_by_user_name,omitempty"`
 }
 
 type CommentListFilter struct {
@@ -67,6 +72,9 @@ type CommentListFilter struct {
 	ExcludeArchived bool
 	SearchText      string
 	BelongsTo       int8
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 type CommentListResult struct {
@@ -93,17 +101,27 @@ type CommentStorer interface {
 	ListByFilter(ctx context.Context, f *CommentListFilter) (*CommentListResult, error)
 	ListByOrderID(ctx context.Context, orderID primitive.ObjectID) (*CommentListResult, error)
 	ListByOrderWJID(ctx context.Context, orderWJID uint64) (*CommentListResult, error)
+	ListByCustomerID(ctx context.Context, customerID primitive.ObjectID) (*CommentListResult, error)
+	ListByCustomerIDWithDeleted(ctx context.Context, customerID primitive.ObjectID) (*CommentListResult, error)
+	ListByAssociateID(ctx context.Context, associateID primitive.ObjectID) (*CommentListResult, error)
+	ListByAssociateIDWithDeleted(ctx context.Context, associateID primitive.ObjectID) (*CommentListResult, error)
 	ListAsSelectOptionByFilter(ctx context.Context, f *CommentListFilter) ([]*CommentAsSelectOption, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
-	PermanentlyDeleteAllByCustomerID(ctx context.Context, customerID primitive.ObjectID) error
-	PermanentlyDeleteAllByAssociateID(ctx context.Context, associateID primitive.ObjectID) error
-	PermanentlyDeleteAllByStaffID(ctx context.Context, staffID primitive.ObjectID) error
+	PermanentlyDeleteByID(ctx context.Context, id primitive.ObjectID) error
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	ListDeletedBefore(ctx context.Context, deletedBefore time.Time) ([]*Comment, error)
+	DeleteAllByCustomerID(ctx context.Context, customerID primitive.ObjectID) error
+	DeleteAllByAssociateID(ctx context.Context, associateID primitive.ObjectID) error
+	DeleteAllByStaffID(ctx context.Context, staffID primitive.ObjectID) error
+	ForTenant(tenantID primitive.ObjectID) CommentStorer
+	AllTenants() CommentStorer
 }
 
 type CommentStorerImpl struct {
-	Logger     *slog.Logger
-	DbClient   *mongo.Client
-	Collection *mongo.Collection
+	Logger         *slog.Logger
+	DbClient       *mongo.Client
+	Collection     *mongodb.TenantCollection
+	AuditLogStorer auditlog_ds.AuditLogStorer
 }
 
 func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) CommentStorer {
@@ -111,6 +129,7 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Co
 	uc := client.Database(appCfg.DB.Name).Collection("comments")
 
 	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
 		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
 		{Keys: bson.D{{Key: "public_id", Value: -1}}},
 		{Keys: bson.D{{Key: "status", Value: 1}}},
@@ -125,9 +144,10 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Co
 	}
 
 	s := &CommentStorerImpl{
-		Logger:     loggerp,
-		DbClient:   client,
-		Collection: uc,
+		Logger:         loggerp,
+		DbClient:       client,
+		Collection:     mongodb.NewTenantCollection(uc),
+		AuditLogStorer: auditlog_ds.NewDatastore(appCfg, loggerp, client),
 	}
 	return s
 }
@@ -18,7 +18,7 @@ const (
 )
 
 func (impl CommentStorerImpl) ListByFilter(ctx context.Context, f *CommentListFilter) (*CommentListResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Create the filter based on the cursor
@@ -68,6 +68,11 @@ func (impl CommentStorerImpl) ListByFilter(ctx context.Context, f *CommentListFi
 		options.SetSort(bson.D{{"score", bson.M{"$meta": "textScore"}}})
 	}
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Execute the query
 	cursor, err := impl.Collection.Find(ctx, filter, options)
 	if err != nil {
@@ -114,7 +119,7 @@ func (impl CommentStorerImpl) ListByFilter(ctx context.Context, f *CommentListFi
 }
 
 func (impl CommentStorerImpl) ListAsSelectOptionByFilter(ctx context.Context, f *CommentListFilter) ([]*CommentAsSelectOption, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Get a reference to the collection
@@ -168,6 +173,11 @@ func (impl CommentStorerImpl) ListAsSelectOptionByFilter(ctx context.Context, f
 
 	options.SetSort(bson.D{{sortField, 1}}) // Sort in ascending order based on the specified field
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		query["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Retrieve the list of items from the collection
 	cursor, err := collection.Find(ctx, query, options)
 	if err != nil {
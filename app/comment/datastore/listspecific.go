@@ -27,3 +27,53 @@ func (impl CommentStorerImpl) ListByOrderWJID(ctx context.Context, orderWJID uin
 	}
 	return impl.ListByFilter(ctx, f)
 }
+
+func (impl CommentStorerImpl) ListByCustomerID(ctx context.Context, customerID primitive.ObjectID) (*CommentListResult, error) {
+	f := &CommentListFilter{
+		Cursor:     primitive.NilObjectID,
+		PageSize:   1_000_000,
+		SortField:  "id",
+		SortOrder:  OrderAscending,
+		CustomerID: customerID,
+	}
+	return impl.ListByFilter(ctx, f)
+}
+
+// ListByCustomerIDWithDeleted does the same as `ListByCustomerID` along
+// with the comments in the trash.
+func (impl CommentStorerImpl) ListByCustomerIDWithDeleted(ctx context.Context, customerID primitive.ObjectID) (*CommentListResult, error) {
+	f := &CommentListFilter{
+		Cursor:         primitive.NilObjectID,
+		PageSize:       1_000_000,
+		SortField:      "id",
+		SortOrder:      OrderAscending,
+		CustomerID:     customerID,
+		IncludeDeleted: true,
+	}
+	return impl.ListByFilter(ctx, f)
+}
+
+func (impl CommentStorerImpl) ListByAssociateID(ctx context.Context, associateID primitive.ObjectID) (*CommentListResult, error) {
+	f := &CommentListFilter{
+		Cursor:      primitive.NilObjectID,
+		PageSize:    1_000_000,
+		SortField:   "id",
+		SortOrder:   OrderAscending,
+		AssociateID: associateID,
+	}
+	return impl.ListByFilter(ctx, f)
+}
+
+// ListByAssociateIDWithDeleted does the same as `ListByAssociateID` along
+// with the comments in the trash.
+func (impl CommentStorerImpl) ListByAssociateIDWithDeleted(ctx context.Context, associateID primitive.ObjectID) (*CommentListResult, error) {
+	f := &CommentListFilter{
+		Cursor:         primitive.NilObjectID,
+		PageSize:       1_000_000,
+		SortField:      "id",
+		SortOrder:      OrderAscending,
+		AssociateID:    associateID,
+		IncludeDeleted: true,
+	}
+	return impl.ListByFilter(ctx, f)
+}
@@ -0,0 +1,121 @@
+package compliance
+
+import (
+	"time"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	c "github.com/over55/workery-cli/config"
+)
+
+const (
+	StatusValid    = "valid"
+	StatusExpiring = "expiring"
+	StatusExpired  = "expired"
+	StatusMissing  = "missing"
+)
+
+// Requirement is a date of an associate which must not have expired. When
+// `ValidityDays` is zero the date is the expiry date, otherwise it is the
+// date the requirement was met and it expires that many days later. When
+// `Applies` is set the requirement is skipped for associates it returns false.
+type Requirement struct {
+	Name         string
+	ValidityDays int
+	Date         func(a *a_ds.Associate) time.Time
+	Applies      func(a *a_ds.Associate) bool
+}
+
+// Policy is every requirement associates are evaluated against.
+type Policy struct {
+	Requirements []*Requirement
+}
+
+// Finding is the evaluation of one requirement of an associate.
+type Finding struct {
+	Requirement string    `json:"requirement"`
+	Status      string    `json:"status"`
+	ExpiresAt   time.Time `json:"expires_at,omitempty"`
+	DaysLeft    int       `json:"days_left"`
+}
+
+// NewPolicy returns the policy with the validity windows of the config.
+func NewPolicy(cfg *c.Conf) *Policy {
+	return &Policy{
+		Requirements: []*Requirement{
+			{
+				Name: "Commercial insurance",
+				Date: func(a *a_ds.Associate) time.Time { return a.CommercialInsuranceExpiryDate },
+			},
+			{
+				Name:    "Auto insurance",
+				Date:    func(a *a_ds.Associate) time.Time { return a.AutoInsuranceExpiryDate },
+				Applies: func(a *a_ds.Associate) bool { return len(a.VehicleTypes) > 0 },
+			},
+			{
+				Name:         "WSIB",
+				ValidityDays: cfg.Compliance.WsibValidityDays,
+				Date:         func(a *a_ds.Associate) time.Time { return a.WsibInsuranceDate },
+			},
+			{
+				Name:         "Police check",
+				ValidityDays: cfg.Compliance.PoliceCheckValidityDays,
+				Date:         func(a *a_ds.Associate) time.Time { return a.PoliceCheck },
+			},
+			{
+				Name:         "Dues",
+				ValidityDays: cfg.Compliance.DuesValidityDays,
+				Date:         func(a *a_ds.Associate) time.Time { return a.DuesDate },
+			},
+		},
+	}
+}
+
+// Evaluate returns the findings of every requirement of the associate which
+// is missing, expired or expires within the number of days.
+func (p *Policy) Evaluate(a *a_ds.Associate, now time.Time, withinDays int) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, r := range p.Requirements {
+		if r.Applies != nil && !r.Applies(a) {
+			continue
+		}
+		if f := r.evaluate(a, now, withinDays); f.Status != StatusValid {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+func (r *Requirement) evaluate(a *a_ds.Associate, now time.Time, withinDays int) *Finding {
+	date := r.Date(a)
+	if date.IsZero() {
+		return &Finding{Requirement: r.Name, Status: StatusMissing}
+	}
+	expiresAt := date
+	if r.ValidityDays > 0 {
+		expiresAt = date.AddDate(0, 0, r.ValidityDays)
+	}
+
+	f := &Finding{
+		Requirement: r.Name,
+		Status:      StatusValid,
+		ExpiresAt:   expiresAt,
+		DaysLeft:    int(expiresAt.Sub(now).Hours() / 24),
+	}
+	switch {
+	case !expiresAt.After(now):
+		f.Status = StatusExpired
+	case expiresAt.Before(now.AddDate(0, 0, withinDays)):
+		f.Status = StatusExpiring
+	}
+	return f
+}
+
+// IsCompliant returns true if none of the findings are missing or expired.
+func IsCompliant(findings []*Finding) bool {
+	for _, f := range findings {
+		if f.Status == StatusExpired || f.Status == StatusMissing {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	"github.com/over55/workery-cli/app/compliance"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	c "github.com/over55/workery-cli/config"
+)
+
+// AssociateCompliance lists the requirements an associate is missing or
+// which have expired or are about to expire.
+type AssociateCompliance struct {
+	AssociateID   primitive.ObjectID    `json:"associate_id"`
+	PublicID      uint64                `json:"public_id"`
+	AssociateName string                `json:"associate_name"`
+	Email         string                `json:"email"`
+	Phone         string                `json:"phone"`
+	OpenOrders    int64                 `json:"open_orders"`
+	Compliant     bool                  `json:"compliant"`
+	Findings      []*compliance.Finding `json:"findings"`
+}
+
+// HasOpenOrdersWhileNonCompliant returns true if the associate is working on
+// orders without meeting every requirement.
+func (ac *AssociateCompliance) HasOpenOrdersWhileNonCompliant() bool {
+	return !ac.Compliant && ac.OpenOrders > 0
+}
+
+// ComplianceController Interface for evaluating associates against the
+// compliance policy.
+type ComplianceController interface {
+	Report(ctx context.Context, withinDays int) ([]*AssociateCompliance, error)
+}
+
+type ComplianceControllerImpl struct {
+	Config          *c.Conf
+	Logger          *slog.Logger
+	Policy          *compliance.Policy
+	AssociateStorer a_ds.AssociateStorer
+	OrderStorer     o_ds.OrderStorer
+}
+
+func NewController(
+	appCfg *c.Conf,
+	loggerp *slog.Logger,
+	aStorer a_ds.AssociateStorer,
+	oStorer o_ds.OrderStorer,
+) ComplianceController {
+	s := &ComplianceControllerImpl{
+		Config:          appCfg,
+		Logger:          loggerp,
+		Policy:          compliance.NewPolicy(appCfg),
+		AssociateStorer: aStorer,
+		OrderStorer:     oStorer,
+	}
+	return s
+}
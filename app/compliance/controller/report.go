@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	"github.com/over55/workery-cli/app/compliance"
+)
+
+// Report returns every active associate with a requirement which is missing,
+// expired or expires within the number of days, ordered by name.
+func (impl *ComplianceControllerImpl) Report(ctx context.Context, withinDays int) ([]*AssociateCompliance, error) {
+	res, err := impl.AssociateStorer.ListByFilter(ctx, &a_ds.AssociatePaginationListFilter{
+		PageSize:  1_000_000,
+		SortField: "lexical_name",
+		SortOrder: a_ds.OrderAscending,
+		Status:    a_ds.AssociateStatusActive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := make([]*AssociateCompliance, 0)
+	for _, a := range res.Results {
+		findings := impl.Policy.Evaluate(a, now, withinDays)
+		if len(findings) == 0 {
+			continue
+		}
+
+		openOrders, err := impl.OrderStorer.CountOpenByAssociateID(ctx, a.ID)
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, &AssociateCompliance{
+			AssociateID:   a.ID,
+			PublicID:      a.PublicID,
+			AssociateName: a.Name,
+			Email:         a.Email,
+			Phone:         a.Phone,
+			OpenOrders:    openOrders,
+			Compliant:     compliance.IsCompliant(findings),
+			Findings:      findings,
+		})
+	}
+	return report, nil
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	resync_c "github.com/over55/workery-cli/app/resync/controller"
+	staff_ds "github.com/over55/workery-cli/app/staff/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	c "github.com/over55/workery-cli/config"
+)
+
+const (
+	KindCustomer  = "customer"
+	KindAssociate = "associate"
+	KindStaff     = "staff"
+	KindUser      = "user"
+
+	FlagInvalidPhone          = "invalid phone"
+	FlagExtensionWithoutField = "extension has no field to move to"
+	FlagExtensionConflict     = "extension differs from the extension field"
+	FlagEmailTaken            = "normalized email belongs to another user"
+)
+
+// Kinds lists the kinds of people records in the order they are normalized.
+var Kinds = []string{KindCustomer, KindAssociate, KindStaff, KindUser}
+
+// Change is a single field of a record which was (or, on a dry run, would
+// be) normalized, or which was flagged for review. Flagged fields are left
+// as they are.
+type Change struct {
+	Kind     string             `json:"kind"`
+	ID       primitive.ObjectID `json:"id"`
+	PublicID uint64             `json:"public_id"`
+	Name     string             `json:"name"`
+	Field    string             `json:"field"`
+	Before   string             `json:"before"`
+	After    string             `json:"after,omitempty"`
+	Flag     string             `json:"flag,omitempty"`
+}
+
+// NormalizeReport lists the before and after value of every normalized field
+// along with the flagged ones.
+type NormalizeReport struct {
+	DryRun          bool      `json:"dry_run"`
+	Checked         int       `json:"checked"`
+	Updated         int       `json:"updated"`
+	RefreshedCopies int       `json:"refreshed_copies"`
+	Changes         []*Change `json:"changes"`
+}
+
+// ContactController Interface for normalizing the phone numbers and emails
+// of every person.
+type ContactController interface {
+	Normalize(ctx context.Context, kinds []string, dryRun bool) (*NormalizeReport, error)
+}
+
+type ContactControllerImpl struct {
+	Config          *c.Conf
+	Logger          *slog.Logger
+	CustomerStorer  c_ds.CustomerStorer
+	AssociateStorer a_ds.AssociateStorer
+	StaffStorer     staff_ds.StaffStorer
+	UserStorer      user_ds.UserStorer
+	Resync          resync_c.ResyncController
+}
+
+func NewController(
+	appCfg *c.Conf,
+	loggerp *slog.Logger,
+	cStorer c_ds.CustomerStorer,
+	aStorer a_ds.AssociateStorer,
+	staffStorer staff_ds.StaffStorer,
+	uStorer user_ds.UserStorer,
+	resync resync_c.ResyncController,
+) ContactController {
+	s := &ContactControllerImpl{
+		Config:          appCfg,
+		Logger:          loggerp,
+		CustomerStorer:  cStorer,
+		AssociateStorer: aStorer,
+		StaffStorer:     staffStorer,
+		UserStorer:      uStorer,
+		Resync:          resync,
+	}
+	return s
+}
@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/over55/workery-cli/app/contact"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	staff_ds "github.com/over55/workery-cli/app/staff/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+)
+
+// record collects the changes made to the fields of one person record.
+type record struct {
+	kind     string
+	id       primitive.ObjectID
+	publicID uint64
+	name     string
+	changes  []*Change
+	changed  bool
+}
+
+func (r *record) add(field, before, after, flag string) {
+	r.changes = append(r.changes, &Change{
+		Kind:     r.kind,
+		ID:       r.id,
+		PublicID: r.publicID,
+		Name:     r.name,
+		Field:    field,
+		Before:   before,
+		After:    after,
+		Flag:     flag,
+	})
+}
+
+func (r *record) set(field string, p *string, v string) {
+	if *p == v {
+		return
+	}
+	r.add(field, *p, v, "")
+	*p = v
+	r.changed = true
+}
+
+// email normalizes the email and flags it when it is invalid, a role
+// address or a placeholder.
+func (r *record) email(field string, p *string) {
+	r.set(field, p, contact.NormalizeEmail(*p))
+	for _, flag := range contact.EmailFlags(*p) {
+		r.add(field, *p, "", flag)
+	}
+}
+
+// phone converts the number to E.164 and moves its extension into the
+// extension field, which is nil for the phones which have none.
+func (r *record) phone(field string, p *string, extensionField string, extension *string) {
+	if *p == "" {
+		return
+	}
+	e164, ext, err := contact.NormalizePhone(*p)
+	if err != nil {
+		r.add(field, *p, "", FlagInvalidPhone)
+		return
+	}
+	if ext != "" {
+		if extension == nil {
+			r.add(field, *p, "", FlagExtensionWithoutField)
+			return
+		}
+		if *extension != "" && contact.Digits(*extension) != ext {
+			r.add(field, *p, "", FlagExtensionConflict)
+			return
+		}
+		r.set(extensionField, extension, ext)
+	}
+	r.set(field, p, e164)
+}
+
+// Normalize converts the phone numbers of the given kinds of people records
+// to E.164, moves their extensions into the extension fields and normalizes
+// their emails. Fields which cannot be normalized are flagged and left as
+// they are. The copies of the changed customers and associates held by
+// orders and task items are refreshed.
+func (impl *ContactControllerImpl) Normalize(ctx context.Context, kinds []string, dryRun bool) (*NormalizeReport, error) {
+	report := &NormalizeReport{DryRun: dryRun, Changes: []*Change{}}
+	done := func(r *record) bool {
+		report.Checked++
+		report.Changes = append(report.Changes, r.changes...)
+		if !r.changed {
+			return false
+		}
+		report.Updated++
+		return !dryRun
+	}
+
+	for _, kind := range kinds {
+		switch kind {
+		case KindCustomer:
+			res, err := impl.CustomerStorer.ListByFilter(ctx, &c_ds.CustomerPaginationListFilter{
+				Cursor:    "",
+				PageSize:  1_000_000,
+				SortField: "",
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, cu := range res.Results {
+				r := &record{kind: kind, id: cu.ID, publicID: cu.PublicID, name: cu.Name}
+				r.email("email", &cu.Email)
+				r.phone("phone", &cu.Phone, "phone_extension", &cu.PhoneExtension)
+				r.phone("other_phone", &cu.OtherPhone, "other_phone_extension", &cu.OtherPhoneExtension)
+				r.phone("shipping_phone", &cu.ShippingPhone, "", nil)
+				if !done(r) {
+					continue
+				}
+				if err := impl.CustomerStorer.UpdateByID(ctx, cu); err != nil {
+					return nil, err
+				}
+				resync, err := impl.Resync.ResyncCustomer(ctx, cu.ID, false)
+				if err != nil {
+					return nil, err
+				}
+				report.RefreshedCopies += len(resync.Drifts)
+			}
+
+		case KindAssociate:
+			res, err := impl.AssociateStorer.ListAll(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, a := range res.Results {
+				r := &record{kind: kind, id: a.ID, publicID: a.PublicID, name: a.Name}
+				r.email("email", &a.Email)
+				r.email("personal_email", &a.PersonalEmail)
+				r.phone("phone", &a.Phone, "phone_extension", &a.PhoneExtension)
+				r.phone("other_phone", &a.OtherPhone, "other_phone_extension", &a.OtherPhoneExtension)
+				r.phone("shipping_phone", &a.ShippingPhone, "", nil)
+				r.phone("emergency_contact_telephone", &a.EmergencyContactTelephone, "", nil)
+				r.phone("emergency_contact_alternative_telephone", &a.EmergencyContactAlternativeTelephone, "", nil)
+				if !done(r) {
+					continue
+				}
+				if err := impl.AssociateStorer.UpdateByID(ctx, a); err != nil {
+					return nil, err
+				}
+				resync, err := impl.Resync.ResyncAssociate(ctx, a.ID, false)
+				if err != nil {
+					return nil, err
+				}
+				report.RefreshedCopies += len(resync.Drifts)
+			}
+
+		case KindStaff:
+			res, err := impl.StaffStorer.ListByFilter(ctx, &staff_ds.StaffPaginationListFilter{
+				Cursor:    "",
+				PageSize:  1_000_000,
+				SortField: "",
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, s := range res.Results {
+				r := &record{kind: kind, id: s.ID, publicID: s.PublicID, name: s.Name}
+				r.email("email", &s.Email)
+				r.email("personal_email", &s.PersonalEmail)
+				r.phone("phone", &s.Phone, "phone_extension", &s.PhoneExtension)
+				r.phone("other_phone", &s.OtherPhone, "other_phone_extension", &s.OtherPhoneExtension)
+				r.phone("shipping_phone", &s.ShippingPhone, "", nil)
+				r.phone("emergency_contact_telephone", &s.EmergencyContactTelephone, "", nil)
+				r.phone("emergency_contact_alternative_telephone", &s.EmergencyContactAlternativeTelephone, "", nil)
+				if done(r) {
+					if err := impl.StaffStorer.UpdateByID(ctx, s); err != nil {
+						return nil, err
+					}
+				}
+			}
+
+		case KindUser:
+			res, err := impl.UserStorer.ListByFilter(ctx, &user_ds.UserListFilter{
+				PageSize:  1_000_000,
+				SortField: "",
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, u := range res.Results {
+				r := &record{kind: kind, id: u.ID, publicID: u.PublicID, name: u.Name}
+
+				// The email is what users log in with so it must stay unique.
+				if email := contact.NormalizeEmail(u.Email); email != u.Email {
+					other, err := impl.UserStorer.GetByEmail(ctx, email)
+					if err != nil {
+						return nil, err
+					}
+					if other != nil && other.ID != u.ID {
+						r.add("email", u.Email, "", FlagEmailTaken)
+					} else {
+						r.email("email", &u.Email)
+					}
+				} else {
+					r.email("email", &u.Email)
+				}
+				r.phone("phone", &u.Phone, "", nil)
+				if done(r) {
+					if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,83 @@
+package contact
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+
+	"github.com/over55/workery-cli/app/user/credentials"
+)
+
+const (
+	FlagInvalidEmail     = "invalid email"
+	FlagRoleEmail        = "role email"
+	FlagPlaceholderEmail = "placeholder email"
+)
+
+var ErrInvalidEmail = errors.New("invalid email")
+
+// roleLocalParts are the local parts of shared mailboxes which do not belong
+// to a person.
+var roleLocalParts = map[string]bool{
+	"accounts": true, "admin": true, "administrator": true, "billing": true,
+	"contact": true, "hello": true, "help": true, "hr": true, "info": true,
+	"inquiries": true, "mail": true, "no-reply": true, "noreply": true,
+	"office": true, "postmaster": true, "reception": true, "sales": true,
+	"service": true, "support": true, "webmaster": true,
+}
+
+// placeholderLocalParts and placeholderDomains are what staff typed when a
+// person had no email.
+var placeholderLocalParts = map[string]bool{
+	"fake": true, "n/a": true, "na": true, "no": true, "noemail": true,
+	"no-email": true, "nomail": true, "none": true, "null": true, "test": true,
+	"unknown": true,
+}
+
+var placeholderDomains = map[string]bool{
+	"email.com": true, "example.com": true, "example.net": true,
+	"example.org": true, "noemail.com": true, "nomail.com": true,
+	"none.com": true, "test.com": true,
+}
+
+// NormalizeEmail trims, lower cases and removes the spaces and `mailto:`
+// prefix of the email.
+func NormalizeEmail(s string) string {
+	s = strings.ToLower(strings.Join(strings.Fields(s), ""))
+	return strings.TrimPrefix(s, "mailto:")
+}
+
+// ValidateEmail returns `ErrInvalidEmail` unless the email is a bare address
+// whose domain has a top level domain.
+func ValidateEmail(s string) error {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s || addr.Name != "" {
+		return ErrInvalidEmail
+	}
+	at := strings.LastIndex(s, "@")
+	domain := s[at+1:]
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return ErrInvalidEmail
+	}
+	return nil
+}
+
+// EmailFlags returns what is wrong with the normalized email, if anything.
+func EmailFlags(s string) []string {
+	flags := []string{}
+	if s == "" {
+		return flags
+	}
+	if ValidateEmail(s) != nil {
+		return append(flags, FlagInvalidEmail)
+	}
+	at := strings.LastIndex(s, "@")
+	local, domain := s[:at], s[at+1:]
+	if roleLocalParts[local] {
+		flags = append(flags, FlagRoleEmail)
+	}
+	if placeholderLocalParts[local] || placeholderDomains[domain] || credentials.IsSyntheticEmail(s) {
+		flags = append(flags, FlagPlaceholderEmail)
+	}
+	return flags
+}
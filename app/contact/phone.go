@@ -0,0 +1,66 @@
+package contact
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// extensionMarker matches what separates the extension from the number, ex:
+// `519-555-1234 ext. 12`, `519 555 1234 x12` or `5195551234#12`.
+var extensionMarker = regexp.MustCompile(`(?i)\s*(?:,|;|#|extension|ext\.?|x)\s*:?\s*([0-9]{1,6})\s*$`)
+
+// NormalizePhone converts the phone number to the E.164 format, ex:
+// `+15195551234`, and returns its extension separately. Numbers without a
+// country code are assumed to be North American.
+func NormalizePhone(s string) (string, string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", "", nil
+	}
+
+	extension := ""
+	if m := extensionMarker.FindStringSubmatchIndex(s); m != nil {
+		extension = s[m[2]:m[3]]
+		s = s[:m[0]]
+	}
+
+	international := strings.HasPrefix(s, "+")
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return "", "", ErrInvalidPhone
+		}
+	}
+	digits := Digits(s)
+	if !international && strings.HasPrefix(digits, "011") {
+		international, digits = true, digits[3:]
+	}
+
+	if international && !strings.HasPrefix(digits, "1") {
+		if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+			return "", "", ErrInvalidPhone
+		}
+		return "+" + digits, extension, nil
+	}
+
+	if len(digits) == 11 && digits[0] == '1' {
+		digits = digits[1:]
+	}
+	if len(digits) != 10 || digits[0] < '2' || digits[3] < '2' {
+		return "", "", ErrInvalidPhone
+	}
+	return "+1" + digits, extension, nil
+}
+
+// Digits returns only the digits of the phone number.
+func Digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
@@ -0,0 +1,200 @@
+// Package convert copies the tags, skill sets, insurance requirements and
+// vehicle types of customers, associates and orders into the shapes embedded
+// in orders and task items.
+package convert
+
+import (
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+)
+
+// ------------------------------------------------ ORDER ------------------------------------------------ //
+
+func ToOrderTagsFromCustomerTags(fromArr []*c_ds.CustomerTag) []*o_ds.OrderTag {
+	arr := make([]*o_ds.OrderTag, 0, len(fromArr))
+	for _, t := range fromArr {
+		arr = append(arr, &o_ds.OrderTag{
+			ID:          t.ID,
+			Text:        t.Text,
+			Description: t.Description,
+			Status:      t.Status,
+		})
+	}
+	return arr
+}
+
+func ToOrderTagsFromAssociateTags(fromArr []*a_ds.AssociateTag) []*o_ds.OrderTag {
+	arr := make([]*o_ds.OrderTag, 0, len(fromArr))
+	for _, t := range fromArr {
+		arr = append(arr, &o_ds.OrderTag{
+			ID:          t.ID,
+			Text:        t.Text,
+			Description: t.Description,
+			Status:      t.Status,
+		})
+	}
+	return arr
+}
+
+func ToOrderSkillSetsFromAssociateSkillSets(fromArr []*a_ds.AssociateSkillSet) []*o_ds.OrderSkillSet {
+	arr := make([]*o_ds.OrderSkillSet, 0, len(fromArr))
+	for _, ss := range fromArr {
+		arr = append(arr, &o_ds.OrderSkillSet{
+			ID:          ss.ID,
+			Category:    ss.Category,
+			SubCategory: ss.SubCategory,
+			Description: ss.Description,
+			Status:      ss.Status,
+		})
+	}
+	return arr
+}
+
+func ToOrderInsuranceRequirementsFromAssociateInsuranceRequirements(fromArr []*a_ds.AssociateInsuranceRequirement) []*o_ds.OrderInsuranceRequirement {
+	arr := make([]*o_ds.OrderInsuranceRequirement, 0, len(fromArr))
+	for _, ir := range fromArr {
+		arr = append(arr, &o_ds.OrderInsuranceRequirement{
+			ID:          ir.ID,
+			Name:        ir.Name,
+			Description: ir.Description,
+			Status:      ir.Status,
+		})
+	}
+	return arr
+}
+
+func ToOrderVehicleTypesFromAssociateVehicleTypes(fromArr []*a_ds.AssociateVehicleType) []*o_ds.OrderVehicleType {
+	arr := make([]*o_ds.OrderVehicleType, 0, len(fromArr))
+	for _, vt := range fromArr {
+		arr = append(arr, &o_ds.OrderVehicleType{
+			ID:          vt.ID,
+			Name:        vt.Name,
+			Description: vt.Description,
+			Status:      vt.Status,
+		})
+	}
+	return arr
+}
+
+// ------------------------------------------------ TASK ITEM ------------------------------------------------ //
+
+func ToTaskItemTagsFromCustomerTags(fromArr []*c_ds.CustomerTag) []*ti_ds.TaskItemTag {
+	arr := make([]*ti_ds.TaskItemTag, 0, len(fromArr))
+	for _, t := range fromArr {
+		arr = append(arr, &ti_ds.TaskItemTag{
+			ID:          t.ID,
+			Text:        t.Text,
+			Description: t.Description,
+			Status:      t.Status,
+		})
+	}
+	return arr
+}
+
+func ToTaskItemTagsFromAssociateTags(fromArr []*a_ds.AssociateTag) []*ti_ds.TaskItemTag {
+	arr := make([]*ti_ds.TaskItemTag, 0, len(fromArr))
+	for _, t := range fromArr {
+		arr = append(arr, &ti_ds.TaskItemTag{
+			ID:          t.ID,
+			Text:        t.Text,
+			Description: t.Description,
+			Status:      t.Status,
+		})
+	}
+	return arr
+}
+
+func ToTaskItemTagsFromOrderTags(fromArr []*o_ds.OrderTag) []*ti_ds.TaskItemTag {
+	arr := make([]*ti_ds.TaskItemTag, 0, len(fromArr))
+	for _, t := range fromArr {
+		arr = append(arr, &ti_ds.TaskItemTag{
+			ID:          t.ID,
+			Text:        t.Text,
+			Description: t.Description,
+			Status:      t.Status,
+		})
+	}
+	return arr
+}
+
+func ToTaskItemSkillSetsFromAssociateSkillSets(fromArr []*a_ds.AssociateSkillSet) []*ti_ds.TaskItemSkillSet {
+	arr := make([]*ti_ds.TaskItemSkillSet, 0, len(fromArr))
+	for _, ss := range fromArr {
+		arr = append(arr, &ti_ds.TaskItemSkillSet{
+			ID:          ss.ID,
+			Category:    ss.Category,
+			SubCategory: ss.SubCategory,
+			Description: ss.Description,
+			Status:      ss.Status,
+		})
+	}
+	return arr
+}
+
+func ToTaskItemSkillSetsFromOrderSkillSets(fromArr []*o_ds.OrderSkillSet) []*ti_ds.TaskItemSkillSet {
+	arr := make([]*ti_ds.TaskItemSkillSet, 0, len(fromArr))
+	for _, ss := range fromArr {
+		arr = append(arr, &ti_ds.TaskItemSkillSet{
+			ID:          ss.ID,
+			Category:    ss.Category,
+			SubCategory: ss.SubCategory,
+			Description: ss.Description,
+			Status:      ss.Status,
+		})
+	}
+	return arr
+}
+
+func ToTaskItemInsuranceRequirementsFromAssociateInsuranceRequirements(fromArr []*a_ds.AssociateInsuranceRequirement) []*ti_ds.TaskItemInsuranceRequirement {
+	arr := make([]*ti_ds.TaskItemInsuranceRequirement, 0, len(fromArr))
+	for _, ir := range fromArr {
+		arr = append(arr, &ti_ds.TaskItemInsuranceRequirement{
+			ID:          ir.ID,
+			Name:        ir.Name,
+			Description: ir.Description,
+			Status:      ir.Status,
+		})
+	}
+	return arr
+}
+
+func ToTaskItemVehicleTypesFromAssociateVehicleTypes(fromArr []*a_ds.AssociateVehicleType) []*ti_ds.TaskItemVehicleType {
+	arr := make([]*ti_ds.TaskItemVehicleType, 0, len(fromArr))
+	for _, vt := range fromArr {
+		arr = append(arr, &ti_ds.TaskItemVehicleType{
+			ID:          vt.ID,
+			Name:        vt.Name,
+			Description: vt.Description,
+			Status:      vt.Status,
+		})
+	}
+	return arr
+}
+
+func ToTaskItemInsuranceRequirementsFromOrderInsuranceRequirements(fromArr []*o_ds.OrderInsuranceRequirement) []*ti_ds.TaskItemInsuranceRequirement {
+	arr := make([]*ti_ds.TaskItemInsuranceRequirement, 0, len(fromArr))
+	for _, ir := range fromArr {
+		arr = append(arr, &ti_ds.TaskItemInsuranceRequirement{
+			ID:          ir.ID,
+			Name:        ir.Name,
+			Description: ir.Description,
+			Status:      ir.Status,
+		})
+	}
+	return arr
+}
+
+func ToTaskItemVehicleTypesFromOrderVehicleTypes(fromArr []*o_ds.OrderVehicleType) []*ti_ds.TaskItemVehicleType {
+	arr := make([]*ti_ds.TaskItemVehicleType, 0, len(fromArr))
+	for _, vt := range fromArr {
+		arr = append(arr, &ti_ds.TaskItemVehicleType{
+			ID:          vt.ID,
+			Name:        vt.Name,
+			Description: vt.Description,
+			Status:      vt.Status,
+		})
+	}
+	return arr
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	att_ds "github.com/over55/workery-cli/app/attachment/datastore"
+	com_ds "github.com/over55/workery-cli/app/comment/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	"github.com/over55/workery-cli/app/dedupe"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	resync_c "github.com/over55/workery-cli/app/resync/controller"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	c "github.com/over55/workery-cli/config"
+)
+
+var (
+	ErrCustomerNotFound = errors.New("customer does not exist")
+	ErrSameCustomer     = errors.New("cannot merge a customer into itself")
+	ErrAlreadyArchived  = errors.New("customer to drop is already archived")
+	ErrKeepArchived     = errors.New("customer to keep is archived")
+)
+
+// MergeReport counts what was (or, on a dry run, would be) moved from the
+// dropped customer onto the kept one.
+type MergeReport struct {
+	DryRun          bool               `json:"dry_run"`
+	KeepID          primitive.ObjectID `json:"keep_id"`
+	KeepPublicID    uint64             `json:"keep_public_id"`
+	DropID          primitive.ObjectID `json:"drop_id"`
+	DropPublicID    uint64             `json:"drop_public_id"`
+	Orders          int                `json:"orders"`
+	TaskItems       int                `json:"task_items"`
+	Comments        int                `json:"comments"`
+	Attachments     int                `json:"attachments"`
+	Tags            int                `json:"tags"`
+	FilledFields    []string           `json:"filled_fields"`
+	RelinkedUserID  primitive.ObjectID `json:"relinked_user_id,omitempty"`
+	ArchivedUserID  primitive.ObjectID `json:"archived_user_id,omitempty"`
+	RefreshedCopies int                `json:"refreshed_copies"`
+}
+
+// CustomerController Interface for finding and merging duplicate customers.
+type CustomerController interface {
+	FindDuplicates(ctx context.Context, threshold float64) ([]*dedupe.Pair, error)
+	Merge(ctx context.Context, keepRef string, dropRef string, dryRun bool) (*MergeReport, error)
+}
+
+type CustomerControllerImpl struct {
+	Config           *c.Conf
+	Logger           *slog.Logger
+	DbClient         *mongo.Client
+	CustomerStorer   c_ds.CustomerStorer
+	OrderStorer      o_ds.OrderStorer
+	TaskItemStorer   ti_ds.TaskItemStorer
+	CommentStorer    com_ds.CommentStorer
+	AttachmentStorer att_ds.AttachmentStorer
+	UserStorer       user_ds.UserStorer
+	Resync           resync_c.ResyncController
+}
+
+func NewController(
+	appCfg *c.Conf,
+	loggerp *slog.Logger,
+	client *mongo.Client,
+	cStorer c_ds.CustomerStorer,
+	oStorer o_ds.OrderStorer,
+	tiStorer ti_ds.TaskItemStorer,
+	comStorer com_ds.CommentStorer,
+	attStorer att_ds.AttachmentStorer,
+	uStorer user_ds.UserStorer,
+	resync resync_c.ResyncController,
+) CustomerController {
+	s := &CustomerControllerImpl{
+		Config:           appCfg,
+		Logger:           loggerp,
+		DbClient:         client,
+		CustomerStorer:   cStorer,
+		OrderStorer:      oStorer,
+		TaskItemStorer:   tiStorer,
+		CommentStorer:    comStorer,
+		AttachmentStorer: attStorer,
+		UserStorer:       uStorer,
+		Resync:           resync,
+	}
+	return s
+}
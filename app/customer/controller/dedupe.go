@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"context"
+
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	"github.com/over55/workery-cli/app/dedupe"
+)
+
+// FindDuplicates returns the pairs of active customers which are likely the
+// same person, best first.
+func (impl *CustomerControllerImpl) FindDuplicates(ctx context.Context, threshold float64) ([]*dedupe.Pair, error) {
+	res, err := impl.CustomerStorer.ListByFilter(ctx, &c_ds.CustomerPaginationListFilter{
+		Cursor:    "",
+		PageSize:  1_000_000,
+		SortField: "",
+		Status:    c_ds.CustomerStatusActive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*dedupe.Record, 0, len(res.Results))
+	for _, cu := range res.Results {
+		records = append(records, toRecord(cu))
+	}
+	return dedupe.Find(records, threshold), nil
+}
+
+func toRecord(cu *c_ds.Customer) *dedupe.Record {
+	return &dedupe.Record{
+		ID:           cu.ID,
+		PublicID:     cu.PublicID,
+		Name:         cu.Name,
+		FirstName:    cu.FirstName,
+		LastName:     cu.LastName,
+		Emails:       []string{cu.Email},
+		Phones:       []string{cu.Phone, cu.OtherPhone},
+		AddressLine1: cu.AddressLine1,
+		PostalCode:   cu.PostalCode,
+		CreatedAt:    cu.CreatedAt,
+	}
+}
@@ -0,0 +1,261 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	att_ds "github.com/over55/workery-cli/app/attachment/datastore"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+)
+
+// Merge moves every order, task item, comment, attachment and tag of the
+// `drop` customer onto the `keep` customer, relinks the login account and
+// archives the dropped record. Either reference may be the hex ID or the
+// public ID of the customer. Documents in the trash are moved as well. The
+// changes are made in one transaction so a failed merge leaves both customers
+// as they were.
+func (impl *CustomerControllerImpl) Merge(ctx context.Context, keepRef string, dropRef string, dryRun bool) (*MergeReport, error) {
+	if dryRun {
+		return impl.merge(ctx, keepRef, dropRef, true)
+	}
+
+	session, err := impl.DbClient.StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	// The customers are looked up inside the transaction function as it is
+	// run again if the transaction is retried.
+	res, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return impl.merge(sessCtx, keepRef, dropRef, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*MergeReport), nil
+}
+
+func (impl *CustomerControllerImpl) merge(ctx context.Context, keepRef string, dropRef string, dryRun bool) (*MergeReport, error) {
+	keep, err := impl.get(ctx, keepRef)
+	if err != nil {
+		return nil, err
+	}
+	drop, err := impl.get(ctx, dropRef)
+	if err != nil {
+		return nil, err
+	}
+	if keep.ID == drop.ID {
+		return nil, ErrSameCustomer
+	}
+	if drop.Status == c_ds.CustomerStatusArchived {
+		return nil, ErrAlreadyArchived
+	}
+	if keep.Status == c_ds.CustomerStatusArchived {
+		return nil, ErrKeepArchived
+	}
+
+	report := &MergeReport{
+		DryRun:       dryRun,
+		KeepID:       keep.ID,
+		KeepPublicID: keep.PublicID,
+		DropID:       drop.ID,
+		DropPublicID: drop.PublicID,
+		FilledFields: []string{},
+	}
+
+	// Documents in the trash are moved as well so restoring one does not
+	// bring back a reference to the archived customer.
+	orders, err := impl.OrderStorer.ListByCustomerIDWithDeleted(ctx, drop.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range orders.Results {
+		report.Orders++
+		if dryRun {
+			continue
+		}
+		o.CustomerID = keep.ID
+		if err := impl.OrderStorer.UpdateByID(ctx, o); err != nil {
+			return nil, err
+		}
+	}
+
+	taskItems, err := impl.TaskItemStorer.ListByCustomerIDWithDeleted(ctx, drop.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ti := range taskItems.Results {
+		report.TaskItems++
+		if dryRun {
+			continue
+		}
+		ti.CustomerID = keep.ID
+		if err := impl.TaskItemStorer.UpdateByID(ctx, ti); err != nil {
+			return nil, err
+		}
+	}
+
+	comments, err := impl.CommentStorer.ListByCustomerIDWithDeleted(ctx, drop.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, com := range comments.Results {
+		report.Comments++
+		if dryRun {
+			continue
+		}
+		com.CustomerID = keep.ID
+		com.CustomerName = keep.Name
+		if err := impl.CommentStorer.UpdateByID(ctx, com); err != nil {
+			return nil, err
+		}
+	}
+
+	attachments, err := impl.AttachmentStorer.ListByFilter(ctx, &att_ds.AttachmentListFilter{
+		PageSize:       1_000_000,
+		SortField:      "id",
+		SortOrder:      att_ds.OrderAscending,
+		CustomerID:     drop.ID,
+		IncludeDeleted: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, att := range attachments.Results {
+		report.Attachments++
+		if dryRun {
+			continue
+		}
+		att.CustomerID = keep.ID
+		att.CustomerName = keep.Name
+		if err := impl.AttachmentStorer.UpdateByID(ctx, att); err != nil {
+			return nil, err
+		}
+	}
+
+	// Copy over the tags the kept customer does not already have along with
+	// the embedded comments.
+	hasTag := make(map[primitive.ObjectID]bool, len(keep.Tags))
+	for _, t := range keep.Tags {
+		hasTag[t.ID] = true
+	}
+	for _, t := range drop.Tags {
+		if hasTag[t.ID] {
+			continue
+		}
+		hasTag[t.ID] = true
+		keep.Tags = append(keep.Tags, t)
+		report.Tags++
+	}
+	keep.Comments = append(keep.Comments, drop.Comments...)
+
+	// Fill in contact details the kept customer is missing.
+	if keep.Email == "" && drop.Email != "" {
+		keep.Email = drop.Email
+		report.FilledFields = append(report.FilledFields, "email")
+	}
+	if keep.Phone == "" && drop.Phone != "" {
+		keep.Phone = drop.Phone
+		keep.PhoneType = drop.PhoneType
+		keep.PhoneExtension = drop.PhoneExtension
+		report.FilledFields = append(report.FilledFields, "phone")
+	}
+	if keep.OtherPhone == "" && drop.OtherPhone != "" {
+		keep.OtherPhone = drop.OtherPhone
+		keep.OtherPhoneType = drop.OtherPhoneType
+		keep.OtherPhoneExtension = drop.OtherPhoneExtension
+		report.FilledFields = append(report.FilledFields, "other_phone")
+	}
+
+	// Only one login may reference the kept customer; if it has none then
+	// the dropped customer's login is relinked, otherwise it is archived.
+	if !drop.UserID.IsZero() {
+		u, err := impl.UserStorer.GetByID(ctx, drop.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if u != nil {
+			if keep.UserID.IsZero() {
+				report.RelinkedUserID = u.ID
+				u.ReferenceID = keep.ID
+				keep.UserID = u.ID
+			} else {
+				report.ArchivedUserID = u.ID
+				u.Status = user_ds.UserStatusArchived
+			}
+			if !dryRun {
+				if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	userID, userName, ipAddress := auditlog_ds.GetActor(ctx)
+	now := time.Now()
+
+	keep.ModifiedAt = now
+	keep.ModifiedByUserID = userID
+	keep.ModifiedByUserName = userName
+	keep.ModifiedFromIPAddress = ipAddress
+	if err := impl.CustomerStorer.UpdateByID(ctx, keep); err != nil {
+		return nil, err
+	}
+
+	drop.Status = c_ds.CustomerStatusArchived
+	drop.DeactivationReason = c_ds.CustomerDeactivationReasonOther
+	drop.DeactivationReasonOther = fmt.Sprintf("Merged into customer %d", keep.PublicID)
+	drop.UserID = primitive.NilObjectID
+	drop.Tags = []*c_ds.CustomerTag{}
+	drop.ModifiedAt = now
+	drop.ModifiedByUserID = userID
+	drop.ModifiedByUserName = userName
+	drop.ModifiedFromIPAddress = ipAddress
+	if err := impl.CustomerStorer.UpdateByID(ctx, drop); err != nil {
+		return nil, err
+	}
+
+	// The moved orders and task items still hold the dropped customer's
+	// name, address and phone, so refresh their copies.
+	resync, err := impl.Resync.ResyncCustomer(ctx, keep.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	report.RefreshedCopies = len(resync.Drifts)
+
+	impl.Logger.Debug("merged customer",
+		slog.String("keep_id", keep.ID.Hex()),
+		slog.String("drop_id", drop.ID.Hex()))
+	return report, nil
+}
+
+// get looks up the customer by hex ID first and then by public ID.
+func (impl *CustomerControllerImpl) get(ctx context.Context, ref string) (*c_ds.Customer, error) {
+	var cu *c_ds.Customer
+	var err error
+	if id, hexErr := primitive.ObjectIDFromHex(ref); hexErr == nil {
+		cu, err = impl.CustomerStorer.GetByID(ctx, id)
+	} else if publicID, parseErr := strconv.ParseUint(ref, 10, 64); parseErr == nil {
+		cu, err = impl.CustomerStorer.GetByPublicID(ctx, publicID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cu == nil {
+		return nil, fmt.Errorf("%w: %s", ErrCustomerNotFound, ref)
+	}
+	return cu, nil
+}
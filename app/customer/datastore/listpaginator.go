@@ -47,6 +47,9 @@ type CustomerPaginationListFilter struct {
 	AllTagIDs []primitive.ObjectID
 
 	IsOkToEmail int8 // 0=All, 1=Yes, 2=No
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 // CustomerPaginationLiteListResult represents the paginated list results for
@@ -16,3 +16,24 @@ func (impl CustomerStorerImpl) ListByHowDidYouHearAboutUsID(ctx context.Context,
 	}
 	return impl.ListByFilter(ctx, f)
 }
+
+func (impl CustomerStorerImpl) ListByTagID(ctx context.Context, tagID primitive.ObjectID) (*CustomerPaginationListResult, error) {
+	f := &CustomerPaginationListFilter{
+		Cursor:    "",
+		PageSize:  1_000_000_000, // Max
+		SortField: "",
+		SortOrder: 0,
+		InTagIDs:  []primitive.ObjectID{tagID},
+	}
+	return impl.ListByFilter(ctx, f)
+}
+
+func (impl CustomerStorerImpl) ListAll(ctx context.Context) (*CustomerPaginationListResult, error) {
+	f := &CustomerPaginationListFilter{
+		Cursor:    "",
+		PageSize:  1_000_000_000, // Max
+		SortField: "",
+		SortOrder: 0,
+	}
+	return impl.ListByFilter(ctx, f)
+}
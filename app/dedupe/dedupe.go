@@ -0,0 +1,253 @@
+package dedupe
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/over55/workery-cli/app/contact"
+	"github.com/over55/workery-cli/app/user/credentials"
+)
+
+const (
+	ReasonEmail   = "email"
+	ReasonPhone   = "phone"
+	ReasonName    = "name"
+	ReasonAddress = "address"
+
+	// How much each kind of evidence weighs in the score of a pair, they add
+	// up to one.
+	weightEmail   = 0.35
+	weightPhone   = 0.30
+	weightName    = 0.20
+	weightAddress = 0.15
+
+	// nameThreshold is the similarity above which names count as a reason.
+	nameThreshold = 0.9
+)
+
+// Record is the contact details of a customer or associate which are
+// compared to find duplicates.
+type Record struct {
+	ID           primitive.ObjectID
+	PublicID     uint64
+	Name         string
+	FirstName    string
+	LastName     string
+	Emails       []string
+	Phones       []string
+	AddressLine1 string
+	PostalCode   string
+	CreatedAt    time.Time
+}
+
+// Pair is two records which are likely the same person. The older record is
+// suggested to be kept.
+type Pair struct {
+	KeepID       primitive.ObjectID `json:"keep_id"`
+	KeepPublicID uint64             `json:"keep_public_id"`
+	KeepName     string             `json:"keep_name"`
+	DropID       primitive.ObjectID `json:"drop_id"`
+	DropPublicID uint64             `json:"drop_public_id"`
+	DropName     string             `json:"drop_name"`
+	Score        float64            `json:"score"`
+	Reasons      []string           `json:"reasons"`
+}
+
+// Find returns the pairs of records scoring at least the threshold, best
+// first. Only records sharing an email, phone, postal code or last name are
+// compared.
+func Find(records []*Record, threshold float64) []*Pair {
+	blocks := make(map[string][]int)
+	for i, r := range records {
+		for _, key := range blockingKeys(r) {
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+
+	seen := make(map[[2]int]bool)
+	pairs := make([]*Pair, 0)
+	for _, members := range blocks {
+		for x := 0; x < len(members); x++ {
+			for y := x + 1; y < len(members); y++ {
+				i, j := members[x], members[y]
+				if i > j {
+					i, j = j, i
+				}
+				if seen[[2]int{i, j}] {
+					continue
+				}
+				seen[[2]int{i, j}] = true
+
+				if p := Compare(records[i], records[j]); p.Score >= threshold {
+					pairs = append(pairs, p)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].Score != pairs[j].Score {
+			return pairs[i].Score > pairs[j].Score
+		}
+		return pairs[i].KeepPublicID < pairs[j].KeepPublicID
+	})
+	return pairs
+}
+
+// Compare scores how likely the two records are the same person.
+func Compare(a, b *Record) *Pair {
+	keep, drop := a, b
+	if isNewer(a, b) {
+		keep, drop = b, a
+	}
+	p := &Pair{
+		KeepID:       keep.ID,
+		KeepPublicID: keep.PublicID,
+		KeepName:     keep.Name,
+		DropID:       drop.ID,
+		DropPublicID: drop.PublicID,
+		DropName:     drop.Name,
+		Reasons:      make([]string, 0),
+	}
+
+	if intersects(emails(a), emails(b)) {
+		p.Score += weightEmail
+		p.Reasons = append(p.Reasons, ReasonEmail)
+	}
+	if intersects(phones(a), phones(b)) {
+		p.Score += weightPhone
+		p.Reasons = append(p.Reasons, ReasonPhone)
+	}
+	if s := Similarity(nameKey(a), nameKey(b)); s > 0 {
+		p.Score += weightName * s
+		if s >= nameThreshold {
+			p.Reasons = append(p.Reasons, ReasonName)
+		}
+	}
+	if s := addressSimilarity(a, b); s > 0 {
+		p.Score += weightAddress * s
+		if s >= nameThreshold {
+			p.Reasons = append(p.Reasons, ReasonAddress)
+		}
+	}
+	return p
+}
+
+func isNewer(a, b *Record) bool {
+	if !a.CreatedAt.IsZero() && !b.CreatedAt.IsZero() && !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.PublicID > b.PublicID
+}
+
+func blockingKeys(r *Record) []string {
+	keys := make([]string, 0)
+	for _, e := range emails(r) {
+		keys = append(keys, "e:"+e)
+	}
+	for _, p := range phones(r) {
+		keys = append(keys, "p:"+p)
+	}
+	if pc := postalCode(r.PostalCode); pc != "" {
+		keys = append(keys, "z:"+pc)
+	}
+	if ln := normalizeText(r.LastName); ln != "" {
+		keys = append(keys, "n:"+ln)
+	}
+	return keys
+}
+
+// emails returns the normalized emails of the record, leaving out the ones
+// made up by the import commands.
+func emails(r *Record) []string {
+	out := make([]string, 0, len(r.Emails))
+	for _, e := range r.Emails {
+		e = contact.NormalizeEmail(e)
+		if e == "" || credentials.IsSyntheticEmail(e) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// phones returns the phone numbers of the record in the E.164 format, or
+// just their digits when they cannot be converted, ex: a number missing its
+// area code.
+func phones(r *Record) []string {
+	out := make([]string, 0, len(r.Phones))
+	for _, p := range r.Phones {
+		if e164, _, err := contact.NormalizePhone(p); err == nil {
+			if e164 != "" {
+				out = append(out, e164)
+			}
+			continue
+		}
+		if digits := contact.Digits(p); len(digits) >= 7 {
+			out = append(out, digits)
+		}
+	}
+	return out
+}
+
+func postalCode(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+}
+
+func nameKey(r *Record) string {
+	name := r.FirstName + " " + r.LastName
+	if strings.TrimSpace(name) == "" {
+		name = r.Name
+	}
+	tokens := strings.Fields(normalizeText(name))
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+func addressSimilarity(a, b *Record) float64 {
+	pa, pb := postalCode(a.PostalCode), postalCode(b.PostalCode)
+	la, lb := normalizeText(a.AddressLine1), normalizeText(b.AddressLine1)
+	if (pa == "" || pb == "") && (la == "" || lb == "") {
+		return 0
+	}
+	var s float64
+	if pa != "" && pa == pb {
+		s += 0.5
+	}
+	return s + 0.5*Similarity(la, lb)
+}
+
+// normalizeText lower cases the text and keeps only its letters, digits and
+// single spaces.
+func normalizeText(s string) string {
+	var sb strings.Builder
+	space := false
+	for _, c := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(c) || unicode.IsDigit(c):
+			if space && sb.Len() > 0 {
+				sb.WriteRune(' ')
+			}
+			space = false
+			sb.WriteRune(c)
+		default:
+			space = true
+		}
+	}
+	return sb.String()
+}
+
+func intersects(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+package datastore
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ForTenant returns a copy of the storer whose queries, updates and deletes
+// are constrained to the tenant.
+func (impl HowHearAboutUsItemStorerImpl) ForTenant(tenantID primitive.ObjectID) HowHearAboutUsItemStorer {
+	impl.Collection = impl.Collection.ForTenant(tenantID)
+	return &impl
+}
+
+// AllTenants returns a copy of the storer which operates across every tenant.
+func (impl HowHearAboutUsItemStorerImpl) AllTenants() HowHearAboutUsItemStorer {
+	impl.Collection = impl.Collection.AllTenants()
+	return &impl
+}
@@ -5,10 +5,12 @@ import (
 	"time"
 
 	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 func (impl InsuranceRequirementStorerImpl) ListByFilter(ctx context.Context, f *InsuranceRequirementPaginationListFilter) (*InsuranceRequirementPaginationListResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Create the paginated filter based on the cursor
@@ -42,6 +44,11 @@ func (impl InsuranceRequirementStorerImpl) ListByFilter(ctx context.Context, f *
 	// 	options.SetSort(bson.D{{"score", bson.M{"$meta": "textScore"}}})
 	// }
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Execute the query
 	cursor, err := impl.Collection.Find(ctx, filter, options)
 	if err != nil {
@@ -10,7 +10,7 @@ import (
 )
 
 func (impl InsuranceRequirementStorerImpl) ListAsSelectOptionByFilter(ctx context.Context, f *InsuranceRequirementPaginationListFilter) ([]*InsuranceRequirementAsSelectOption, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Get a reference to the collection
@@ -52,6 +52,11 @@ func (impl InsuranceRequirementStorerImpl) ListAsSelectOptionByFilter(ctx contex
 
 	options.SetSort(bson.D{{f.SortField, 1}}) // Sort in ascending order based on the specified field
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		query["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Retrieve the list of items from the collection
 	cursor, err := collection.Find(ctx, query, options)
 	if err != nil {
@@ -27,6 +27,9 @@ type InsuranceRequirementPaginationListFilter struct {
 	TenantID   primitive.ObjectID
 	Status     int8
 	SearchText string
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 // InsuranceRequirementPaginationListResult represents the paginated list results for
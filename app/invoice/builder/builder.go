@@ -0,0 +1,327 @@
+package builder
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/go-pdf/fpdf/contrib/gofpdi"
+
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+)
+
+// Template controls how an invoice looks. When `BackgroundFilePath` is set
+// the first page of that PDF is drawn under every page of the invoice, ex: a
+// letterhead, and the company details are not printed as the background is
+// expected to have them.
+type Template struct {
+	BackgroundFilePath string
+	CompanyName        string
+	CompanyAddress     []string
+	CompanyPhone       string
+	CompanyEmail       string
+}
+
+const (
+	pageMargin  = 15.0
+	lineHeight  = 5.0
+	dateLayout  = "Jan 2, 2006"
+	currencyFmt = "$%.2f"
+)
+
+// importBackground imports the first page of the PDF to draw under every
+// page. The importer panics on a file it cannot parse and never returns on
+// one without a cross-reference table, so the file is checked first and a
+// panic is turned back into an error.
+func importBackground(pdf *fpdf.Fpdf, filePath string) (importer *gofpdi.Importer, tplID int, err error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invoice template: %w", err)
+	}
+	tail := b
+	if len(tail) > 1024 {
+		tail = tail[len(tail)-1024:]
+	}
+	if !bytes.HasPrefix(b, []byte("%PDF-")) || !bytes.Contains(tail, []byte("startxref")) {
+		return nil, 0, fmt.Errorf("invoice template %v is not a valid PDF", filePath)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			importer, tplID, err = nil, 0, fmt.Errorf("invoice template %v is not a valid PDF: %v", filePath, r)
+		}
+	}()
+	importer = gofpdi.NewImporter()
+	tplID = importer.ImportPage(pdf, filePath, 1, "/MediaBox")
+	return importer, tplID, nil
+}
+
+// BuildPDF returns the invoice rendered as a PDF document.
+func BuildPDF(inv *o_ds.OrderInvoice, tmpl *Template) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "Letter", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin+10)
+	pdf.AliasNbPages("")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	if tmpl.BackgroundFilePath != "" {
+		importer, tplID, err := importBackground(pdf, tmpl.BackgroundFilePath)
+		if err != nil {
+			return nil, err
+		}
+		pdf.SetHeaderFunc(func() {
+			w, h := pdf.GetPageSize()
+			importer.UseImportedTemplate(pdf, tplID, 0, 0, w, h)
+		})
+	}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pageMargin - 5)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, lineHeight, fmt.Sprintf("Invoice %v - Page %v of {nb}", tr(invoiceNumber(inv)), pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+	width, _ := pdf.GetPageSize()
+	contentWidth := width - 2*pageMargin
+
+	//
+	// Header.
+	//
+
+	if tmpl.BackgroundFilePath == "" {
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(contentWidth/2, 7, tr(tmpl.CompanyName), "", 2, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		for _, line := range append(tmpl.CompanyAddress, tmpl.CompanyPhone, tmpl.CompanyEmail) {
+			if line != "" {
+				pdf.CellFormat(contentWidth/2, 4, tr(line), "", 2, "L", false, 0, "")
+			}
+		}
+	}
+	pdf.SetXY(pageMargin+contentWidth/2, pageMargin)
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(contentWidth/2, 9, "INVOICE", "", 2, "R", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range []string{
+		"Invoice #: " + invoiceNumber(inv),
+		fmt.Sprintf("Job #: %v", inv.OrderWJID),
+		"Date: " + formatDate(inv.InvoiceDate),
+		fmt.Sprintf("Revision: %v", inv.RevisionVersion),
+	} {
+		pdf.CellFormat(contentWidth/2, 4, tr(line), "", 2, "R", false, 0, "")
+	}
+	pdf.SetY(pageMargin + 35)
+
+	//
+	// Client and associate.
+	//
+
+	top := pdf.GetY()
+	writeBlock(pdf, tr, pageMargin, contentWidth/2, "Bill To", []string{
+		inv.ClientName,
+		inv.ClientAddress,
+		inv.ClientPhone,
+		inv.ClientEmail,
+	})
+	clientBottom := pdf.GetY()
+	pdf.SetY(top)
+	writeBlock(pdf, tr, pageMargin+contentWidth/2, contentWidth/2, "Associate", []string{
+		inv.AssociateName,
+		inv.AssociatePhone,
+		labelled("Tax #", inv.InvoiceAssociateTax),
+	})
+	if clientBottom > pdf.GetY() {
+		pdf.SetY(clientBottom)
+	}
+	pdf.Ln(lineHeight)
+
+	if !inv.InvoiceQuoteDate.IsZero() || inv.InvoiceQuoteDays != 0 {
+		pdf.SetFont("Helvetica", "", 9)
+		quote := "Quote date: " + formatDate(inv.InvoiceQuoteDate)
+		if inv.InvoiceQuoteDays != 0 {
+			quote += fmt.Sprintf(", valid for %v day(s)", inv.InvoiceQuoteDays)
+		}
+		if inv.InvoiceCustomersApproval != "" {
+			quote += ", approved by " + inv.InvoiceCustomersApproval
+		}
+		pdf.MultiCell(contentWidth, lineHeight, tr(quote), "", "L", false)
+		pdf.Ln(2)
+	}
+
+	//
+	// Line items.
+	//
+
+	cols := []struct {
+		title string
+		width float64
+		align string
+	}{
+		{"Qty", 15, "C"},
+		{"Description", contentWidth - 15 - 3*27, "L"},
+		{"Unit Price", 27, "R"},
+		{"Tax", 27, "R"},
+		{"Amount", 27, "R"},
+	}
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	for _, c := range cols {
+		pdf.CellFormat(c.width, 7, c.title, "1", 0, c.align, true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	for _, li := range inv.LineItems {
+		desc := li.Description
+		if li.Notes != "" {
+			desc += "\n" + li.Notes
+		}
+		descLines := pdf.SplitText(tr(desc), cols[1].width-2)
+		h := float64(len(descLines)) * lineHeight
+		if h < lineHeight {
+			h = lineHeight
+		}
+		_, pageHeight := pdf.GetPageSize()
+		if pdf.GetY()+h > pageHeight-pageMargin-10 {
+			pdf.AddPage()
+		}
+
+		pdf.SetFont("Helvetica", "", 9)
+		y := pdf.GetY()
+		pdf.CellFormat(cols[0].width, h, fmt.Sprint(li.Quantity), "1", 0, cols[0].align, false, 0, "")
+		x := pdf.GetX()
+		pdf.MultiCell(cols[1].width, lineHeight, tr(desc), "", cols[1].align, false)
+		pdf.Rect(x, y, cols[1].width, h, "D")
+		pdf.SetXY(x+cols[1].width, y)
+		pdf.CellFormat(cols[2].width, h, formatMoney(li.UnitPrice), "1", 0, cols[2].align, false, 0, "")
+		pdf.CellFormat(cols[3].width, h, formatMoney(li.Tax), "1", 0, cols[3].align, false, 0, "")
+		pdf.CellFormat(cols[4].width, h, formatMoney(li.Amount), "1", 0, cols[4].align, false, 0, "")
+		pdf.SetXY(pageMargin, y+h)
+	}
+	pdf.Ln(2)
+
+	//
+	// Totals.
+	//
+
+	labelWidth := 40.0
+	valueWidth := 27.0
+	totalsX := pageMargin + contentWidth - labelWidth - valueWidth
+	for _, t := range []struct {
+		label string
+		value float64
+		bold  bool
+	}{
+		{"Sub-total", inv.SubTotal, false},
+		{"Tax", inv.Tax, false},
+		{"Total", inv.Total, true},
+		{"Deposit", inv.Deposit, false},
+		{"Amount Due", inv.AmountDue, true},
+	} {
+		style := ""
+		if t.bold {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 9)
+		pdf.SetX(totalsX)
+		pdf.CellFormat(labelWidth, 6, t.label, "", 0, "R", false, 0, "")
+		pdf.CellFormat(valueWidth, 6, formatMoney(t.value), "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(lineHeight)
+
+	//
+	// Payment.
+	//
+
+	var methods []string
+	for _, m := range inv.PaymentMethods {
+		if label, ok := o_ds.PaymentMethodLabels[m]; ok {
+			methods = append(methods, label)
+		}
+	}
+	writeBlock(pdf, tr, pageMargin, contentWidth, "Payment", []string{
+		labelled("Amount paid", nonZeroMoney(inv.PaymentAmount)),
+		labelled("Date paid", formatDate(inv.DateClientPaidInvoice)),
+		labelled("Method(s)", strings.Join(methods, ", ")),
+	})
+
+	if inv.Notes != "" {
+		pdf.Ln(2)
+		writeBlock(pdf, tr, pageMargin, contentWidth, "Notes", strings.Split(inv.Notes, "\n"))
+	}
+
+	//
+	// Signatures.
+	//
+
+	pdf.Ln(lineHeight * 2)
+	top = pdf.GetY()
+	writeSignature(pdf, tr, pageMargin, contentWidth/2-5, "Client signature", inv.ClientSignature, time.Time{})
+	pdf.SetY(top)
+	writeSignature(pdf, tr, pageMargin+contentWidth/2+5, contentWidth/2-5, "Associate signature", inv.AssociateSignature, inv.AssociateSignDate)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeBlock(pdf *fpdf.Fpdf, tr func(string) string, x float64, w float64, title string, lines []string) {
+	pdf.SetX(x)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(w, 6, tr(title), "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		pdf.SetX(x)
+		pdf.MultiCell(w, lineHeight, tr(line), "", "L", false)
+	}
+}
+
+func writeSignature(pdf *fpdf.Fpdf, tr func(string) string, x float64, w float64, title string, signature string, signedAt time.Time) {
+	pdf.SetX(x)
+	pdf.SetFont("Helvetica", "I", 11)
+	pdf.CellFormat(w, 8, tr(signature), "B", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 8)
+	caption := title
+	if !signedAt.IsZero() {
+		caption += ", signed " + formatDate(signedAt)
+	}
+	pdf.CellFormat(w, lineHeight, tr(caption), "", 2, "L", false, 0, "")
+}
+
+func invoiceNumber(inv *o_ds.OrderInvoice) string {
+	if inv.InvoiceID != "" {
+		return inv.InvoiceID
+	}
+	return fmt.Sprint(inv.OrderWJID)
+}
+
+func labelled(label string, value string) string {
+	if value == "" {
+		return ""
+	}
+	return label + ": " + value
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+func formatMoney(v float64) string {
+	return fmt.Sprintf(currencyFmt, v)
+}
+
+func nonZeroMoney(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return formatMoney(v)
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	s3storage "github.com/over55/workery-cli/adapter/storage/s3"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	c "github.com/over55/workery-cli/config"
+)
+
+// RenderedInvoice is an invoice rendered as a PDF document.
+type RenderedInvoice struct {
+	Order     *o_ds.Order `json:"order"`
+	FileTitle string      `json:"file_title"`
+	ObjectKey string      `json:"object_key,omitempty"` // Only set once uploaded.
+	Content   []byte      `json:"-"`
+}
+
+// InvoiceController Interface for producing the documents of order invoices.
+type InvoiceController interface {
+	Render(ctx context.Context, wjid uint64, upload bool) (*RenderedInvoice, error)
+}
+
+type InvoiceControllerImpl struct {
+	Config       *c.Conf
+	Logger       *slog.Logger
+	S3           s3storage.S3Storager
+	TenantStorer tenant_ds.TenantStorer
+	OrderStorer  o_ds.OrderStorer
+}
+
+// NewController returns the controller. The S3 storager is only needed to
+// upload invoices and may be nil otherwise.
+func NewController(
+	appCfg *c.Conf,
+	loggerp *slog.Logger,
+	s3 s3storage.S3Storager,
+	tenantStorer tenant_ds.TenantStorer,
+	oStorer o_ds.OrderStorer,
+) InvoiceController {
+	s := &InvoiceControllerImpl{
+		Config:       appCfg,
+		Logger:       loggerp,
+		S3:           s3,
+		TenantStorer: tenantStorer,
+		OrderStorer:  oStorer,
+	}
+	return s
+}
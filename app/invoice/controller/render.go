@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	"github.com/over55/workery-cli/app/invoice/builder"
+	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+)
+
+// fileObjectURLDuration is how long the download link saved on an uploaded
+// invoice stays valid.
+const fileObjectURLDuration = 24 * time.Hour
+
+var (
+	ErrOrderNotFound   = errors.New("order does not exist")
+	ErrInvoiceNotFound = errors.New("order does not have an invoice")
+	ErrS3NotConfigured = errors.New("s3 storage is required to upload invoices")
+)
+
+// Render returns the current invoice of the order as a PDF document. When
+// `upload` is true the document is uploaded to S3 and the file fields of the
+// invoice are updated to point to it.
+func (impl *InvoiceControllerImpl) Render(ctx context.Context, wjid uint64, upload bool) (*RenderedInvoice, error) {
+	o, err := impl.OrderStorer.GetByWJID(ctx, wjid)
+	if err != nil {
+		return nil, err
+	}
+	if o == nil {
+		return nil, ErrOrderNotFound
+	}
+	inv := o.Invoice
+	if inv == nil {
+		return nil, ErrInvoiceNotFound
+	}
+
+	tenant, err := impl.TenantStorer.GetByID(ctx, o.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, errors.New("tenant does not exist")
+	}
+
+	content, err := builder.BuildPDF(inv, newTemplate(impl.Config.InvoiceBuilder.PDFTemplateFilePath, tenant))
+	if err != nil {
+		return nil, fmt.Errorf("failed rendering invoice: %w", err)
+	}
+	res := &RenderedInvoice{
+		Order:     o,
+		FileTitle: fmt.Sprintf("invoice-%v-rev%v.pdf", o.WJID, inv.RevisionVersion),
+		Content:   content,
+	}
+	if !upload {
+		return res, nil
+	}
+
+	if impl.S3 == nil {
+		return nil, ErrS3NotConfigured
+	}
+	objectKey := "tenant/" + o.TenantID.Hex() + "/private/invoices/" + res.FileTitle
+	if err := impl.S3.UploadContent(ctx, objectKey, content); err != nil {
+		return nil, fmt.Errorf("failed uploading invoice: %w", err)
+	}
+	url, err := impl.S3.GetDownloadablePresignedURL(ctx, objectKey, fileObjectURLDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	userID, userName, ipAddress := auditlog_ds.GetActor(ctx)
+	inv.FileObjectKey = objectKey
+	inv.FileTitle = res.FileTitle
+	inv.FileObjectURL = url
+	inv.FileObjectExpiry = now.Add(fileObjectURLDuration)
+	inv.ModifiedAt = now
+	inv.ModifiedByUserID = userID
+	inv.ModifiedByUserName = userName
+	inv.ModifiedFromIPAddress = ipAddress
+	if err := impl.OrderStorer.UpdateByID(ctx, o); err != nil {
+		return nil, err
+	}
+	res.ObjectKey = objectKey
+
+	impl.Logger.Info("invoice uploaded",
+		slog.Uint64("wjid", o.WJID),
+		slog.String("object_key", objectKey))
+	return res, nil
+}
+
+func newTemplate(backgroundFilePath string, tenant *tenant_ds.Tenant) *builder.Template {
+	var address []string
+	for _, line := range []string{
+		tenant.StreetAddress,
+		tenant.StreetAddressExtra,
+		strings.TrimSpace(strings.Join([]string{tenant.AddressLocality, tenant.AddressRegion, tenant.PostalCode}, " ")),
+	} {
+		if line != "" {
+			address = append(address, line)
+		}
+	}
+	return &builder.Template{
+		BackgroundFilePath: backgroundFilePath,
+		CompanyName:        tenant.Name,
+		CompanyAddress:     address,
+		CompanyPhone:       tenant.Telephone,
+		CompanyEmail:       tenant.Email,
+	}
+}
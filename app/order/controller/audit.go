@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	"github.com/over55/workery-cli/app/order/lifecycle"
+)
+
+// AuditStatuses returns every order of the tenant which is in a state the
+// order lifecycle does not allow, ex: orders imported from the legacy system.
+// Nothing is changed as these orders need to be reviewed by hand.
+func (impl *OrderControllerImpl) AuditStatuses(ctx context.Context, tenantID primitive.ObjectID) ([]*StatusViolation, error) {
+	oo, err := impl.OrderStorer.ListByFilter(ctx, &o_ds.OrderPaginationListFilter{
+		PageSize:  1_000_000,
+		SortField: "start_date",
+		SortOrder: o_ds.SortOrderAscending,
+		TenantID:  tenantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []*StatusViolation
+	for _, o := range oo.Results {
+		if problems := lifecycle.Check(o); len(problems) > 0 {
+			violations = append(violations, &StatusViolation{
+				OrderID:  o.ID,
+				WJID:     o.WJID,
+				Status:   o.Status,
+				Problems: problems,
+			})
+		}
+	}
+	return violations, nil
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+	cust_ds "github.com/over55/workery-cli/app/customer/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	"github.com/over55/workery-cli/app/order/ledger"
+	"github.com/over55/workery-cli/app/order/lifecycle"
+	"github.com/over55/workery-cli/app/order/matcher"
+	ss_ds "github.com/over55/workery-cli/app/skillset/datastore"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	"github.com/over55/workery-cli/app/taskitem/workflow"
+	c "github.com/over55/workery-cli/config"
+)
+
+// StatusViolation represents an order whose status could not have been
+// reached through the order lifecycle.
+type StatusViolation struct {
+	OrderID  primitive.ObjectID `json:"order_id"`
+	WJID     uint64             `json:"wjid"`
+	Status   int8               `json:"status"`
+	Problems []string           `json:"problems"`
+}
+
+// LedgerDivergence represents an order whose stored deposit, amount due or
+// balance owing amounts do not match its deposits and invoice amounts.
+type LedgerDivergence struct {
+	OrderID  primitive.ObjectID `json:"order_id"`
+	WJID     uint64             `json:"wjid"`
+	Problems []string           `json:"problems"`
+}
+
+// OrderController Interface for moving orders through their lifecycle,
+// recording their payments and finding associates to take them.
+type OrderController interface {
+	Transition(ctx context.Context, wjid uint64, status int8, closure *lifecycle.Closure) (*o_ds.Order, error)
+	AuditStatuses(ctx context.Context, tenantID primitive.ObjectID) ([]*StatusViolation, error)
+	RecordPayment(ctx context.Context, wjid uint64, d *o_ds.OrderDeposit, allowOverpayment bool) (*o_ds.Order, *ledger.Balance, error)
+	AuditLedger(ctx context.Context, tenantID primitive.ObjectID, dryRun bool) ([]*LedgerDivergence, error)
+	Match(ctx context.Context, wjid uint64, vehicleTypes []string) (*o_ds.Order, []*matcher.Candidate, error)
+}
+
+type OrderControllerImpl struct {
+	Config                 *c.Conf
+	Logger                 *slog.Logger
+	OrderStorer            o_ds.OrderStorer
+	AssociateStorer        a_ds.AssociateStorer
+	AssociateAwayLogStorer aal_ds.AssociateAwayLogStorer
+	CustomerStorer         cust_ds.CustomerStorer
+	SkillSetStorer         ss_ds.SkillSetStorer
+	Workflow               *workflow.Engine
+}
+
+func NewController(
+	appCfg *c.Conf,
+	loggerp *slog.Logger,
+	oStorer o_ds.OrderStorer,
+	aStorer a_ds.AssociateStorer,
+	aalStorer aal_ds.AssociateAwayLogStorer,
+	custStorer cust_ds.CustomerStorer,
+	ssStorer ss_ds.SkillSetStorer,
+	tiStorer ti_ds.TaskItemStorer,
+) OrderController {
+	s := &OrderControllerImpl{
+		Config:                 appCfg,
+		Logger:                 loggerp,
+		OrderStorer:            oStorer,
+		AssociateStorer:        aStorer,
+		AssociateAwayLogStorer: aalStorer,
+		CustomerStorer:         custStorer,
+		SkillSetStorer:         ssStorer,
+		Workflow:               workflow.NewEngine(appCfg, loggerp, tiStorer),
+	}
+	return s
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	"github.com/over55/workery-cli/app/order/matcher"
+)
+
+// Match returns the associates who can take the order ranked best first. An
+// associate must have every skill set of the order, the insurance linked to
+// those skill sets, the vehicle types and must not be away.
+func (impl *OrderControllerImpl) Match(ctx context.Context, wjid uint64, vehicleTypes []string) (*o_ds.Order, []*matcher.Candidate, error) {
+	o, err := impl.OrderStorer.GetByWJID(ctx, wjid)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o == nil {
+		return nil, nil, ErrOrderNotFound
+	}
+
+	req, err := impl.requirements(ctx, o)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.VehicleTypes = vehicleTypes
+
+	// Customers without coordinates make distances unknown for everyone.
+	var lat, lng float64
+	if !o.CustomerID.IsZero() {
+		cust, err := impl.CustomerStorer.GetByID(ctx, o.CustomerID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cust != nil {
+			lat, lng = cust.Latitude, cust.Longitude
+		}
+	}
+
+	res, err := impl.AssociateStorer.ListByFilter(ctx, &a_ds.AssociatePaginationListFilter{
+		PageSize:       1_000_000,
+		SortField:      "", // Candidates are ranked afterwards.
+		SortOrder:      1,
+		Status:         a_ds.AssociateStatusActive,
+		AllSkillSetIDs: req.SkillSetIDs,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	candidates := make([]*matcher.Candidate, 0)
+	for _, a := range res.Results {
+		if problems := matcher.Check(a, req, now); len(problems) > 0 {
+			impl.Logger.Debug("associate does not match",
+				slog.Any("associate_id", a.ID),
+				slog.Any("problems", problems))
+			continue
+		}
+
+		logs, err := impl.AssociateAwayLogStorer.ListByFilter(ctx, &aal_ds.AssociateAwayLogPaginationListFilter{
+			PageSize:    1_000,
+			SortField:   "start_date",
+			SortOrder:   -1,
+			AssociateID: a.ID,
+			Status:      aal_ds.AssociateAwayLogStatusActive,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if matcher.IsAway(logs.Results, now) {
+			continue
+		}
+
+		openOrders, err := impl.OrderStorer.CountOpenByAssociateID(ctx, a.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		c := &matcher.Candidate{
+			AssociateID:   a.ID,
+			AssociateName: a.Name,
+			Score:         a.Score,
+			OpenOrders:    openOrders,
+			DistanceKm:    -1,
+		}
+		if hasCoordinates(lat, lng) && hasCoordinates(a.Latitude, a.Longitude) {
+			c.DistanceKm = matcher.Distance(lat, lng, a.Latitude, a.Longitude)
+		}
+		candidates = append(candidates, c)
+	}
+
+	matcher.Rank(candidates)
+	return o, candidates, nil
+}
+
+// requirements returns the skill sets of the order with the insurance
+// requirements linked to them.
+func (impl *OrderControllerImpl) requirements(ctx context.Context, o *o_ds.Order) (*matcher.Requirements, error) {
+	req := &matcher.Requirements{}
+	seen := map[primitive.ObjectID]bool{}
+	for _, oss := range o.SkillSets {
+		req.SkillSetIDs = append(req.SkillSetIDs, oss.ID)
+
+		ss, err := impl.SkillSetStorer.GetByID(ctx, oss.ID)
+		if err != nil {
+			return nil, err
+		}
+		if ss == nil {
+			impl.Logger.Warn("skill set of order does not exist",
+				slog.Uint64("wjid", o.WJID),
+				slog.Any("skill_set_id", oss.ID))
+			continue
+		}
+		for _, ir := range ss.InsuranceRequirements {
+			if !seen[ir.ID] {
+				seen[ir.ID] = true
+				req.InsuranceRequirementIDs = append(req.InsuranceRequirementIDs, ir.ID)
+			}
+		}
+	}
+	return req, nil
+}
+
+func hasCoordinates(lat, lng float64) bool {
+	return lat != 0 || lng != 0
+}
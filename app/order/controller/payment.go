@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	"github.com/over55/workery-cli/app/order/ledger"
+)
+
+// RecordPayment appends the deposit to the order, recalculates the amounts
+// derived from its deposits and saves it. Only the amount, method, paid to
+// and paid at fields of the deposit need to be set. Payments over the amount
+// due are refused unless `allowOverpayment` is set.
+func (impl *OrderControllerImpl) RecordPayment(ctx context.Context, wjid uint64, d *o_ds.OrderDeposit, allowOverpayment bool) (*o_ds.Order, *ledger.Balance, error) {
+	o, err := impl.OrderStorer.GetByWJID(ctx, wjid)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o == nil {
+		return nil, nil, ErrOrderNotFound
+	}
+
+	now := time.Now()
+	userID, userName, ipAddress := auditlog_ds.GetActor(ctx)
+	d.ID = primitive.NewObjectID()
+	d.OrderID = o.ID
+	d.OrderWJID = o.WJID
+	d.OrderTenantIDWithWJID = o.TenantIDWithWJID
+	d.TenantID = o.TenantID
+	if d.PaidAt.IsZero() {
+		d.PaidAt = now
+	}
+	if d.Currency == "" {
+		d.Currency = o.Currency
+	}
+	d.Status = o_ds.OrderDepositStatusActive
+	d.CreatedAt = now
+	d.CreatedByUserID = userID
+	d.CreatedByUserName = userName
+	d.CreatedFromIPAddress = ipAddress
+	d.ModifiedAt = now
+	d.ModifiedByUserID = userID
+	d.ModifiedByUserName = userName
+	d.ModifiedFromIPAddress = ipAddress
+
+	b, err := ledger.RecordDeposit(o, d, allowOverpayment)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	o.ModifiedAt = now
+	o.ModifiedByUserID = userID
+	o.ModifiedByUserName = userName
+	o.ModifiedFromIPAddress = ipAddress
+	if err := impl.OrderStorer.UpdateByID(ctx, o); err != nil {
+		return nil, nil, err
+	}
+
+	impl.Logger.Info("payment recorded",
+		slog.Uint64("wjid", o.WJID),
+		slog.Float64("amount", d.Amount),
+		slog.Float64("amount_due", b.AmountDue))
+	return o, b, nil
+}
+
+// AuditLedger returns every order of the tenant whose stored amounts do not
+// match its deposits and invoice amounts. When `dryRun` is false the stored
+// amounts are recalculated as well.
+func (impl *OrderControllerImpl) AuditLedger(ctx context.Context, tenantID primitive.ObjectID, dryRun bool) ([]*LedgerDivergence, error) {
+	oo, err := impl.OrderStorer.ListByFilter(ctx, &o_ds.OrderPaginationListFilter{
+		PageSize:  1_000_000,
+		SortField: "start_date",
+		SortOrder: o_ds.SortOrderAscending,
+		TenantID:  tenantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var divergences []*LedgerDivergence
+	for _, o := range oo.Results {
+		problems := ledger.Check(o)
+		if len(problems) == 0 {
+			continue
+		}
+		divergences = append(divergences, &LedgerDivergence{
+			OrderID:  o.ID,
+			WJID:     o.WJID,
+			Problems: problems,
+		})
+		if dryRun {
+			continue
+		}
+		ledger.Apply(o)
+		if err := impl.OrderStorer.UpdateByID(ctx, o); err != nil {
+			return nil, fmt.Errorf("failed recalculating order %v: %w", o.WJID, err)
+		}
+	}
+	return divergences, nil
+}
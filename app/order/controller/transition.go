@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	"github.com/over55/workery-cli/app/order/lifecycle"
+)
+
+var ErrOrderNotFound = errors.New("order does not exist")
+
+// Transition moves the order to the status, enforcing the order lifecycle,
+// and saves it.
+func (impl *OrderControllerImpl) Transition(ctx context.Context, wjid uint64, status int8, closure *lifecycle.Closure) (*o_ds.Order, error) {
+	o, err := impl.OrderStorer.GetByWJID(ctx, wjid)
+	if err != nil {
+		return nil, err
+	}
+	if o == nil {
+		return nil, ErrOrderNotFound
+	}
+
+	from := o.Status
+	now := time.Now()
+	if err := lifecycle.Transition(o, status, closure, now); err != nil {
+		return nil, err
+	}
+
+	// Create the task item the order is now waiting on.
+	if _, err := impl.Workflow.Apply(ctx, o, now, false); err != nil {
+		return nil, err
+	}
+
+	userID, userName, ipAddress := auditlog_ds.GetActor(ctx)
+	o.ModifiedAt = now
+	o.ModifiedByUserID = userID
+	o.ModifiedByUserName = userName
+	o.ModifiedFromIPAddress = ipAddress
+
+	if err := impl.OrderStorer.UpdateByID(ctx, o); err != nil {
+		return nil, err
+	}
+
+	impl.Logger.Info("order status changed",
+		slog.Uint64("wjid", o.WJID),
+		slog.String("from", o_ds.OrderStatusLabels[from]),
+		slog.String("to", o_ds.OrderStatusLabels[o.Status]))
+	return o, nil
+}
@@ -19,6 +19,9 @@ const (
 	OrderInvoicePaidToAssociate    = 1
 	OrderInvoicePaidToOrganization = 2
 
+	OrderDepositStatusActive   = 1
+	OrderDepositStatusArchived = 2
+
 	OrderUnassignedReasonOther                        = 1
 	OrderUnassignedReasonAossicateNotAFit             = 2
 	OrderUnassignedReasonJobBiggerThanThought         = 3
@@ -2,6 +2,7 @@ package datastore
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"time"
 
@@ -10,7 +11,7 @@ import (
 )
 
 func (impl OrderStorerImpl) CountByFilter(ctx context.Context, f *OrderListFilter) (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Create the filter based on the cursor
@@ -27,6 +28,11 @@ func (impl OrderStorerImpl) CountByFilter(ctx context.Context, f *OrderListFilte
 	impl.Logger.Debug("counting w/ filter:",
 		slog.Any("filter", filter))
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Use the CountDocuments method to count the matching documents.
 	count, err := impl.Collection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -36,21 +42,33 @@ func (impl OrderStorerImpl) CountByFilter(ctx context.Context, f *OrderListFilte
 	return count, nil
 }
 
-func (impl OrderStorerImpl) CountByAssociateID(ctx context.Context, tenantID primitive.ObjectID) (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+// ErrMissingAssociateID is returned when counting the orders of an associate
+// without giving which associate.
+var ErrMissingAssociateID = errors.New("associate id is required")
+
+// CountOpenByAssociateID returns the number of open orders, meaning new,
+// pending, in progress or ongoing, which are assigned to the associate.
+func (impl OrderStorerImpl) CountOpenByAssociateID(ctx context.Context, associateID primitive.ObjectID) (int64, error) {
+	// Without an associate we would count the open orders of everyone.
+	if associateID.IsZero() {
+		return 0, ErrMissingAssociateID
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Create the filter based on the cursor
-	filter := bson.M{}
-
-	// Add filter conditions to the filter
-	if !tenantID.IsZero() {
-		filter["associate_id"] = tenantID
+	filter := bson.M{
+		"status":       bson.M{"$in": []int8{OrderStatusNew, OrderStatusPending, OrderStatusInProgress, OrderStatusOngoing}},
+		"associate_id": associateID,
 	}
 
 	impl.Logger.Debug("counting w/ filter:",
 		slog.Any("filter", filter))
 
+	// Exclude documents in the trash.
+	filter["deleted_at"] = bson.M{"$exists": false}
+
 	// Use the CountDocuments method to count the matching documents.
 	count, err := impl.Collection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -61,7 +79,7 @@ func (impl OrderStorerImpl) CountByAssociateID(ctx context.Context, tenantID pri
 }
 
 func (impl OrderStorerImpl) CountByTenantID(ctx context.Context, tenantID primitive.ObjectID) (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Create the filter based on the cursor
@@ -75,6 +93,9 @@ func (impl OrderStorerImpl) CountByTenantID(ctx context.Context, tenantID primit
 	impl.Logger.Debug("counting w/ filter:",
 		slog.Any("filter", filter))
 
+	// Exclude documents in the trash.
+	filter["deleted_at"] = bson.M{"$exists": false}
+
 	// Use the CountDocuments method to count the matching documents.
 	count, err := impl.Collection.CountDocuments(ctx, filter)
 	if err != nil {
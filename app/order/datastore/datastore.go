@@ -10,6 +10,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 	c "github.com/over55/workery-cli/config"
 )
 
@@ -137,6 +139,9 @@ type Order struct {
 	Deposits                              []*OrderDeposit              `bson:"deposits" json:"deposits,omitempty"`
 	Invoice                               *OrderInvoice                `bson:"invoice" json:"invoice,omitempty"`
 	PastInvoices                          []*OrderInvoice              `bson:"past_invoices" json:"past_invoices,omitempty"`
+	DeletedAt                             time.Time                    `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedByUserID                       primitive.ObjectID           `bson:"deleted_by_user_id,omitempty" json:"deleted_by_user_id,omitempty"`
+	DeletedByUserName                     string                       `bson:"deleted_by_user_name,omitempty" json:"deleted_by_user_name,omitempty"`
 }
 
 type OrderComment struct {
@@ -179,72 +184,12 @@ type OrderInvoice struct {
 	ClientName               string             `bson:"client_name" json:"client_name"`
 	ClientPhone              string             `bson:"client_phone" json:"client_phone"`
 	ClientEmail              string             `bson:"client_email" json:"client_email"`
-	Line01Qty                int64              `bson:"line_01_qty" json:"line_01_qty"`
-	Line01Desc               string             `bson:"line_01_desc" json:"line_01_desc"`
-	Line01Price              float64            `bson:"line_01_price" json:"line_01_price"`
-	Line01Amount             float64            `bson:"line_01_amount" json:"line_01_amount"`
-	Line02Qty                int64              `bson:"line_02_qty" json:"line_02_qty"` // Make `int8`
-	Line02Desc               string             `bson:"line_02_desc" json:"line_02_desc"`
-	Line02Price              float64            `bson:"line_02_price" json:"line_02_price"`
-	Line02Amount             float64            `bson:"line_02_amount" json:"line_02_amount"`
-	Line03Qty                int64              `bson:"line_03_qty" json:"line_03_qty"` // Make `int8`
-	Line03Desc               string             `bson:"line_03_desc" json:"line_03_desc"`
-	Line03Price              float64            `bson:"line_03_price" json:"line_03_price"`
-	Line03Amount             float64            `bson:"line_03_amount" json:"line_03_amount"`
-	Line04Qty                int64              `bson:"line_04_qty" json:"line_04_qty"` // Make `int8`
-	Line04Desc               string             `bson:"line_04_desc" json:"line_04_desc"`
-	Line04Price              float64            `bson:"line_04_price" json:"line_04_price"`
-	Line04Amount             float64            `bson:"line_04_amount" json:"line_04_amount"`
-	Line05Qty                int64              `bson:"line_05_qty" json:"line_05_qty"` // Make `int8`
-	Line05Desc               string             `bson:"line_05_desc" json:"line_05_desc"`
-	Line05Price              float64            `bson:"line_05_price" json:"line_05_price"`
-	Line05Amount             float64            `bson:"line_05_amount" json:"line_05_amount"`
-	Line06Qty                int64              `bson:"line_06_qty" json:"line_06_qty"` // Make `int8`
-	Line06Desc               string             `bson:"line_06_desc" json:"line_06_desc"`
-	Line06Price              float64            `bson:"line_06_price" json:"line_06_price"`
-	Line06Amount             float64            `bson:"line_06_amount" json:"line_06_amount"`
-	Line07Qty                int64              `bson:"line_07_qty" json:"line_07_qty"` // Make `int8`
-	Line07Desc               string             `bson:"line_07_desc" json:"line_07_desc"`
-	Line07Price              float64            `bson:"line_07_price" json:"line_07_price"`
-	Line07Amount             float64            `bson:"line_07_amount" json:"line_07_amount"`
-	Line08Qty                int64              `bson:"line_08_qty" json:"line_08_qty"` // Make `int8`
-	Line08Desc               string             `bson:"line_08_desc" json:"line_08_desc"`
-	Line08Price              float64            `bson:"line_08_price" json:"line_08_price"`
-	Line08Amount             float64            `bson:"line_08_amount" json:"line_08_amount"`
-	Line09Qty                int64              `bson:"line_09_qty" json:"line_09_qty"` // Make `int8`
-	Line09Desc               string             `bson:"line_09_desc" json:"line_09_desc"`
-	Line09Price              float64            `bson:"line_09_price" json:"line_09_price"`
-	Line09Amount             float64            `bson:"line_09_amount" json:"line_09_amount"`
-	Line10Qty                int64              `bson:"line_10_qty" json:"line_10_qty"` // Make `int8`
-	Line10Desc               string             `bson:"line_10_desc" json:"line_10_desc"`
-	Line10Price              float64            `bson:"line_10_price" json:"line_10_price"`
-	Line10Amount             float64            `bson:"line_10_amount" json:"line_10_amount"`
-	Line11Qty                int64              `bson:"line_11_qty" json:"line_11_qty"` // Make `int8`
-	Line11Desc               string             `bson:"line_11_desc" json:"line_11_desc"`
-	Line11Price              float64            `bson:"line_11_price" json:"line_11_price"`
-	Line11Amount             float64            `bson:"line_11_amount" json:"line_11_amount"`
-	Line12Qty                int64              `bson:"line_12_qty" json:"line_12_qty"` // Make `int8`
-	Line12Desc               string             `bson:"line_12_desc" json:"line_12_desc"`
-	Line12Price              float64            `bson:"line_12_price" json:"line_12_price"`
-	Line12Amount             float64            `bson:"line_12_amount" json:"line_12_amount"`
-	Line13Qty                int64              `bson:"line_13_qty" json:"line_13_qty"` // Make `int8`
-	Line13Desc               string             `bson:"line_13_desc" json:"line_13_desc"`
-	Line13Price              float64            `bson:"line_13_price" json:"line_13_price"`
-	Line13Amount             float64            `bson:"line_13_amount" json:"line_13_amount"`
-	Line14Qty                int64              `bson:"line_14_qty" json:"line_14_qty"` // Make `int8`
-	Line14Desc               string             `bson:"line_14_desc" json:"line_14_desc"`
-	Line14Price              float64            `bson:"line_14_price" json:"line_14_price"`
-	Line14Amount             float64            `bson:"line_14_amount" json:"line_14_amount"`
-	Line15Qty                int64              `bson:"line_15_qty" json:"line_15_qty"` // Make `int8`
-	Line15Desc               string             `bson:"line_15_desc" json:"line_15_desc"`
-	Line15Price              float64            `bson:"line_15_price" json:"line_15_price"`
-	Line15Amount             float64            `bson:"line_15_amount" json:"line_15_amount"`
+	LineItems                []*InvoiceLineItem `bson:"line_items" json:"line_items"`
 	InvoiceQuoteDays         int8               `bson:"invoice_quote_days" json:"invoice_quote_days"`
 	InvoiceAssociateTax      string             `bson:"invoice_associate_tax" json:"invoice_associate_tax"`
 	InvoiceQuoteDate         time.Time          `bson:"invoice_quote_date" json:"invoice_quote_date"`
 	InvoiceCustomersApproval string             `bson:"invoice_customers_approval" json:"invoice_customers_approval"`
-	Line01Notes              string             `bson:"line_01_notes" json:"line_01_notes"`
-	Line02Notes              string             `bson:"line_02_notes" json:"line_02_notes"`
+	Notes                    string             `bson:"notes" json:"notes"`
 	TotalLabour              float64            `bson:"total_labour" json:"total_labour"`
 	TotalMaterials           float64            `bson:"total_materials" json:"total_materials"`
 	OtherCosts               float64            `bson:"other_costs" json:"other_costs"`
@@ -339,6 +284,9 @@ type OrderListFilter struct {
 	ExcludeArchived  bool
 	SearchText       string
 	ModifiedByUserID primitive.ObjectID
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 type OrderListResult struct {
@@ -367,21 +315,31 @@ type OrderStorer interface {
 	ListByFilter(ctx context.Context, f *OrderPaginationListFilter) (*OrderPaginationListResult, error)
 	LiteListByFilter(ctx context.Context, f *OrderPaginationListFilter) (*OrderPaginationLiteListResult, error)
 	ListByCustomerID(ctx context.Context, customerID primitive.ObjectID) (*OrderPaginationListResult, error)
+	ListByCustomerIDWithDeleted(ctx context.Context, customerID primitive.ObjectID) (*OrderPaginationListResult, error)
 	ListByAssociateID(ctx context.Context, associateID primitive.ObjectID) (*OrderPaginationListResult, error)
+	ListByAssociateIDWithDeleted(ctx context.Context, associateID primitive.ObjectID) (*OrderPaginationListResult, error)
 	ListByServiceFeeID(ctx context.Context, serviceFeeID primitive.ObjectID) (*OrderPaginationListResult, error)
+	ListBySkillSetID(ctx context.Context, skillSetID primitive.ObjectID) (*OrderPaginationListResult, error)
+	ListByTagID(ctx context.Context, tagID primitive.ObjectID) (*OrderPaginationListResult, error)
 	// ListAsSelectOptionByFilter(ctx context.Context, f *OrderListFilter) ([]*OrderAsSelectOption, error)
-	// DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	PermanentlyDeleteByID(ctx context.Context, id primitive.ObjectID) error
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	ListDeletedBefore(ctx context.Context, deletedBefore time.Time) ([]*Order, error)
 	CountByFilter(ctx context.Context, f *OrderListFilter) (int64, error)
-	CountByAssociateID(ctx context.Context, associateID primitive.ObjectID) (int64, error)
+	CountOpenByAssociateID(ctx context.Context, associateID primitive.ObjectID) (int64, error)
 	CountByTenantID(ctx context.Context, tenantID primitive.ObjectID) (int64, error)
-	PermanentlyDeleteAllByCustomerID(ctx context.Context, customerID primitive.ObjectID) error
-	PermanentlyDeleteAllByAssociateID(ctx context.Context, associateID primitive.ObjectID) error
+	DeleteAllByCustomerID(ctx context.Context, customerID primitive.ObjectID) error
+	DeleteAllByAssociateID(ctx context.Context, associateID primitive.ObjectID) error
+	ForTenant(tenantID primitive.ObjectID) OrderStorer
+	AllTenants() OrderStorer
 }
 
 type OrderStorerImpl struct {
-	Logger     *slog.Logger
-	DbClient   *mongo.Client
-	Collection *mongo.Collection
+	Logger         *slog.Logger
+	DbClient       *mongo.Client
+	Collection     *mongodb.TenantCollection
+	AuditLogStorer auditlog_ds.AuditLogStorer
 }
 
 func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) OrderStorer {
@@ -399,6 +357,7 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Or
 	// }
 
 	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
 		{Keys: bson.D{{Key: "wjid", Value: 1}}},
 		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
 		{Keys: bson.D{{Key: "wjid", Value: -1}}},
@@ -452,9 +411,10 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Or
 	}
 
 	s := &OrderStorerImpl{
-		Logger:     loggerp,
-		DbClient:   client,
-		Collection: uc,
+		Logger:         loggerp,
+		DbClient:       client,
+		Collection:     mongodb.NewTenantCollection(uc),
+		AuditLogStorer: auditlog_ds.NewDatastore(appCfg, loggerp, client),
 	}
 	return s
 }
@@ -0,0 +1,127 @@
+package datastore
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrInvoiceTotalsMismatch is returned when the totals stored on an invoice do
+// not match the totals computed from its line items.
+var ErrInvoiceTotalsMismatch = errors.New("invoice totals do not match its line items")
+
+// InvoiceLineItem is a single billable line of an invoice.
+type InvoiceLineItem struct {
+	Quantity    int64   `bson:"quantity" json:"quantity"`
+	Description string  `bson:"description" json:"description"`
+	UnitPrice   float64 `bson:"unit_price" json:"unit_price"`
+	Amount      float64 `bson:"amount" json:"amount"` // Amount is `Quantity` times `UnitPrice` before tax.
+	Tax         float64 `bson:"tax" json:"tax"`
+	Notes       string  `bson:"notes" json:"notes"`
+}
+
+// IsEmpty returns true if the line has nothing on it.
+func (li *InvoiceLineItem) IsEmpty() bool {
+	return li.Quantity == 0 && strings.TrimSpace(li.Description) == "" && li.UnitPrice == 0 && li.Amount == 0 && li.Tax == 0 && li.Notes == ""
+}
+
+// CompactInvoiceLineItems returns the line items without the empty ones.
+func CompactInvoiceLineItems(items []*InvoiceLineItem) []*InvoiceLineItem {
+	compacted := make([]*InvoiceLineItem, 0, len(items))
+	for _, li := range items {
+		if li != nil && !li.IsEmpty() {
+			compacted = append(compacted, li)
+		}
+	}
+	return compacted
+}
+
+// SpreadTax splits a single tax amount across the line items in proportion
+// to their amounts. This is used for invoices which only recorded the total
+// tax. Any rounding difference is added to the last line so the line taxes
+// always add up to `tax`.
+func (oi *OrderInvoice) SpreadTax(tax float64) {
+	if len(oi.LineItems) == 0 {
+		return
+	}
+	var subTotal float64
+	for _, li := range oi.LineItems {
+		subTotal += li.Amount
+	}
+
+	var allocated float64
+	for i, li := range oi.LineItems {
+		switch {
+		case i == len(oi.LineItems)-1:
+			li.Tax = RoundToCents(tax - allocated)
+		case subTotal == 0:
+			li.Tax = 0
+		default:
+			li.Tax = RoundToCents(tax * li.Amount / subTotal)
+		}
+		allocated += li.Tax
+	}
+}
+
+// computedTotals returns the `SubTotal`, `Tax`, `Total` and `AmountDue` the
+// line items and deposit of the invoice add up to.
+func (oi *OrderInvoice) computedTotals() (subTotal float64, tax float64, total float64, amountDue float64) {
+	for _, li := range oi.LineItems {
+		subTotal += li.Amount
+		tax += li.Tax
+	}
+	subTotal = RoundToCents(subTotal)
+	tax = RoundToCents(tax)
+	total = RoundToCents(subTotal + tax)
+	amountDue = RoundToCents(total - oi.Deposit)
+	return subTotal, tax, total, amountDue
+}
+
+// ComputeTotals sets the `SubTotal`, `Tax`, `Total` and `AmountDue` of the
+// invoice from its line items and deposit. Call it whenever the line items
+// change.
+func (oi *OrderInvoice) ComputeTotals() {
+	oi.SubTotal, oi.Tax, oi.Total, oi.AmountDue = oi.computedTotals()
+}
+
+// ValidateTotals returns an error listing every stored amount which does not
+// match the amount computed from the line items.
+func (oi *OrderInvoice) ValidateTotals() error {
+	var problems []string
+	for i, li := range oi.LineItems {
+		if want := RoundToCents(float64(li.Quantity) * li.UnitPrice); !centsEqual(li.Amount, want) {
+			problems = append(problems, fmt.Sprintf("line %v amount is %.2f, expected %.2f", i+1, li.Amount, want))
+		}
+	}
+
+	subTotal, tax, total, amountDue := oi.computedTotals()
+	for _, t := range []struct {
+		name   string
+		stored float64
+		want   float64
+	}{
+		{"sub total", oi.SubTotal, subTotal},
+		{"tax", oi.Tax, tax},
+		{"total", oi.Total, total},
+		{"amount due", oi.AmountDue, amountDue},
+	} {
+		if !centsEqual(t.stored, t.want) {
+			problems = append(problems, fmt.Sprintf("%v is %.2f, expected %.2f", t.name, t.stored, t.want))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %v", ErrInvoiceTotalsMismatch, strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// RoundToCents rounds the amount to the nearest cent.
+func RoundToCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func centsEqual(a float64, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
@@ -74,6 +74,9 @@ type OrderPaginationListFilter struct {
 	CompletionDateGTE time.Time
 	CompletionDateLT  time.Time
 	CompletionDateLTE time.Time
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 // OrderPaginationLiteListResult represents the paginated list results for
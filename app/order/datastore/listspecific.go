@@ -21,6 +21,24 @@ func (impl OrderStorerImpl) ListByCustomerID(ctx context.Context, customerID pri
 	return res, nil
 }
 
+// ListByCustomerIDWithDeleted does the same as `ListByCustomerID` along
+// with the orders in the trash.
+func (impl OrderStorerImpl) ListByCustomerIDWithDeleted(ctx context.Context, customerID primitive.ObjectID) (*OrderPaginationListResult, error) {
+	f := &OrderPaginationListFilter{
+		Cursor:         "",
+		PageSize:       1_000_00,
+		SortField:      "", // Setting this empty to ignore any sorting.
+		SortOrder:      SortOrderAscending,
+		CustomerID:     customerID,
+		IncludeDeleted: true,
+	}
+	res, err := impl.ListByFilter(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (impl OrderStorerImpl) ListByAssociateID(ctx context.Context, associateID primitive.ObjectID) (*OrderPaginationListResult, error) {
 	f := &OrderPaginationListFilter{
 		Cursor:      "",
@@ -36,6 +54,24 @@ func (impl OrderStorerImpl) ListByAssociateID(ctx context.Context, associateID p
 	return res, nil
 }
 
+// ListByAssociateIDWithDeleted does the same as `ListByAssociateID` along
+// with the orders in the trash.
+func (impl OrderStorerImpl) ListByAssociateIDWithDeleted(ctx context.Context, associateID primitive.ObjectID) (*OrderPaginationListResult, error) {
+	f := &OrderPaginationListFilter{
+		Cursor:         "",
+		PageSize:       1_000_00,
+		SortField:      "", // Setting this empty to ignore any sorting.
+		SortOrder:      SortOrderAscending,
+		AssociateID:    associateID,
+		IncludeDeleted: true,
+	}
+	res, err := impl.ListByFilter(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (impl OrderStorerImpl) ListByServiceFeeID(ctx context.Context, serviceFeeID primitive.ObjectID) (*OrderPaginationListResult, error) {
 	f := &OrderPaginationListFilter{
 		Cursor:              "",
@@ -50,3 +86,33 @@ func (impl OrderStorerImpl) ListByServiceFeeID(ctx context.Context, serviceFeeID
 	}
 	return res, nil
 }
+
+func (impl OrderStorerImpl) ListBySkillSetID(ctx context.Context, skillSetID primitive.ObjectID) (*OrderPaginationListResult, error) {
+	f := &OrderPaginationListFilter{
+		Cursor:        "",
+		PageSize:      1_000_00,
+		SortField:     "", // Setting this empty to ignore any sorting.
+		SortOrder:     SortOrderAscending,
+		InSkillSetIDs: []primitive.ObjectID{skillSetID},
+	}
+	res, err := impl.ListByFilter(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (impl OrderStorerImpl) ListByTagID(ctx context.Context, tagID primitive.ObjectID) (*OrderPaginationListResult, error) {
+	f := &OrderPaginationListFilter{
+		Cursor:    "",
+		PageSize:  1_000_00,
+		SortField: "", // Setting this empty to ignore any sorting.
+		SortOrder: SortOrderAscending,
+		InTagIDs:  []primitive.ObjectID{tagID},
+	}
+	res, err := impl.ListByFilter(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
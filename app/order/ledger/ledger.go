@@ -0,0 +1,167 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+)
+
+var (
+	ErrInvalidAmount        = errors.New("payment amount must be greater than zero")
+	ErrUnknownPaymentMethod = errors.New("unknown payment method")
+	ErrUnknownPaidTo        = errors.New("unknown paid to")
+	ErrOverpayment          = errors.New("payment is more than the amount due")
+	ErrNotInvoiced          = errors.New("order has no invoice total to pay against")
+)
+
+// Balance holds the amounts of an order which are derived from its deposits
+// and invoice amounts.
+type Balance struct {
+	// DepositsPaid is the sum of every active deposit.
+	DepositsPaid float64 `json:"deposits_paid"`
+
+	// AmountDue is what the customer still owes on the invoice total.
+	AmountDue float64 `json:"amount_due"`
+
+	// BalanceOwing is the part of the service fee the associate still owes
+	// the organization.
+	BalanceOwing float64 `json:"balance_owing"`
+}
+
+// Compute returns the balance of the order.
+func Compute(o *o_ds.Order) *Balance {
+	var paid float64
+	for _, d := range o.Deposits {
+		if d != nil && d.Status != o_ds.OrderDepositStatusArchived {
+			paid += d.Amount
+		}
+	}
+	paid = o_ds.RoundToCents(paid)
+	return &Balance{
+		DepositsPaid: paid,
+		AmountDue:    o_ds.RoundToCents(o.InvoiceTotalAmount - paid),
+		BalanceOwing: o_ds.RoundToCents(o.InvoiceServiceFeeAmount - o.InvoiceActualServiceFeeAmountPaid),
+	}
+}
+
+// Apply saves the computed balance into the stored amounts of the order and
+// into the deposit and amount due of its invoice, if it has one.
+func Apply(o *o_ds.Order) *Balance {
+	b := Compute(o)
+	o.InvoiceDepositAmount = b.DepositsPaid
+	o.InvoiceAmountDue = b.AmountDue
+	o.InvoiceBalanceOwingAmount = b.BalanceOwing
+	if o.Invoice != nil {
+		o.Invoice.Deposit = b.DepositsPaid
+		o.Invoice.AmountDue = invoiceAmountDue(o.Invoice, b)
+	}
+	return b
+}
+
+// invoiceAmountDue returns what is still owed on the total of the invoice.
+func invoiceAmountDue(inv *o_ds.OrderInvoice, b *Balance) float64 {
+	return o_ds.RoundToCents(inv.Total - b.DepositsPaid)
+}
+
+// ServiceFee returns the fee the associate owes the organization for the
+// order, charged as a percentage of the invoice labour and material amounts.
+func ServiceFee(o *o_ds.Order, percentage float64) float64 {
+	return o_ds.RoundToCents((o.InvoiceLabourAmount + o.InvoiceMaterialAmount) * percentage / 100)
+}
+
+// ApplyServiceFee sets the service fee amount of the order at the percentage
+// along with the part of it the associate still owes. The deposit and amount
+// due of the customer are left as they are.
+func ApplyServiceFee(o *o_ds.Order, percentage float64) {
+	o.InvoiceServiceFeeAmount = ServiceFee(o, percentage)
+	o.InvoiceBalanceOwingAmount = o_ds.RoundToCents(o.InvoiceServiceFeeAmount - o.InvoiceActualServiceFeeAmountPaid)
+}
+
+// Check returns every stored amount of the order which does not match the
+// amount computed from its deposits and invoice amounts.
+func Check(o *o_ds.Order) []string {
+	b := Compute(o)
+
+	type check struct {
+		name   string
+		stored float64
+		want   float64
+	}
+	checks := []check{
+		{"deposit amount", o.InvoiceDepositAmount, b.DepositsPaid},
+		{"amount due", o.InvoiceAmountDue, b.AmountDue},
+		{"balance owing", o.InvoiceBalanceOwingAmount, b.BalanceOwing},
+	}
+	if o.Invoice != nil {
+		checks = append(checks, []check{
+			{"invoice deposit", o.Invoice.Deposit, b.DepositsPaid},
+			{"invoice amount due", o.Invoice.AmountDue, invoiceAmountDue(o.Invoice, b)},
+		}...)
+	}
+
+	var problems []string
+	for _, t := range checks {
+		if math.Abs(t.stored-t.want) >= 0.005 {
+			problems = append(problems, fmt.Sprintf("%v is %.2f, expected %.2f", t.name, t.stored, t.want))
+		}
+	}
+	return problems
+}
+
+// RecordDeposit validates the deposit, appends it to the order and updates
+// the stored amounts. A deposit more than the amount due, or on an order
+// with no invoice total yet, is refused unless `allowOverpayment` is set.
+// The order is left untouched if an error is returned.
+func RecordDeposit(o *o_ds.Order, d *o_ds.OrderDeposit, allowOverpayment bool) (*Balance, error) {
+	if d.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if _, ok := o_ds.PaymentMethodLabels[d.DepositMethod]; !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownPaymentMethod, d.DepositMethod)
+	}
+	if _, ok := o_ds.OrderOrganizationInvoicePaidToLabels[d.PaidTo]; !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownPaidTo, d.PaidTo)
+	}
+	if !allowOverpayment {
+		if o.InvoiceTotalAmount <= 0 {
+			return nil, ErrNotInvoiced
+		}
+		if b := Compute(o); o_ds.RoundToCents(d.Amount) > b.AmountDue {
+			return nil, fmt.Errorf("%w: %.2f is due", ErrOverpayment, b.AmountDue)
+		}
+	}
+
+	o.Deposits = append(o.Deposits, d)
+	return Apply(o), nil
+}
+
+// ParsePaymentMethod returns the payment method matching either the number
+// or the label (case insensitive), ex: `2` or `cash`.
+func ParsePaymentMethod(s string) (int8, error) {
+	if v, ok := parseLabel(s, o_ds.PaymentMethodLabels); ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("%w: %v", ErrUnknownPaymentMethod, s)
+}
+
+// ParsePaidTo returns who was paid matching either the number or the label
+// (case insensitive), ex: `1` or `associate`.
+func ParsePaidTo(s string) (int8, error) {
+	if v, ok := parseLabel(s, o_ds.OrderOrganizationInvoicePaidToLabels); ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("%w: %v", ErrUnknownPaidTo, s)
+}
+
+func parseLabel(s string, labels map[int8]string) (int8, bool) {
+	s = strings.TrimSpace(s)
+	for v, label := range labels {
+		if strings.EqualFold(s, label) || s == fmt.Sprint(v) {
+			return v, true
+		}
+	}
+	return 0, false
+}
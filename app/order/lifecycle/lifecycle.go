@@ -0,0 +1,237 @@
+package lifecycle
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+)
+
+const (
+	// ClosingReasonOther is the closing reason which must be explained with
+	// the `ClosingReasonOther` text.
+	ClosingReasonOther = 1
+)
+
+var (
+	ErrUnknownStatus         = errors.New("unknown order status")
+	ErrTransitionNotAllowed  = errors.New("order status transition is not allowed")
+	ErrClosingReasonRequired = errors.New("closing reason is required")
+	ErrUnknownClosingReason  = errors.New("unknown closing reason")
+	ErrClosingReasonOther    = errors.New("closing reason `other` must be explained")
+	ErrAssociateRequired     = errors.New("order must be assigned to an associate")
+)
+
+// transitions lists the statuses an order may move to from each status. An
+// order is worked on from `New` through to `CompletedAndPaid`, ongoing orders
+// use `Ongoing` in place of `InProgress`, and `Declined` and `Cancelled` are
+// the exits for orders which will never be completed. Finished orders may
+// only be archived, and archived orders are final.
+var transitions = map[int8][]int8{
+	o_ds.OrderStatusNew: {
+		o_ds.OrderStatusPending,
+		o_ds.OrderStatusDeclined,
+		o_ds.OrderStatusCancelled,
+	},
+	o_ds.OrderStatusPending: {
+		o_ds.OrderStatusInProgress,
+		o_ds.OrderStatusOngoing,
+		o_ds.OrderStatusDeclined,
+		o_ds.OrderStatusCancelled,
+	},
+	o_ds.OrderStatusInProgress: {
+		o_ds.OrderStatusCompletedButUnpaid,
+		o_ds.OrderStatusCompletedAndPaid,
+		o_ds.OrderStatusCancelled,
+	},
+	o_ds.OrderStatusOngoing: {
+		o_ds.OrderStatusCompletedButUnpaid,
+		o_ds.OrderStatusCompletedAndPaid,
+		o_ds.OrderStatusCancelled,
+	},
+	o_ds.OrderStatusCompletedButUnpaid: {
+		o_ds.OrderStatusCompletedAndPaid,
+	},
+	o_ds.OrderStatusCompletedAndPaid: {
+		o_ds.OrderStatusArchived,
+	},
+	o_ds.OrderStatusDeclined: {
+		o_ds.OrderStatusArchived,
+	},
+	o_ds.OrderStatusCancelled: {
+		o_ds.OrderStatusArchived,
+	},
+	o_ds.OrderStatusArchived: {},
+}
+
+// Closure holds the details recorded when an order is declined or cancelled.
+type Closure struct {
+	Reason        int8
+	ReasonOther   string
+	ReasonComment string
+}
+
+// Allowed returns the statuses an order in the `from` status may move to.
+func Allowed(from int8) []int8 {
+	return transitions[from]
+}
+
+// CanTransition returns true if an order may move between the statuses.
+func CanTransition(from int8, to int8) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsClosed returns true if the order was declined or cancelled.
+func IsClosed(status int8) bool {
+	return status == o_ds.OrderStatusDeclined || status == o_ds.OrderStatusCancelled
+}
+
+// IsCompleted returns true if the work on the order is done.
+func IsCompleted(status int8) bool {
+	return status == o_ds.OrderStatusCompletedButUnpaid || status == o_ds.OrderStatusCompletedAndPaid
+}
+
+// IsOpen returns true if the order has not been finished in any way.
+func IsOpen(status int8) bool {
+	switch status {
+	case o_ds.OrderStatusNew, o_ds.OrderStatusPending, o_ds.OrderStatusInProgress, o_ds.OrderStatusOngoing:
+		return true
+	}
+	return false
+}
+
+// requiresAssociate returns true if an order in the status must have been
+// assigned to an associate.
+func requiresAssociate(status int8) bool {
+	switch status {
+	case o_ds.OrderStatusPending, o_ds.OrderStatusInProgress, o_ds.OrderStatusOngoing:
+		return true
+	}
+	return IsCompleted(status)
+}
+
+// ParseStatus returns the status matching either the number or the label
+// (case and spacing insensitive), ex: `6`, `in_progress` or `In Progress`.
+func ParseStatus(s string) (int8, error) {
+	want := normalizeLabel(s)
+	for status, label := range o_ds.OrderStatusLabels {
+		if want == normalizeLabel(label) || want == fmt.Sprint(status) {
+			return status, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %v", ErrUnknownStatus, s)
+}
+
+func normalizeLabel(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer(" ", "", "_", "", "-", "").Replace(s)
+	return s
+}
+
+// ValidateClosure returns an error if the closure does not satisfy the
+// closing reason rules.
+func ValidateClosure(c *Closure) error {
+	if c == nil || c.Reason == 0 {
+		return ErrClosingReasonRequired
+	}
+	if _, ok := o_ds.OrderClosingReasonLabels[c.Reason]; !ok {
+		return fmt.Errorf("%w: %v", ErrUnknownClosingReason, c.Reason)
+	}
+	if c.Reason == ClosingReasonOther && strings.TrimSpace(c.ReasonOther) == "" {
+		return ErrClosingReasonOther
+	}
+	return nil
+}
+
+// Transition moves the order to the `to` status and applies the side effects
+// of the move. The closure is required when declining or cancelling the order
+// and ignored otherwise. The order is left untouched if an error is returned.
+func Transition(o *o_ds.Order, to int8, closure *Closure, now time.Time) error {
+	if _, ok := o_ds.OrderStatusLabels[to]; !ok {
+		return fmt.Errorf("%w: %v", ErrUnknownStatus, to)
+	}
+	if !CanTransition(o.Status, to) {
+		return fmt.Errorf("%w: from %v to %v", ErrTransitionNotAllowed, o_ds.OrderStatusLabels[o.Status], o_ds.OrderStatusLabels[to])
+	}
+
+	switch {
+	case IsClosed(to):
+		if err := ValidateClosure(closure); err != nil {
+			return err
+		}
+	case requiresAssociate(to):
+		if o.AssociateID.IsZero() {
+			return ErrAssociateRequired
+		}
+	}
+
+	switch to {
+	case o_ds.OrderStatusPending:
+		if o.AssignmentDate.IsZero() {
+			o.AssignmentDate = now
+		}
+	case o_ds.OrderStatusInProgress:
+		if o.StartDate.IsZero() {
+			o.StartDate = now
+		}
+	case o_ds.OrderStatusOngoing:
+		o.IsOngoing = true
+		if o.StartDate.IsZero() {
+			o.StartDate = now
+		}
+	case o_ds.OrderStatusCompletedButUnpaid, o_ds.OrderStatusCompletedAndPaid:
+		if o.CompletionDate.IsZero() {
+			o.CompletionDate = now
+		}
+	case o_ds.OrderStatusDeclined, o_ds.OrderStatusCancelled:
+		o.ClosingReason = closure.Reason
+		o.ClosingReasonOther = ""
+		if closure.Reason == ClosingReasonOther {
+			o.ClosingReasonOther = closure.ReasonOther
+		}
+		o.ClosingReasonComment = closure.ReasonComment
+	}
+
+	o.Status = to
+	return nil
+}
+
+// Check returns the reasons the order is in a state which it could not have
+// reached through `Transition`, ex: orders imported from the legacy system.
+func Check(o *o_ds.Order) []string {
+	var problems []string
+
+	if _, ok := o_ds.OrderStatusLabels[o.Status]; !ok {
+		return append(problems, fmt.Sprintf("unknown status %v", o.Status))
+	}
+
+	if IsClosed(o.Status) {
+		err := ValidateClosure(&Closure{Reason: o.ClosingReason, ReasonOther: o.ClosingReasonOther})
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+	} else if IsOpen(o.Status) && o.ClosingReason != 0 {
+		problems = append(problems, "open order has a closing reason")
+	}
+
+	if requiresAssociate(o.Status) && o.AssociateID.IsZero() {
+		problems = append(problems, "order is not assigned to an associate")
+	}
+	if IsCompleted(o.Status) && o.CompletionDate.IsZero() {
+		problems = append(problems, "completed order has no completion date")
+	}
+	if IsOpen(o.Status) && !o.CompletionDate.IsZero() {
+		problems = append(problems, "open order has a completion date")
+	}
+	if o.Status == o_ds.OrderStatusOngoing && !o.IsOngoing {
+		problems = append(problems, "ongoing status on an order which is not ongoing")
+	}
+	return problems
+}
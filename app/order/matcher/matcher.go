@@ -0,0 +1,164 @@
+package matcher
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+)
+
+// Weights of the parts of the rank of a candidate, they add up to one.
+const (
+	ScoreWeight     = 0.5
+	WorkloadWeight  = 0.3
+	ProximityWeight = 0.2
+
+	// proximityHalfKm is the distance at which the proximity of a candidate
+	// falls to one half.
+	proximityHalfKm = 25.0
+
+	earthRadiusKm = 6371.0
+)
+
+// Requirements is what an associate must have to be able to take an order.
+type Requirements struct {
+	SkillSetIDs             []primitive.ObjectID
+	InsuranceRequirementIDs []primitive.ObjectID
+
+	// VehicleTypes are matched against either the ID or the name (case
+	// insensitive) of the vehicle types of the associate.
+	VehicleTypes []string
+}
+
+// Candidate is an associate who meets the requirements of an order.
+type Candidate struct {
+	AssociateID   primitive.ObjectID `json:"associate_id"`
+	AssociateName string             `json:"associate_name"`
+	Score         float64            `json:"score"`
+	OpenOrders    int64              `json:"open_orders"`
+
+	// DistanceKm is negative when the location of either the associate or the
+	// customer is unknown.
+	DistanceKm float64 `json:"distance_km"`
+
+	// Rank is from zero to one, the higher the better the fit.
+	Rank float64 `json:"rank"`
+}
+
+// Check returns every reason the associate cannot take an order with the
+// requirements. Insurance is only valid if the commercial insurance of the
+// associate has not expired, and vehicles also need valid auto insurance.
+func Check(a *a_ds.Associate, req *Requirements, now time.Time) []string {
+	var problems []string
+	if a.Status != a_ds.AssociateStatusActive {
+		problems = append(problems, "not active")
+	}
+
+	skillSets := map[primitive.ObjectID]bool{}
+	for _, ss := range a.SkillSets {
+		skillSets[ss.ID] = true
+	}
+	for _, id := range req.SkillSetIDs {
+		if !skillSets[id] {
+			problems = append(problems, fmt.Sprintf("missing skill set %v", id.Hex()))
+		}
+	}
+
+	if len(req.InsuranceRequirementIDs) > 0 {
+		insurance := map[primitive.ObjectID]bool{}
+		for _, ir := range a.InsuranceRequirements {
+			insurance[ir.ID] = true
+		}
+		for _, id := range req.InsuranceRequirementIDs {
+			if !insurance[id] {
+				problems = append(problems, fmt.Sprintf("missing insurance requirement %v", id.Hex()))
+			}
+		}
+		if !a.CommercialInsuranceExpiryDate.After(now) {
+			problems = append(problems, "commercial insurance expired")
+		}
+	}
+
+	if len(req.VehicleTypes) > 0 {
+		for _, vt := range req.VehicleTypes {
+			if !hasVehicleType(a, vt) {
+				problems = append(problems, fmt.Sprintf("missing vehicle type %v", vt))
+			}
+		}
+		if !a.AutoInsuranceExpiryDate.After(now) {
+			problems = append(problems, "auto insurance expired")
+		}
+	}
+	return problems
+}
+
+func hasVehicleType(a *a_ds.Associate, vt string) bool {
+	vt = strings.TrimSpace(vt)
+	for _, avt := range a.VehicleTypes {
+		if avt.ID.Hex() == vt || strings.EqualFold(avt.Name, vt) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAway returns true if any of the away logs is active at the time. An away
+// log without an end date lasts until it is archived.
+func IsAway(logs []*aal_ds.AssociateAwayLog, now time.Time) bool {
+	for _, l := range logs {
+		if l.Status != aal_ds.AssociateAwayLogStatusActive {
+			continue
+		}
+		if !l.StartDate.IsZero() && l.StartDate.After(now) {
+			continue
+		}
+		if l.UntilFurtherNotice == aal_ds.UntilFurtherNoticeYes || l.UntilDate.IsZero() || l.UntilDate.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Distance returns the great-circle distance in kilometres between the two
+// coordinates.
+func Distance(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// Rank sets the rank of every candidate and sorts them best first. The score
+// is relative to the highest score of the candidates, the workload favours
+// associates with fewer open orders and the proximity favours associates who
+// are closer to the customer. An unknown distance counts as half proximity.
+func Rank(candidates []*Candidate) {
+	var maxScore float64
+	for _, c := range candidates {
+		if c.Score > maxScore {
+			maxScore = c.Score
+		}
+	}
+	for _, c := range candidates {
+		score := 0.0
+		if maxScore > 0 {
+			score = c.Score / maxScore
+		}
+		workload := 1 / float64(1+c.OpenOrders)
+		proximity := 0.5
+		if c.DistanceKm >= 0 {
+			proximity = 1 / (1 + c.DistanceKm/proximityHalfKm)
+		}
+		c.Rank = ScoreWeight*score + WorkloadWeight*workload + ProximityWeight*proximity
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Rank > candidates[j].Rank
+	})
+}
@@ -0,0 +1,210 @@
+package permission
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	c "github.com/over55/workery-cli/config"
+)
+
+const (
+	ResourceOrder      = "order"
+	ResourceCustomer   = "customer"
+	ResourceAssociate  = "associate"
+	ResourceInvoice    = "invoice"
+	ResourceAttachment = "attachment"
+	ResourceReport     = "report"
+	ResourceUser       = "user"
+	ResourceSystem     = "system"
+
+	ActionRead   = "read"
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	ActionExport = "export"
+
+	// ActionAdmin on users is changing roles and acting on the accounts of
+	// users with a higher role than the operator's own.
+	ActionAdmin = "admin"
+
+	// Any matches every resource or every action in a grant.
+	Any = "*"
+)
+
+var Resources = []string{ResourceOrder, ResourceCustomer, ResourceAssociate, ResourceInvoice, ResourceAttachment, ResourceReport, ResourceUser, ResourceSystem}
+
+var Actions = []string{ActionRead, ActionCreate, ActionUpdate, ActionDelete, ActionExport, ActionAdmin}
+
+var ErrForbidden = errors.New("permission denied")
+
+// DefaultGrants is what each role may do, as `<resource>:<action>` where
+// either side may be `*`.
+var DefaultGrants = map[int8][]string{
+	user_ds.UserRoleExecutive: {
+		"*:*",
+	},
+	user_ds.UserRoleManagement: {
+		"order:*", "customer:*", "associate:*", "invoice:*", "attachment:*", "report:*",
+		"user:read", "user:update",
+	},
+	user_ds.UserRoleStaff: {
+		"order:read", "order:create", "order:update",
+		"customer:read", "customer:create", "customer:update",
+		"associate:read", "associate:create", "associate:update",
+		"invoice:read", "invoice:create", "invoice:export",
+		"attachment:read", "attachment:create",
+		"report:read",
+	},
+	user_ds.UserRoleAssociate: {
+		"order:read", "order:update",
+		"invoice:read",
+		"attachment:read", "attachment:create",
+	},
+	user_ds.UserRoleCustomer: {
+		"order:read",
+		"invoice:read",
+	},
+}
+
+// Override changes the grants of a role for one tenant, denials win over
+// grants.
+type Override struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// Policy is the grants of every role and the overrides of every tenant.
+type Policy struct {
+	Grants    map[int8][]string
+	Overrides map[primitive.ObjectID]map[int8]*Override
+}
+
+// NewPolicy returns the default grants with the tenant overrides of the file
+// in the config. The file maps the tenant ID to the role label to the
+// override, for example:
+//
+//	{"64b0...": {"Staff": {"allow": ["invoice:update"], "deny": ["customer:create"]}}}
+func NewPolicy(cfg *c.Conf) (*Policy, error) {
+	p := &Policy{
+		Grants:    DefaultGrants,
+		Overrides: make(map[primitive.ObjectID]map[int8]*Override),
+	}
+	if cfg.Permission.OverridesFilePath == "" {
+		return p, nil
+	}
+
+	b, err := os.ReadFile(cfg.Permission.OverridesFilePath)
+	if err != nil {
+		return nil, err
+	}
+	raw := make(map[string]map[string]*Override)
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("invalid permission overrides file: %w", err)
+	}
+	for tenantHex, roles := range raw {
+		tenantID, err := primitive.ObjectIDFromHex(tenantHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tenant id in permission overrides: %v", tenantHex)
+		}
+		p.Overrides[tenantID] = make(map[int8]*Override)
+		for label, o := range roles {
+			role, ok := roleByLabel(label)
+			if !ok {
+				return nil, fmt.Errorf("invalid role in permission overrides: %v", label)
+			}
+			p.Overrides[tenantID][role] = o
+		}
+	}
+	return p, nil
+}
+
+// Can returns true if the user may do the action on the resource. Archived
+// users may not do anything, and users with the staff role may do at least
+// what staff may whatever their role.
+func (p *Policy) Can(u *user_ds.User, action string, resource string) bool {
+	if u == nil || u.Status != user_ds.UserStatusActive {
+		return false
+	}
+
+	var override *Override
+	if roles, ok := p.Overrides[u.TenantID]; ok {
+		override = roles[u.Role]
+	}
+	if override != nil && matchesAny(override.Deny, action, resource) {
+		return false
+	}
+	if matchesAny(p.Grants[u.Role], action, resource) {
+		return true
+	}
+	if u.HasStaffRole && matchesAny(p.Grants[user_ds.UserRoleStaff], action, resource) {
+		return true
+	}
+	return override != nil && matchesAny(override.Allow, action, resource)
+}
+
+// Check returns an error wrapping `ErrForbidden` if the user may not do the
+// action on the resource.
+func (p *Policy) Check(u *user_ds.User, action string, resource string) error {
+	if p.Can(u, action, resource) {
+		return nil
+	}
+	name := "unknown user"
+	if u != nil {
+		name = u.Email
+	}
+	return fmt.Errorf("%w: %v may not %v %v", ErrForbidden, name, action, resource)
+}
+
+// CheckRole returns an error wrapping `ErrForbidden` if the role is higher
+// than the user's own and they may not administer users. A lower role number
+// is a higher role, executive being the highest.
+func (p *Policy) CheckRole(u *user_ds.User, role int8) error {
+	if u != nil && u.Role <= role {
+		return nil
+	}
+	if p.Can(u, ActionAdmin, ResourceUser) {
+		return nil
+	}
+	name := "unknown user"
+	if u != nil {
+		name = u.Email
+	}
+	return fmt.Errorf("%w: %v may not manage %v users", ErrForbidden, name, user_ds.UserRoleLabels[role])
+}
+
+// Parse splits a `<resource>:<action>` permission.
+func Parse(permission string) (resource string, action string, err error) {
+	parts := strings.Split(permission, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid permission: %v", permission)
+	}
+	return parts[0], parts[1], nil
+}
+
+func matchesAny(grants []string, action string, resource string) bool {
+	for _, g := range grants {
+		r, a, err := Parse(g)
+		if err != nil {
+			continue
+		}
+		if (r == Any || r == resource) && (a == Any || a == action) {
+			return true
+		}
+	}
+	return false
+}
+
+func roleByLabel(label string) (int8, bool) {
+	for role, l := range user_ds.UserRoleLabels {
+		if strings.EqualFold(l, label) {
+			return role, true
+		}
+	}
+	return 0, false
+}
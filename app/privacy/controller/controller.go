@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	s3_storage "github.com/over55/workery-cli/adapter/storage/s3"
+	as_ds "github.com/over55/workery-cli/app/activitysheet/datastore"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+	att_ds "github.com/over55/workery-cli/app/attachment/datastore"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	com_ds "github.com/over55/workery-cli/app/comment/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	"github.com/over55/workery-cli/app/privacy"
+	resync_c "github.com/over55/workery-cli/app/resync/controller"
+	staff_ds "github.com/over55/workery-cli/app/staff/datastore"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	c "github.com/over55/workery-cli/config"
+)
+
+const (
+	KindCustomer  = "customer"
+	KindAssociate = "associate"
+	KindStaff     = "staff"
+)
+
+// Kinds lists the kinds of people records which can be erased.
+var Kinds = []string{KindCustomer, KindAssociate, KindStaff}
+
+var (
+	ErrUnsupportedKind = errors.New("unsupported kind")
+	ErrPersonNotFound  = errors.New("person does not exist")
+)
+
+// PrivacyController Interface for erasing a person from every collection
+// their personal information was copied into.
+type PrivacyController interface {
+	Erase(ctx context.Context, kind string, ref string, dryRun bool) (*privacy.Receipt, error)
+}
+
+type PrivacyControllerImpl struct {
+	Config                 *c.Conf
+	Logger                 *slog.Logger
+	S3                     s3_storage.S3Storager
+	CustomerStorer         c_ds.CustomerStorer
+	AssociateStorer        a_ds.AssociateStorer
+	StaffStorer            staff_ds.StaffStorer
+	UserStorer             user_ds.UserStorer
+	OrderStorer            o_ds.OrderStorer
+	TaskItemStorer         ti_ds.TaskItemStorer
+	ActivitySheetStorer    as_ds.ActivitySheetStorer
+	CommentStorer          com_ds.CommentStorer
+	AssociateAwayLogStorer aal_ds.AssociateAwayLogStorer
+	AttachmentStorer       att_ds.AttachmentStorer
+	AuditLogStorer         auditlog_ds.AuditLogStorer
+	Resync                 resync_c.ResyncController
+}
+
+func NewController(
+	appCfg *c.Conf,
+	loggerp *slog.Logger,
+	s3 s3_storage.S3Storager,
+	cStorer c_ds.CustomerStorer,
+	aStorer a_ds.AssociateStorer,
+	staffStorer staff_ds.StaffStorer,
+	uStorer user_ds.UserStorer,
+	oStorer o_ds.OrderStorer,
+	tiStorer ti_ds.TaskItemStorer,
+	asStorer as_ds.ActivitySheetStorer,
+	comStorer com_ds.CommentStorer,
+	aalStorer aal_ds.AssociateAwayLogStorer,
+	attStorer att_ds.AttachmentStorer,
+	alStorer auditlog_ds.AuditLogStorer,
+	resync resync_c.ResyncController,
+) PrivacyController {
+	s := &PrivacyControllerImpl{
+		Config:                 appCfg,
+		Logger:                 loggerp,
+		S3:                     s3,
+		CustomerStorer:         cStorer,
+		AssociateStorer:        aStorer,
+		StaffStorer:            staffStorer,
+		UserStorer:             uStorer,
+		OrderStorer:            oStorer,
+		TaskItemStorer:         tiStorer,
+		ActivitySheetStorer:    asStorer,
+		CommentStorer:          comStorer,
+		AssociateAwayLogStorer: aalStorer,
+		AttachmentStorer:       attStorer,
+		AuditLogStorer:         alStorer,
+		Resync:                 resync,
+	}
+	return s
+}
@@ -0,0 +1,596 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+	att_ds "github.com/over55/workery-cli/app/attachment/datastore"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	"github.com/over55/workery-cli/app/privacy"
+	resync_c "github.com/over55/workery-cli/app/resync/controller"
+	staff_ds "github.com/over55/workery-cli/app/staff/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+)
+
+const (
+	customersCollectionName         = "customers"
+	associatesCollectionName        = "associates"
+	staffCollectionName             = "staff"
+	usersCollectionName             = "users"
+	ordersCollectionName            = "orders"
+	taskItemsCollectionName         = "task_items"
+	activitySheetsCollectionName    = "activity_sheets"
+	commentsCollectionName          = "comments"
+	associateAwayLogsCollectionName = "associate_away_log"
+	attachmentsCollectionName       = "attachments"
+)
+
+const (
+	retainedRecords      = "orders, task items and activity sheets with their dates and amounts"
+	retainedInvoices     = "invoice amounts and line items along with the invoice files already issued"
+	retainedAuditLogs    = "who changed what and when in the audit log, with the erased values redacted"
+	retainedActorNames   = "their name where it is recorded as the author of a comment or change made by them"
+	retainedOrganization = "organization name of the business"
+	retainedCompliance   = "skill sets, service fee, balance owing and compliance dates"
+)
+
+// erasure collects what an erasure changed so the audit log entries of every
+// changed document can be redacted once nothing else will be saved.
+type erasure struct {
+	receipt    *privacy.Receipt
+	dryRun     bool
+	redactions []*redaction
+}
+
+// redaction is a document whose audit log values need redacting, every field
+// of it if no fields are given.
+type redaction struct {
+	collectionName string
+	documentID     primitive.ObjectID
+	fields         []string
+}
+
+func (e *erasure) erased(collectionName string, documentID primitive.ObjectID, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	e.receipt.Erased(collectionName, documentID, fields)
+	e.redactions = append(e.redactions, &redaction{collectionName, documentID, fields})
+}
+
+// subject records the erased fields of the person or their login account and
+// queues every audit log value of it for redaction, even if nothing changed
+// now, so running an erasure again after it failed past saving the tombstones
+// still redacts the values the first run left behind.
+func (e *erasure) subject(collectionName string, documentID primitive.ObjectID, fields []string) {
+	if len(fields) > 0 {
+		e.receipt.Erased(collectionName, documentID, fields)
+	}
+	e.redactions = append(e.redactions, &redaction{collectionName, documentID, nil})
+}
+
+func (e *erasure) deleted(collectionName string, documentID primitive.ObjectID) {
+	e.receipt.Count(collectionName, documentID)
+	e.redactions = append(e.redactions, &redaction{collectionName, documentID, nil})
+}
+
+// copied records the documents which held a copy of the person and were
+// refreshed from their tombstoned record.
+func (e *erasure) copied(report *resync_c.ResyncReport) {
+	for _, d := range report.Drifts {
+		e.erased(d.CollectionName, d.DocumentID, d.Fields)
+	}
+}
+
+// Erase replaces the personal fields of the customer, associate or staff
+// member and of their login account with tombstones, refreshes every copy of
+// them held by orders, task items, activity sheets and comments, tombstones
+// their details on invoices, deletes their attachments and archives them.
+// Documents in the trash are erased the same as the others.
+// Orders and invoice amounts are kept for accounting. The audit log values
+// of every changed document, and all of those of the person and their login
+// account, are redacted and a signed receipt is returned.
+// A dry run changes nothing, counts the documents holding a copy of the
+// person and returns an unsigned receipt. The reference may be the hex ID or
+// the public ID of the person.
+func (impl *PrivacyControllerImpl) Erase(ctx context.Context, kind string, ref string, dryRun bool) (*privacy.Receipt, error) {
+	// Fail before changing anything rather than leave an erasure without a
+	// receipt.
+	if !dryRun && impl.Config.Privacy.ReceiptSigningKey == "" {
+		return nil, privacy.ErrNoSigningKey
+	}
+
+	var e *erasure
+	var err error
+	switch kind {
+	case KindCustomer:
+		e, err = impl.eraseCustomer(ctx, ref, dryRun)
+	case KindAssociate:
+		e, err = impl.eraseAssociate(ctx, ref, dryRun)
+	case KindStaff:
+		e, err = impl.eraseStaff(ctx, ref, dryRun)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKind, kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return e.receipt, nil
+	}
+
+	for _, rd := range e.redactions {
+		n, err := impl.AuditLogStorer.RedactByDocumentID(ctx, rd.collectionName, rd.documentID, rd.fields)
+		if err != nil {
+			return nil, err
+		}
+		e.receipt.RedactedAuditLogs += n
+	}
+	if err := privacy.Sign(e.receipt, impl.Config.Privacy.ReceiptSigningKey); err != nil {
+		return nil, err
+	}
+
+	impl.Logger.Debug("erased person",
+		slog.String("kind", kind),
+		slog.String("id", e.receipt.SubjectID.Hex()),
+		slog.String("receipt_id", e.receipt.ID))
+	return e.receipt, nil
+}
+
+func (impl *PrivacyControllerImpl) newErasure(ctx context.Context, tenantID primitive.ObjectID, kind string, id primitive.ObjectID, publicID uint64, email string, userID primitive.ObjectID, dryRun bool) *erasure {
+	_, userName, _ := auditlog_ds.GetActor(ctx)
+	r := privacy.NewReceipt(tenantID, kind, id, publicID, userName, dryRun)
+	r.SubjectFingerprint = privacy.Fingerprint(impl.Config.Privacy.ReceiptSigningKey, email)
+	r.UserID = userID
+	return &erasure{receipt: r, dryRun: dryRun}
+}
+
+func (impl *PrivacyControllerImpl) eraseCustomer(ctx context.Context, ref string, dryRun bool) (*erasure, error) {
+	cu, err := impl.getCustomer(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	e := impl.newErasure(ctx, cu.TenantID, KindCustomer, cu.ID, cu.PublicID, cu.Email, cu.UserID, dryRun)
+	e.receipt.Retained = append(e.receipt.Retained, retainedRecords, retainedInvoices, retainedAuditLogs, retainedActorNames)
+	if cu.IsBusiness {
+		e.receipt.Retained = append(e.receipt.Retained, retainedOrganization)
+	}
+
+	// The files go first so a failure leaves the avatar key on the record
+	// to retry with. Attachments in the trash still hold their files.
+	attachments, err := impl.AttachmentStorer.ListByFilter(ctx, &att_ds.AttachmentListFilter{
+		PageSize:       1_000_000,
+		SortField:      "id",
+		SortOrder:      att_ds.OrderAscending,
+		CustomerID:     cu.ID,
+		IncludeDeleted: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := impl.deleteAttachments(ctx, e, attachments.Results, cu.AvatarObjectKey); err != nil {
+		return nil, err
+	}
+
+	before, err := snapshot(cu)
+	if err != nil {
+		return nil, err
+	}
+	tombstoneCustomer(cu)
+	fields, err := changedFields(before, cu)
+	if err != nil {
+		return nil, err
+	}
+	e.subject(customersCollectionName, cu.ID, fields)
+
+	if dryRun {
+		if err := impl.countCustomerCopies(ctx, e, cu.ID); err != nil {
+			return nil, err
+		}
+	} else {
+		userID, userName, ipAddress := auditlog_ds.GetActor(ctx)
+		cu.Status = c_ds.CustomerStatusArchived
+		cu.DeactivationReason = c_ds.CustomerDeactivationReasonOther
+		cu.DeactivationReasonOther = fmt.Sprintf("Erased on request, receipt %s", e.receipt.ID)
+		cu.ModifiedAt = time.Now()
+		cu.ModifiedByUserID = userID
+		cu.ModifiedByUserName = userName
+		cu.ModifiedFromIPAddress = ipAddress
+		if err := impl.CustomerStorer.UpdateByID(ctx, cu); err != nil {
+			return nil, err
+		}
+
+		resync, err := impl.Resync.ResyncCustomer(ctx, cu.ID, false)
+		if err != nil {
+			return nil, err
+		}
+		e.copied(resync)
+	}
+
+	orders, err := impl.OrderStorer.ListByCustomerIDWithDeleted(ctx, cu.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := impl.eraseInvoices(ctx, e, orders.Results, tombstoneInvoiceClient); err != nil {
+		return nil, err
+	}
+	if err := impl.eraseUser(ctx, e, cu.UserID); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (impl *PrivacyControllerImpl) eraseAssociate(ctx context.Context, ref string, dryRun bool) (*erasure, error) {
+	a, err := impl.getAssociate(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	e := impl.newErasure(ctx, a.TenantID, KindAssociate, a.ID, a.PublicID, a.Email, a.UserID, dryRun)
+	e.receipt.Retained = append(e.receipt.Retained, retainedRecords, retainedInvoices, retainedCompliance, retainedAuditLogs, retainedActorNames)
+
+	attachments, err := impl.AttachmentStorer.ListByFilter(ctx, &att_ds.AttachmentListFilter{
+		PageSize:       1_000_000,
+		SortField:      "id",
+		SortOrder:      att_ds.OrderAscending,
+		AssociateID:    a.ID,
+		IncludeDeleted: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := impl.deleteAttachments(ctx, e, attachments.Results, a.AvatarObjectKey); err != nil {
+		return nil, err
+	}
+
+	before, err := snapshot(a)
+	if err != nil {
+		return nil, err
+	}
+	tombstoneAssociate(a)
+	fields, err := changedFields(before, a)
+	if err != nil {
+		return nil, err
+	}
+	e.subject(associatesCollectionName, a.ID, fields)
+
+	if dryRun {
+		if err := impl.countAssociateCopies(ctx, e, a.ID); err != nil {
+			return nil, err
+		}
+	} else {
+		userID, userName, ipAddress := auditlog_ds.GetActor(ctx)
+		a.Status = a_ds.AssociateStatusArchived
+		a.DeactivationReason = a_ds.AssociateDeactivationReasonOther
+		a.DeactivationReasonOther = fmt.Sprintf("Erased on request, receipt %s", e.receipt.ID)
+		a.ModifiedAt = time.Now()
+		a.ModifiedByUserID = userID
+		a.ModifiedByUserName = userName
+		a.ModifiedFromIPAddress = ipAddress
+		if err := impl.AssociateStorer.UpdateByID(ctx, a); err != nil {
+			return nil, err
+		}
+
+		resync, err := impl.Resync.ResyncAssociate(ctx, a.ID, false)
+		if err != nil {
+			return nil, err
+		}
+		e.copied(resync)
+	}
+
+	awayLogs, err := impl.AssociateAwayLogStorer.ListByFilter(ctx, &aal_ds.AssociateAwayLogPaginationListFilter{
+		PageSize:       1_000_000,
+		SortField:      "", // Forget sorting, we don't need it here.
+		SortOrder:      1,
+		AssociateID:    a.ID,
+		IncludeDeleted: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, aal := range awayLogs.Results {
+		before, err := snapshot(aal)
+		if err != nil {
+			return nil, err
+		}
+		aal.AssociateName = privacy.Tombstone
+		aal.AssociateLexicalName = privacy.Tombstone
+		fields, err := changedFields(before, aal)
+		if err != nil {
+			return nil, err
+		}
+		e.erased(associateAwayLogsCollectionName, aal.ID, fields)
+		if dryRun || len(fields) == 0 {
+			continue
+		}
+		if err := impl.AssociateAwayLogStorer.UpdateByID(ctx, aal); err != nil {
+			return nil, err
+		}
+	}
+
+	orders, err := impl.OrderStorer.ListByAssociateIDWithDeleted(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := impl.eraseInvoices(ctx, e, orders.Results, tombstoneInvoiceAssociate); err != nil {
+		return nil, err
+	}
+	if err := impl.eraseUser(ctx, e, a.UserID); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// eraseStaff tombstones the staff member and their login account, staff are
+// not copied into other documents besides as the author of changes.
+func (impl *PrivacyControllerImpl) eraseStaff(ctx context.Context, ref string, dryRun bool) (*erasure, error) {
+	s, err := impl.getStaff(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	e := impl.newErasure(ctx, s.TenantID, KindStaff, s.ID, s.PublicID, s.Email, s.UserID, dryRun)
+	e.receipt.Retained = append(e.receipt.Retained, retainedAuditLogs, retainedActorNames)
+
+	if err := impl.deleteAttachments(ctx, e, nil, s.AvatarObjectKey); err != nil {
+		return nil, err
+	}
+
+	before, err := snapshot(s)
+	if err != nil {
+		return nil, err
+	}
+	tombstoneStaff(s)
+	fields, err := changedFields(before, s)
+	if err != nil {
+		return nil, err
+	}
+	e.subject(staffCollectionName, s.ID, fields)
+
+	if !dryRun {
+		userID, userName, ipAddress := auditlog_ds.GetActor(ctx)
+		s.Status = staff_ds.StaffStatusArchived
+		s.DeactivationReason = staff_ds.StaffDeactivationReasonOther
+		s.DeactivationReasonOther = fmt.Sprintf("Erased on request, receipt %s", e.receipt.ID)
+		s.ModifiedAt = time.Now()
+		s.ModifiedByUserID = userID
+		s.ModifiedByUserName = userName
+		s.ModifiedFromIPAddress = ipAddress
+		if err := impl.StaffStorer.UpdateByID(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := impl.eraseUser(ctx, e, s.UserID); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// deleteAttachments deletes the files of the attachments and the avatar from
+// the bucket and then the attachments themselves.
+func (impl *PrivacyControllerImpl) deleteAttachments(ctx context.Context, e *erasure, attachments []*att_ds.Attachment, avatarObjectKey string) error {
+	keys := []string{}
+	if avatarObjectKey != "" {
+		keys = append(keys, avatarObjectKey)
+	}
+	for _, att := range attachments {
+		if att.ObjectKey != "" {
+			keys = append(keys, att.ObjectKey)
+		}
+		e.deleted(attachmentsCollectionName, att.ID)
+	}
+	e.receipt.DeletedObjects = len(keys)
+	if e.dryRun {
+		return nil
+	}
+
+	if len(keys) > 0 {
+		if err := impl.S3.DeleteByKeys(ctx, keys); err != nil {
+			impl.Logger.Error("failed deleting objects", slog.Any("error", err))
+			return err
+		}
+	}
+	for _, att := range attachments {
+		if err := impl.AttachmentStorer.PermanentlyDeleteByID(ctx, att.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eraseInvoices tombstones the person on the current and past invoices of
+// every order.
+func (impl *PrivacyControllerImpl) eraseInvoices(ctx context.Context, e *erasure, orders []*o_ds.Order, tombstone func(*o_ds.OrderInvoice)) error {
+	for _, o := range orders {
+		before, err := snapshot(o)
+		if err != nil {
+			return err
+		}
+		if o.Invoice != nil {
+			tombstone(o.Invoice)
+		}
+		for _, inv := range o.PastInvoices {
+			if inv != nil {
+				tombstone(inv)
+			}
+		}
+		fields, err := changedFields(before, o)
+		if err != nil {
+			return err
+		}
+		e.erased(ordersCollectionName, o.ID, fields)
+		if e.dryRun || len(fields) == 0 {
+			continue
+		}
+		if err := impl.OrderStorer.UpdateByID(ctx, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eraseUser tombstones and archives the login account, if there is one.
+func (impl *PrivacyControllerImpl) eraseUser(ctx context.Context, e *erasure, id primitive.ObjectID) error {
+	if id.IsZero() {
+		return nil
+	}
+	u, err := impl.UserStorer.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return nil
+	}
+
+	before, err := snapshot(u)
+	if err != nil {
+		return err
+	}
+	tombstoneUser(u)
+	fields, err := changedFields(before, u)
+	if err != nil {
+		return err
+	}
+	e.subject(usersCollectionName, u.ID, fields)
+	if e.dryRun {
+		return nil
+	}
+
+	userID, userName, ipAddress := auditlog_ds.GetActor(ctx)
+	u.Status = user_ds.UserStatusArchived
+	u.ModifiedAt = time.Now()
+	u.ModifiedByUserID = userID
+	u.ModifiedByUserName = userName
+	u.ModifiedFromIPAddress = ipAddress
+	return impl.UserStorer.UpdateByID(ctx, u)
+}
+
+// countCustomerCopies counts the documents holding a copy of the customer,
+// including the ones in the trash, a dry run cannot tell which of them would
+// change.
+func (impl *PrivacyControllerImpl) countCustomerCopies(ctx context.Context, e *erasure, id primitive.ObjectID) error {
+	orders, err := impl.OrderStorer.ListByCustomerIDWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, o := range orders.Results {
+		e.receipt.Count(ordersCollectionName, o.ID)
+	}
+	taskItems, err := impl.TaskItemStorer.ListByCustomerIDWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, ti := range taskItems.Results {
+		e.receipt.Count(taskItemsCollectionName, ti.ID)
+	}
+	comments, err := impl.CommentStorer.ListByCustomerIDWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, com := range comments.Results {
+		e.receipt.Count(commentsCollectionName, com.ID)
+	}
+	return nil
+}
+
+// countAssociateCopies does the same for the associate.
+func (impl *PrivacyControllerImpl) countAssociateCopies(ctx context.Context, e *erasure, id primitive.ObjectID) error {
+	orders, err := impl.OrderStorer.ListByAssociateIDWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, o := range orders.Results {
+		e.receipt.Count(ordersCollectionName, o.ID)
+	}
+	taskItems, err := impl.TaskItemStorer.ListByAssociateIDWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, ti := range taskItems.Results {
+		e.receipt.Count(taskItemsCollectionName, ti.ID)
+	}
+	activitySheets, err := impl.ActivitySheetStorer.ListByAssociateIDWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, as := range activitySheets.Results {
+		e.receipt.Count(activitySheetsCollectionName, as.ID)
+	}
+	comments, err := impl.CommentStorer.ListByAssociateIDWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, com := range comments.Results {
+		e.receipt.Count(commentsCollectionName, com.ID)
+	}
+	return nil
+}
+
+// parseRef returns the hex ID or, failing that, the public ID the reference
+// holds.
+func parseRef(ref string) (primitive.ObjectID, uint64) {
+	if id, err := primitive.ObjectIDFromHex(ref); err == nil {
+		return id, 0
+	}
+	publicID, _ := strconv.ParseUint(ref, 10, 64)
+	return primitive.NilObjectID, publicID
+}
+
+func (impl *PrivacyControllerImpl) getCustomer(ctx context.Context, ref string) (*c_ds.Customer, error) {
+	var cu *c_ds.Customer
+	var err error
+	if id, publicID := parseRef(ref); !id.IsZero() {
+		cu, err = impl.CustomerStorer.GetByID(ctx, id)
+	} else if publicID != 0 {
+		cu, err = impl.CustomerStorer.GetByPublicID(ctx, publicID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cu == nil {
+		return nil, fmt.Errorf("%w: customer %s", ErrPersonNotFound, ref)
+	}
+	return cu, nil
+}
+
+func (impl *PrivacyControllerImpl) getAssociate(ctx context.Context, ref string) (*a_ds.Associate, error) {
+	var a *a_ds.Associate
+	var err error
+	if id, publicID := parseRef(ref); !id.IsZero() {
+		a, err = impl.AssociateStorer.GetByID(ctx, id)
+	} else if publicID != 0 {
+		a, err = impl.AssociateStorer.GetByPublicID(ctx, publicID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, fmt.Errorf("%w: associate %s", ErrPersonNotFound, ref)
+	}
+	return a, nil
+}
+
+func (impl *PrivacyControllerImpl) getStaff(ctx context.Context, ref string) (*staff_ds.Staff, error) {
+	var s *staff_ds.Staff
+	var err error
+	if id, publicID := parseRef(ref); !id.IsZero() {
+		s, err = impl.StaffStorer.GetByID(ctx, id)
+	} else if publicID != 0 {
+		s, err = impl.StaffStorer.GetByPublicID(ctx, publicID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, fmt.Errorf("%w: staff %s", ErrPersonNotFound, ref)
+	}
+	return s, nil
+}
@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	"github.com/over55/workery-cli/app/privacy"
+	staff_ds "github.com/over55/workery-cli/app/staff/datastore"
+	"github.com/over55/workery-cli/app/user/credentials"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+)
+
+// blank empties every one of the strings.
+func blank(ps ...*string) {
+	for _, p := range ps {
+		*p = ""
+	}
+}
+
+// tombstoneCustomer replaces the names and empties the contact details,
+// addresses, birthdate, gender, avatar and comments of the customer. The
+// organization name of a business is kept.
+func tombstoneCustomer(cu *c_ds.Customer) {
+	cu.FirstName = privacy.Tombstone
+	cu.Name = privacy.Tombstone
+	cu.LexicalName = privacy.Tombstone
+	blank(&cu.LastName, &cu.Email, &cu.Phone, &cu.PhoneExtension, &cu.FaxNumber, &cu.OtherPhone, &cu.OtherPhoneExtension)
+	blank(&cu.Country, &cu.Region, &cu.City, &cu.PostalCode, &cu.AddressLine1, &cu.AddressLine2, &cu.PostOfficeBoxNumber)
+	blank(&cu.FullAddressWithoutPostalCode, &cu.FullAddressWithPostalCode, &cu.FullAddressURL)
+	blank(&cu.ShippingName, &cu.ShippingPhone, &cu.ShippingCountry, &cu.ShippingRegion, &cu.ShippingCity, &cu.ShippingPostalCode)
+	blank(&cu.ShippingAddressLine1, &cu.ShippingAddressLine2, &cu.ShippingPostOfficeBoxNumber)
+	blank(&cu.ShippingFullAddressWithoutPostalCode, &cu.ShippingFullAddressWithPostalCode, &cu.ShippingFullAddressURL)
+	blank(&cu.Nationality, &cu.GenderOther, &cu.TaxId, &cu.Description, &cu.HowDidYouHearAboutUsOther, &cu.PrAccessCode)
+	blank(&cu.AvatarObjectURL, &cu.AvatarObjectKey, &cu.AvatarFileType, &cu.AvatarFileName)
+	cu.HasShippingAddress = false
+	cu.AvatarObjectExpiry = time.Time{}
+	cu.BirthDate = time.Time{}
+	cu.Gender = 0
+	cu.Elevation, cu.Latitude, cu.Longitude = 0, 0, 0
+	if !cu.IsBusiness {
+		cu.OrganizationName = ""
+	}
+	if len(cu.Comments) > 0 {
+		cu.Comments = []*c_ds.CustomerComment{}
+	}
+}
+
+// tombstoneAssociate does the same for the associate along with their
+// emergency contact and licence and tax numbers. Their skill sets, service
+// fee and compliance dates are kept.
+func tombstoneAssociate(a *a_ds.Associate) {
+	a.FirstName = privacy.Tombstone
+	a.Name = privacy.Tombstone
+	a.LexicalName = privacy.Tombstone
+	blank(&a.LastName, &a.Email, &a.PersonalEmail, &a.Phone, &a.PhoneExtension, &a.FaxNumber, &a.OtherPhone, &a.OtherPhoneExtension)
+	blank(&a.Country, &a.Region, &a.City, &a.PostalCode, &a.AddressLine1, &a.AddressLine2, &a.PostOfficeBoxNumber)
+	blank(&a.FullAddressWithoutPostalCode, &a.FullAddressWithPostalCode, &a.FullAddressURL)
+	blank(&a.ShippingName, &a.ShippingPhone, &a.ShippingCountry, &a.ShippingRegion, &a.ShippingCity, &a.ShippingPostalCode)
+	blank(&a.ShippingAddressLine1, &a.ShippingAddressLine2, &a.ShippingPostOfficeBoxNumber)
+	blank(&a.ShippingFullAddressWithoutPostalCode, &a.ShippingFullAddressWithPostalCode, &a.ShippingFullAddressURL)
+	blank(&a.Nationality, &a.GenderOther, &a.TaxID, &a.Description, &a.HowDidYouHearAboutUsOther, &a.PrAccessCode)
+	blank(&a.WsibNumber, &a.DriversLicenseClass, &a.OrganizationName)
+	blank(&a.EmergencyContactName, &a.EmergencyContactRelationship, &a.EmergencyContactTelephone, &a.EmergencyContactAlternativeTelephone)
+	blank(&a.AvatarObjectURL, &a.AvatarObjectKey, &a.AvatarFileType, &a.AvatarFileName)
+	a.HasShippingAddress = false
+	a.AvatarObjectExpiry = time.Time{}
+	a.BirthDate = time.Time{}
+	a.Gender = 0
+	a.Elevation, a.Latitude, a.Longitude = 0, 0, 0
+	if len(a.IdentifyAs) > 0 {
+		a.IdentifyAs = []int8{}
+	}
+	if len(a.Comments) > 0 {
+		a.Comments = []*a_ds.AssociateComment{}
+	}
+	for _, aal := range a.AwayLogs {
+		aal.AssociateName = privacy.Tombstone
+		aal.AssociateLexicalName = privacy.Tombstone
+	}
+}
+
+// tombstoneStaff does the same for the staff member.
+func tombstoneStaff(s *staff_ds.Staff) {
+	s.FirstName = privacy.Tombstone
+	s.Name = privacy.Tombstone
+	s.LexicalName = privacy.Tombstone
+	blank(&s.LastName, &s.Email, &s.PersonalEmail, &s.Phone, &s.PhoneExtension, &s.FaxNumber, &s.OtherPhone, &s.OtherPhoneExtension)
+	blank(&s.Country, &s.Region, &s.City, &s.PostalCode, &s.AddressLine1, &s.AddressLine2, &s.PostOfficeBoxNumber)
+	blank(&s.FullAddressWithoutPostalCode, &s.FullAddressWithPostalCode, &s.FullAddressURL)
+	blank(&s.ShippingName, &s.ShippingPhone, &s.ShippingCountry, &s.ShippingRegion, &s.ShippingCity, &s.ShippingPostalCode)
+	blank(&s.ShippingAddressLine1, &s.ShippingAddressLine2, &s.ShippingPostOfficeBoxNumber)
+	blank(&s.ShippingFullAddressWithoutPostalCode, &s.ShippingFullAddressWithPostalCode, &s.ShippingFullAddressURL)
+	blank(&s.Nationality, &s.GenderOther, &s.TaxID, &s.Description, &s.HowDidYouHearAboutUsOther, &s.PrAccessCode)
+	blank(&s.WsibNumber, &s.DriversLicenseClass)
+	blank(&s.EmergencyContactName, &s.EmergencyContactRelationship, &s.EmergencyContactTelephone, &s.EmergencyContactAlternativeTelephone)
+	blank(&s.AvatarObjectURL, &s.AvatarObjectKey, &s.AvatarFileType, &s.AvatarFileName)
+	s.HasShippingAddress = false
+	s.AvatarObjectExpiry = time.Time{}
+	s.BirthDate = time.Time{}
+	s.Gender = 0
+	s.Elevation, s.Latitude, s.Longitude = 0, 0, 0
+	if len(s.IdentifyAs) > 0 {
+		s.IdentifyAs = []int8{}
+	}
+	if len(s.Comments) > 0 {
+		s.Comments = []*staff_ds.StaffComment{}
+	}
+	for _, sal := range s.AwayLogs {
+		sal.StaffName = privacy.Tombstone
+		sal.StaffLexicalName = privacy.Tombstone
+	}
+}
+
+// tombstoneUser replaces the names and email of the login account, which
+// must stay unique, and removes every way of logging in with it.
+func tombstoneUser(u *user_ds.User) {
+	u.Email = fmt.Sprintf("erased_%s@workery.invalid", u.ID.Hex())
+	u.FirstName = privacy.Tombstone
+	u.Name = privacy.Tombstone
+	u.LexicalName = privacy.Tombstone
+	blank(&u.LastName, &u.Phone, &u.Country, &u.Region, &u.City)
+	blank(&u.EmailVerificationCode, &u.PrAccessCode, &u.OTPSecret, &u.OTPAuthURL)
+	u.PasswordHash = credentials.PlaceholderPasswordHash
+	u.OTPEnabled, u.OTPVerified, u.OTPValidated = false, false, false
+	if len(u.OTPRecoveryCodeHashes) > 0 {
+		u.OTPRecoveryCodeHashes = []string{}
+	}
+	if len(u.Comments) > 0 {
+		u.Comments = []*user_ds.UserComment{}
+	}
+}
+
+// tombstoneInvoiceClient empties the customer details printed on the
+// invoice, the amounts are kept for accounting.
+func tombstoneInvoiceClient(inv *o_ds.OrderInvoice) {
+	inv.ClientName = privacy.Tombstone
+	blank(&inv.ClientPhone, &inv.ClientEmail, &inv.ClientAddress, &inv.ClientSignature)
+}
+
+// tombstoneInvoiceAssociate does the same for the associate details.
+func tombstoneInvoiceAssociate(inv *o_ds.OrderInvoice) {
+	inv.AssociateName = privacy.Tombstone
+	blank(&inv.AssociatePhone, &inv.AssociateSignature)
+}
+
+// snapshot encodes the document the way it is stored so the fields a
+// tombstone changed can be listed with `changedFields` afterwards.
+func snapshot(doc interface{}) (bson.M, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	m := bson.M{}
+	if err := bson.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// changedFields returns the sorted top-level fields of the document which
+// differ from the snapshot.
+func changedFields(before bson.M, doc interface{}) ([]string, error) {
+	after, err := snapshot(doc)
+	if err != nil {
+		return nil, err
+	}
+	fields := []string{}
+	for k, v := range after {
+		if !reflect.DeepEqual(before[k], v) {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
@@ -0,0 +1,169 @@
+package privacy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Tombstone replaces the names of erased people, the rest of their
+	// personal fields are emptied.
+	Tombstone = "[erased]"
+
+	// Algorithm is the only signature algorithm receipts are signed with.
+	Algorithm = "HMAC-SHA256"
+)
+
+var (
+	ErrNoSigningKey     = errors.New("receipt signing key is not configured")
+	ErrUnsigned         = errors.New("receipt is not signed")
+	ErrInvalidSignature = errors.New("receipt signature does not match")
+)
+
+// Receipt records what an erasure tombstoned, deleted and deliberately kept,
+// it holds no personal information of the erased person. The signature
+// covers every other field so the receipt cannot be altered after the fact.
+type Receipt struct {
+	ID              string             `json:"id"`
+	DryRun          bool               `json:"dry_run"`
+	TenantID        primitive.ObjectID `json:"tenant_id"`
+	Kind            string             `json:"kind"`
+	SubjectID       primitive.ObjectID `json:"subject_id"`
+	SubjectPublicID uint64             `json:"subject_public_id"`
+
+	// SubjectFingerprint is a keyed hash of the erased email so a later
+	// request from the same person can be matched to this receipt without
+	// the receipt revealing who they are.
+	SubjectFingerprint string `json:"subject_fingerprint,omitempty"`
+
+	UserID   primitive.ObjectID `json:"user_id"`
+	ErasedAt time.Time          `json:"erased_at"`
+	ErasedBy string             `json:"erased_by"`
+
+	// ErasedFields lists the tombstoned fields of each collection and
+	// Documents how many documents of each collection were tombstoned or
+	// deleted.
+	ErasedFields      map[string][]string `json:"erased_fields"`
+	Documents         map[string]int      `json:"documents"`
+	DeletedObjects    int                 `json:"deleted_objects"`
+	RedactedAuditLogs int64               `json:"redacted_audit_logs"`
+	Retained          []string            `json:"retained"`
+
+	Algorithm string `json:"algorithm,omitempty"`
+	Signature string `json:"signature,omitempty"`
+
+	counted map[string]bool
+}
+
+// NewReceipt returns an empty receipt for the person.
+func NewReceipt(tenantID primitive.ObjectID, kind string, subjectID primitive.ObjectID, subjectPublicID uint64, erasedBy string, dryRun bool) *Receipt {
+	return &Receipt{
+		ID:              primitive.NewObjectID().Hex(),
+		DryRun:          dryRun,
+		TenantID:        tenantID,
+		Kind:            kind,
+		SubjectID:       subjectID,
+		SubjectPublicID: subjectPublicID,
+		ErasedAt:        time.Now().UTC().Truncate(time.Second),
+		ErasedBy:        erasedBy,
+		ErasedFields:    map[string][]string{},
+		Documents:       map[string]int{},
+		Retained:        []string{},
+	}
+}
+
+// Erased records that the fields of the document were tombstoned.
+func (r *Receipt) Erased(collectionName string, documentID primitive.ObjectID, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	r.Count(collectionName, documentID)
+	has := make(map[string]bool, len(r.ErasedFields[collectionName]))
+	for _, f := range r.ErasedFields[collectionName] {
+		has[f] = true
+	}
+	for _, f := range fields {
+		if !has[f] {
+			has[f] = true
+			r.ErasedFields[collectionName] = append(r.ErasedFields[collectionName], f)
+		}
+	}
+}
+
+// Count adds the document to the documents of the collection, once.
+func (r *Receipt) Count(collectionName string, documentID primitive.ObjectID) {
+	if r.counted == nil {
+		r.counted = make(map[string]bool)
+	}
+	key := collectionName + "/" + documentID.Hex()
+	if r.counted[key] {
+		return
+	}
+	r.counted[key] = true
+	r.Documents[collectionName]++
+}
+
+// Fingerprint returns the keyed hash of the email, or nothing if there is
+// no email or no key.
+func Fingerprint(key string, email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if key == "" || email == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("subject:" + email))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign sets the signature of the receipt.
+func Sign(r *Receipt, key string) error {
+	if key == "" {
+		return ErrNoSigningKey
+	}
+	r.Algorithm = Algorithm
+	sig, err := signature(r, key)
+	if err != nil {
+		return err
+	}
+	r.Signature = sig
+	return nil
+}
+
+// Verify returns nil if the receipt was signed with the key and has not been
+// altered since.
+func Verify(r *Receipt, key string) error {
+	if key == "" {
+		return ErrNoSigningKey
+	}
+	if r.Signature == "" || r.Algorithm != Algorithm {
+		return ErrUnsigned
+	}
+	want, err := signature(r, key)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(want), []byte(r.Signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// signature returns the HMAC of the receipt encoded without its signature.
+func signature(r *Receipt, key string) (string, error) {
+	unsigned := *r
+	unsigned.Signature = ""
+	b, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(b)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	sf_ds "github.com/over55/workery-cli/app/servicefee/datastore"
+	ss_ds "github.com/over55/workery-cli/app/skillset/datastore"
+	tag_ds "github.com/over55/workery-cli/app/tag/datastore"
+)
+
+// Audit compares every denormalized copy belonging to the tenant against the
+// source record it was copied from. Unlike the `Resync*` functions this does
+// not cascade, each document is only compared against its direct sources. When
+// `dryRun` is false the drift found is fixed as well.
+//
+// A document refreshed by one pass is kept in memory and reused by the later
+// passes, so a dry run reports the same drift as a real run even though the
+// earlier fixes were never saved.
+func (impl *ResyncControllerImpl) Audit(ctx context.Context, tenantID primitive.ObjectID, dryRun bool) (*ResyncReport, error) {
+	report := &ResyncReport{DryRun: dryRun}
+	customers := map[primitive.ObjectID]*c_ds.Customer{}
+	associates := map[primitive.ObjectID]*a_ds.Associate{}
+	orders := map[primitive.ObjectID]*o_ds.Order{}
+
+	sfs, err := impl.ServiceFeeStore.ListByFilter(ctx, &sf_ds.ServiceFeePaginationListFilter{
+		PageSize:  1_000_000,
+		SortField: "created_at",
+		SortOrder: sf_ds.OrderAscending,
+		TenantID:  tenantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, sf := range sfs.Results {
+		aa, err := impl.AssociateStorer.ListByServiceFeeID(ctx, sf.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range aa.Results {
+			a := latest(associates, a.ID, a)
+			fields := applyServiceFeeToAssociate(a, sf)
+			if err := impl.reconcile(report, associatesCollectionName, a.ID, serviceFeesCollectionName, sf.ID, fields, func() error {
+				return impl.AssociateStorer.UpdateByID(ctx, a)
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sss, err := impl.SkillSetStorer.ListByFilter(ctx, &ss_ds.SkillSetPaginationListFilter{
+		PageSize:  1_000_000,
+		SortField: "created_at",
+		SortOrder: ss_ds.OrderAscending,
+		TenantID:  tenantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, ss := range sss.Results {
+		aa, err := impl.AssociateStorer.ListBySkillSetID(ctx, ss.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range aa.Results {
+			a := latest(associates, a.ID, a)
+			fields := applySkillSetToAssociate(a, ss)
+			if err := impl.reconcile(report, associatesCollectionName, a.ID, skillSetsCollectionName, ss.ID, fields, func() error {
+				return impl.AssociateStorer.UpdateByID(ctx, a)
+			}); err != nil {
+				return nil, err
+			}
+		}
+		oo, err := impl.OrderStorer.ListBySkillSetID(ctx, ss.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range oo.Results {
+			o := latest(orders, o.ID, o)
+			fields := applySkillSetToOrder(o, ss)
+			if err := impl.reconcile(report, ordersCollectionName, o.ID, skillSetsCollectionName, ss.ID, fields, func() error {
+				return impl.OrderStorer.UpdateByID(ctx, o)
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	tt, err := impl.TagStorer.ListByFilter(ctx, &tag_ds.TagPaginationListFilter{
+		PageSize:  1_000_000,
+		SortField: "created_at",
+		SortOrder: tag_ds.OrderAscending,
+		TenantID:  tenantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tt.Results {
+		cc, err := impl.CustomerStorer.ListByTagID(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cc.Results {
+			c := latest(customers, c.ID, c)
+			fields := applyTagToCustomer(c, t)
+			if err := impl.reconcile(report, customersCollectionName, c.ID, tagsCollectionName, t.ID, fields, func() error {
+				return impl.CustomerStorer.UpdateByID(ctx, c)
+			}); err != nil {
+				return nil, err
+			}
+		}
+		aa, err := impl.AssociateStorer.ListByTagID(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range aa.Results {
+			a := latest(associates, a.ID, a)
+			fields := applyTagToAssociate(a, t)
+			if err := impl.reconcile(report, associatesCollectionName, a.ID, tagsCollectionName, t.ID, fields, func() error {
+				return impl.AssociateStorer.UpdateByID(ctx, a)
+			}); err != nil {
+				return nil, err
+			}
+		}
+		oo, err := impl.OrderStorer.ListByTagID(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range oo.Results {
+			o := latest(orders, o.ID, o)
+			fields := applyTagToOrder(o, t)
+			if err := impl.reconcile(report, ordersCollectionName, o.ID, tagsCollectionName, t.ID, fields, func() error {
+				return impl.OrderStorer.UpdateByID(ctx, o)
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Customers and associates are fetched again so the ones no pass above
+	// touched are compared against their orders as well.
+	cc, err := impl.CustomerStorer.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cc.Results {
+		if c.TenantID != tenantID {
+			continue
+		}
+		if err := impl.resyncFromCustomer(ctx, report, latest(customers, c.ID, c), orders); err != nil {
+			return nil, err
+		}
+	}
+
+	aa, err := impl.AssociateStorer.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range aa.Results {
+		if a.TenantID != tenantID {
+			continue
+		}
+		if err := impl.resyncFromAssociate(ctx, report, latest(associates, a.ID, a), orders); err != nil {
+			return nil, err
+		}
+	}
+
+	oo, err := impl.OrderStorer.ListByFilter(ctx, &o_ds.OrderPaginationListFilter{
+		PageSize:  1_000_000,
+		SortField: "start_date",
+		SortOrder: o_ds.SortOrderAscending,
+		TenantID:  tenantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range oo.Results {
+		if err := impl.resyncFromOrder(ctx, report, latest(orders, o.ID, o)); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
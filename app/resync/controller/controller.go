@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	as_ds "github.com/over55/workery-cli/app/activitysheet/datastore"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	com_ds "github.com/over55/workery-cli/app/comment/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	rt_ds "github.com/over55/workery-cli/app/resync/datastore"
+	sf_ds "github.com/over55/workery-cli/app/servicefee/datastore"
+	ss_ds "github.com/over55/workery-cli/app/skillset/datastore"
+	tag_ds "github.com/over55/workery-cli/app/tag/datastore"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	c "github.com/over55/workery-cli/config"
+)
+
+// ResyncReport lists every document which held a stale copy of a source
+// record along with the fields which were (or, on a dry run, would be)
+// refreshed.
+type ResyncReport struct {
+	DryRun bool     `json:"dry_run"`
+	Drifts []*Drift `json:"drifts"`
+}
+
+// Drift represents a single document whose denormalized fields no longer
+// match the source record they were copied from.
+type Drift struct {
+	CollectionName       string             `json:"collection_name"`
+	DocumentID           primitive.ObjectID `json:"document_id"`
+	SourceCollectionName string             `json:"source_collection_name"`
+	SourceID             primitive.ObjectID `json:"source_id"`
+	Fields               []string           `json:"fields"`
+}
+
+func (r *ResyncReport) add(collectionName string, documentID primitive.ObjectID, sourceCollectionName string, sourceID primitive.ObjectID, fields []string) {
+	r.Drifts = append(r.Drifts, &Drift{
+		CollectionName:       collectionName,
+		DocumentID:           documentID,
+		SourceCollectionName: sourceCollectionName,
+		SourceID:             sourceID,
+		Fields:               fields,
+	})
+}
+
+// ResyncController Interface for refreshing the copies of customer,
+// associate, service fee, skill set and tag records embedded in other
+// documents, either on demand or continuously with `Watch`.
+type ResyncController interface {
+	ResyncCustomer(ctx context.Context, customerID primitive.ObjectID, dryRun bool) (*ResyncReport, error)
+	ResyncAssociate(ctx context.Context, associateID primitive.ObjectID, dryRun bool) (*ResyncReport, error)
+	ResyncServiceFee(ctx context.Context, serviceFeeID primitive.ObjectID, dryRun bool) (*ResyncReport, error)
+	ResyncSkillSet(ctx context.Context, skillSetID primitive.ObjectID, dryRun bool) (*ResyncReport, error)
+	ResyncTag(ctx context.Context, tagID primitive.ObjectID, dryRun bool) (*ResyncReport, error)
+	Audit(ctx context.Context, tenantID primitive.ObjectID, dryRun bool) (*ResyncReport, error)
+	Watch(ctx context.Context, fromNow bool) error
+}
+
+type ResyncControllerImpl struct {
+	Config              *c.Conf
+	Logger              *slog.Logger
+	DbClient            *mongo.Client
+	CustomerStorer      c_ds.CustomerStorer
+	AssociateStorer     a_ds.AssociateStorer
+	OrderStorer         o_ds.OrderStorer
+	TaskItemStorer      ti_ds.TaskItemStorer
+	ActivitySheetStorer as_ds.ActivitySheetStorer
+	CommentStorer       com_ds.CommentStorer
+	ServiceFeeStore     sf_ds.ServiceFeeStorer
+	SkillSetStorer      ss_ds.SkillSetStorer
+	TagStorer           tag_ds.TagStorer
+	ResumeTokenStorer   rt_ds.ResumeTokenStorer
+}
+
+func NewController(
+	appCfg *c.Conf,
+	loggerp *slog.Logger,
+	client *mongo.Client,
+	cStorer c_ds.CustomerStorer,
+	aStorer a_ds.AssociateStorer,
+	oStorer o_ds.OrderStorer,
+	tiStorer ti_ds.TaskItemStorer,
+	asStorer as_ds.ActivitySheetStorer,
+	comStorer com_ds.CommentStorer,
+	sfStorer sf_ds.ServiceFeeStorer,
+	ssStorer ss_ds.SkillSetStorer,
+	tagStorer tag_ds.TagStorer,
+	rtStorer rt_ds.ResumeTokenStorer,
+) ResyncController {
+	s := &ResyncControllerImpl{
+		Config:              appCfg,
+		Logger:              loggerp,
+		DbClient:            client,
+		CustomerStorer:      cStorer,
+		AssociateStorer:     aStorer,
+		OrderStorer:         oStorer,
+		TaskItemStorer:      tiStorer,
+		ActivitySheetStorer: asStorer,
+		CommentStorer:       comStorer,
+		ServiceFeeStore:     sfStorer,
+		SkillSetStorer:      ssStorer,
+		TagStorer:           tagStorer,
+		ResumeTokenStorer:   rtStorer,
+	}
+	return s
+}
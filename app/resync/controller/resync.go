@@ -0,0 +1,365 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+)
+
+const (
+	customersCollectionName      = "customers"
+	associatesCollectionName     = "associates"
+	ordersCollectionName         = "orders"
+	taskItemsCollectionName      = "task_items"
+	activitySheetsCollectionName = "activity_sheets"
+	commentsCollectionName       = "comments"
+	serviceFeesCollectionName    = "service_fees"
+	skillSetsCollectionName      = "skill_sets"
+	tagsCollectionName           = "tags"
+)
+
+// ErrSourceNotFound is returned when the record to resync from does not exist.
+var ErrSourceNotFound = errors.New("does not exist")
+
+// reconcile records the drift found in a document and, unless this is a dry
+// run, saves the refreshed document.
+func (impl *ResyncControllerImpl) reconcile(report *ResyncReport, collectionName string, documentID primitive.ObjectID, sourceCollectionName string, sourceID primitive.ObjectID, fields []string, save func() error) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	report.add(collectionName, documentID, sourceCollectionName, sourceID, fields)
+	impl.Logger.Debug("denormalized fields drifted",
+		slog.String("collection", collectionName),
+		slog.Any("document_id", documentID),
+		slog.String("source_collection", sourceCollectionName),
+		slog.Any("source_id", sourceID),
+		slog.Any("fields", fields),
+		slog.Bool("dry_run", report.DryRun))
+	if report.DryRun {
+		return nil
+	}
+	if err := save(); err != nil {
+		impl.Logger.Error("failed resyncing document",
+			slog.String("collection", collectionName),
+			slog.Any("document_id", documentID),
+			slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// latest returns the copy of the document already refreshed by an earlier
+// pass, remembering `v` as that copy when there is none yet. A nil map
+// always returns `v`.
+func latest[T any](seen map[primitive.ObjectID]T, id primitive.ObjectID, v T) T {
+	if seen == nil {
+		return v
+	}
+	if prev, ok := seen[id]; ok {
+		return prev
+	}
+	seen[id] = v
+	return v
+}
+
+func (impl *ResyncControllerImpl) ResyncCustomer(ctx context.Context, customerID primitive.ObjectID, dryRun bool) (*ResyncReport, error) {
+	c, err := impl.CustomerStorer.GetByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, fmt.Errorf("customer %v: %w", customerID.Hex(), ErrSourceNotFound)
+	}
+	report := &ResyncReport{DryRun: dryRun}
+	if err := impl.resyncFromCustomer(ctx, report, c, nil); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (impl *ResyncControllerImpl) ResyncAssociate(ctx context.Context, associateID primitive.ObjectID, dryRun bool) (*ResyncReport, error) {
+	a, err := impl.AssociateStorer.GetByID(ctx, associateID)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, fmt.Errorf("associate %v: %w", associateID.Hex(), ErrSourceNotFound)
+	}
+	report := &ResyncReport{DryRun: dryRun}
+	if err := impl.resyncFromAssociate(ctx, report, a, nil); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ResyncServiceFee refreshes the service fee copied into every associate
+// which uses it and then cascades the associate into their orders and task
+// items. Invoices keep the service fee they were issued with.
+func (impl *ResyncControllerImpl) ResyncServiceFee(ctx context.Context, serviceFeeID primitive.ObjectID, dryRun bool) (*ResyncReport, error) {
+	sf, err := impl.ServiceFeeStore.GetByID(ctx, serviceFeeID)
+	if err != nil {
+		return nil, err
+	}
+	if sf == nil {
+		return nil, fmt.Errorf("service fee %v: %w", serviceFeeID.Hex(), ErrSourceNotFound)
+	}
+	report := &ResyncReport{DryRun: dryRun}
+
+	aa, err := impl.AssociateStorer.ListByServiceFeeID(ctx, sf.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range aa.Results {
+		fields := applyServiceFeeToAssociate(a, sf)
+		if err := impl.reconcile(report, associatesCollectionName, a.ID, serviceFeesCollectionName, sf.ID, fields, func() error {
+			return impl.AssociateStorer.UpdateByID(ctx, a)
+		}); err != nil {
+			return nil, err
+		}
+		if err := impl.resyncFromAssociate(ctx, report, a, nil); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+func (impl *ResyncControllerImpl) ResyncSkillSet(ctx context.Context, skillSetID primitive.ObjectID, dryRun bool) (*ResyncReport, error) {
+	ss, err := impl.SkillSetStorer.GetByID(ctx, skillSetID)
+	if err != nil {
+		return nil, err
+	}
+	if ss == nil {
+		return nil, fmt.Errorf("skill set %v: %w", skillSetID.Hex(), ErrSourceNotFound)
+	}
+	report := &ResyncReport{DryRun: dryRun}
+	orders := map[primitive.ObjectID]*o_ds.Order{}
+
+	aa, err := impl.AssociateStorer.ListBySkillSetID(ctx, ss.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range aa.Results {
+		fields := applySkillSetToAssociate(a, ss)
+		if err := impl.reconcile(report, associatesCollectionName, a.ID, skillSetsCollectionName, ss.ID, fields, func() error {
+			return impl.AssociateStorer.UpdateByID(ctx, a)
+		}); err != nil {
+			return nil, err
+		}
+		if err := impl.resyncFromAssociate(ctx, report, a, orders); err != nil {
+			return nil, err
+		}
+	}
+
+	// The orders the associates were cascaded into are reused so we neither
+	// overwrite nor, on a dry run, report again the fields just refreshed.
+	oo, err := impl.OrderStorer.ListBySkillSetID(ctx, ss.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range oo.Results {
+		o := latest(orders, o.ID, o)
+		fields := applySkillSetToOrder(o, ss)
+		if err := impl.reconcile(report, ordersCollectionName, o.ID, skillSetsCollectionName, ss.ID, fields, func() error {
+			return impl.OrderStorer.UpdateByID(ctx, o)
+		}); err != nil {
+			return nil, err
+		}
+		if err := impl.resyncFromOrder(ctx, report, o); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+func (impl *ResyncControllerImpl) ResyncTag(ctx context.Context, tagID primitive.ObjectID, dryRun bool) (*ResyncReport, error) {
+	t, err := impl.TagStorer.GetByID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, fmt.Errorf("tag %v: %w", tagID.Hex(), ErrSourceNotFound)
+	}
+	report := &ResyncReport{DryRun: dryRun}
+	orders := map[primitive.ObjectID]*o_ds.Order{}
+
+	cc, err := impl.CustomerStorer.ListByTagID(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cc.Results {
+		fields := applyTagToCustomer(c, t)
+		if err := impl.reconcile(report, customersCollectionName, c.ID, tagsCollectionName, t.ID, fields, func() error {
+			return impl.CustomerStorer.UpdateByID(ctx, c)
+		}); err != nil {
+			return nil, err
+		}
+		if err := impl.resyncFromCustomer(ctx, report, c, orders); err != nil {
+			return nil, err
+		}
+	}
+
+	aa, err := impl.AssociateStorer.ListByTagID(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range aa.Results {
+		fields := applyTagToAssociate(a, t)
+		if err := impl.reconcile(report, associatesCollectionName, a.ID, tagsCollectionName, t.ID, fields, func() error {
+			return impl.AssociateStorer.UpdateByID(ctx, a)
+		}); err != nil {
+			return nil, err
+		}
+		if err := impl.resyncFromAssociate(ctx, report, a, orders); err != nil {
+			return nil, err
+		}
+	}
+
+	oo, err := impl.OrderStorer.ListByTagID(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range oo.Results {
+		o := latest(orders, o.ID, o)
+		fields := applyTagToOrder(o, t)
+		if err := impl.reconcile(report, ordersCollectionName, o.ID, tagsCollectionName, t.ID, fields, func() error {
+			return impl.OrderStorer.UpdateByID(ctx, o)
+		}); err != nil {
+			return nil, err
+		}
+		if err := impl.resyncFromOrder(ctx, report, o); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// resyncFromCustomer copies the customer into every order, task item and
+// comment which belongs to them, including the ones in the trash so no stale
+// copy survives to be restored. Orders already refreshed by the caller are
+// taken from `orders`, which may be nil.
+func (impl *ResyncControllerImpl) resyncFromCustomer(ctx context.Context, report *ResyncReport, c *c_ds.Customer, orders map[primitive.ObjectID]*o_ds.Order) error {
+	oo, err := impl.OrderStorer.ListByCustomerIDWithDeleted(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	for _, o := range oo.Results {
+		o := latest(orders, o.ID, o)
+		fields := applyCustomerToOrder(o, c)
+		if err := impl.reconcile(report, ordersCollectionName, o.ID, customersCollectionName, c.ID, fields, func() error {
+			return impl.OrderStorer.UpdateByID(ctx, o)
+		}); err != nil {
+			return err
+		}
+	}
+
+	tis, err := impl.TaskItemStorer.ListByCustomerIDWithDeleted(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	for _, ti := range tis.Results {
+		fields := applyCustomerToTaskItem(ti, c)
+		if err := impl.reconcile(report, taskItemsCollectionName, ti.ID, customersCollectionName, c.ID, fields, func() error {
+			return impl.TaskItemStorer.UpdateByID(ctx, ti)
+		}); err != nil {
+			return err
+		}
+	}
+
+	comms, err := impl.CommentStorer.ListByCustomerIDWithDeleted(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	for _, com := range comms.Results {
+		fields := applyCustomerToComment(com, c)
+		if err := impl.reconcile(report, commentsCollectionName, com.ID, customersCollectionName, c.ID, fields, func() error {
+			return impl.CommentStorer.UpdateByID(ctx, com)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resyncFromAssociate copies the associate into every order, task item,
+// activity sheet and comment which belongs to them, including the ones in the
+// trash. Orders already refreshed by the caller are taken from `orders`, which
+// may be nil.
+func (impl *ResyncControllerImpl) resyncFromAssociate(ctx context.Context, report *ResyncReport, a *a_ds.Associate, orders map[primitive.ObjectID]*o_ds.Order) error {
+	oo, err := impl.OrderStorer.ListByAssociateIDWithDeleted(ctx, a.ID)
+	if err != nil {
+		return err
+	}
+	for _, o := range oo.Results {
+		o := latest(orders, o.ID, o)
+		fields := applyAssociateToOrder(o, a)
+		if err := impl.reconcile(report, ordersCollectionName, o.ID, associatesCollectionName, a.ID, fields, func() error {
+			return impl.OrderStorer.UpdateByID(ctx, o)
+		}); err != nil {
+			return err
+		}
+	}
+
+	tis, err := impl.TaskItemStorer.ListByAssociateIDWithDeleted(ctx, a.ID)
+	if err != nil {
+		return err
+	}
+	for _, ti := range tis.Results {
+		fields := applyAssociateToTaskItem(ti, a)
+		if err := impl.reconcile(report, taskItemsCollectionName, ti.ID, associatesCollectionName, a.ID, fields, func() error {
+			return impl.TaskItemStorer.UpdateByID(ctx, ti)
+		}); err != nil {
+			return err
+		}
+	}
+
+	ass, err := impl.ActivitySheetStorer.ListByAssociateIDWithDeleted(ctx, a.ID)
+	if err != nil {
+		return err
+	}
+	for _, as := range ass.Results {
+		fields := applyAssociateToActivitySheet(as, a)
+		if err := impl.reconcile(report, activitySheetsCollectionName, as.ID, associatesCollectionName, a.ID, fields, func() error {
+			return impl.ActivitySheetStorer.UpdateByID(ctx, as)
+		}); err != nil {
+			return err
+		}
+	}
+
+	comms, err := impl.CommentStorer.ListByAssociateIDWithDeleted(ctx, a.ID)
+	if err != nil {
+		return err
+	}
+	for _, com := range comms.Results {
+		fields := applyAssociateToComment(com, a)
+		if err := impl.reconcile(report, commentsCollectionName, com.ID, associatesCollectionName, a.ID, fields, func() error {
+			return impl.CommentStorer.UpdateByID(ctx, com)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resyncFromOrder copies the order into every task item which belongs to it.
+func (impl *ResyncControllerImpl) resyncFromOrder(ctx context.Context, report *ResyncReport, o *o_ds.Order) error {
+	tis, err := impl.TaskItemStorer.ListByOrderID(ctx, o.ID)
+	if err != nil {
+		return err
+	}
+	for _, ti := range tis.Results {
+		fields := applyOrderToTaskItem(ti, o)
+		if err := impl.reconcile(report, taskItemsCollectionName, ti.ID, ordersCollectionName, o.ID, fields, func() error {
+			return impl.TaskItemStorer.UpdateByID(ctx, ti)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package controller
+
+import (
+	"reflect"
+	"time"
+
+	as_ds "github.com/over55/workery-cli/app/activitysheet/datastore"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	com_ds "github.com/over55/workery-cli/app/comment/datastore"
+	"github.com/over55/workery-cli/app/convert"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	sf_ds "github.com/over55/workery-cli/app/servicefee/datastore"
+	ss_ds "github.com/over55/workery-cli/app/skillset/datastore"
+	tag_ds "github.com/over55/workery-cli/app/tag/datastore"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+)
+
+// fieldSync keeps track of which fields were refreshed while copying values
+// from a source document into a document which embeds a copy of it.
+type fieldSync struct {
+	fields []string
+}
+
+func syncValue[T comparable](s *fieldSync, field string, dst *T, src T) {
+	if *dst != src {
+		*dst = src
+		s.fields = append(s.fields, field)
+	}
+}
+
+func syncTime(s *fieldSync, field string, dst *time.Time, src time.Time) {
+	if !dst.Equal(src) {
+		*dst = src
+		s.fields = append(s.fields, field)
+	}
+}
+
+// syncSlice treats a nil and an empty slice as equal since both are used
+// interchangeably throughout the import code.
+func syncSlice[T any](s *fieldSync, field string, dst *[]T, src []T) {
+	if len(*dst) == 0 && len(src) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(*dst, src) {
+		*dst = src
+		s.fields = append(s.fields, field)
+	}
+}
+
+// ------------------------------------------------ CUSTOMER ------------------------------------------------ //
+
+func applyCustomerToOrder(o *o_ds.Order, c *c_ds.Customer) []string {
+	s := &fieldSync{}
+	syncValue(s, "customer_organization_name", &o.CustomerOrganizationName, c.OrganizationName)
+	syncValue(s, "customer_organization_type", &o.CustomerOrganizationType, c.OrganizationType)
+	syncValue(s, "customer_public_id", &o.CustomerPublicID, c.PublicID)
+	syncValue(s, "customer_first_name", &o.CustomerFirstName, c.FirstName)
+	syncValue(s, "customer_last_name", &o.CustomerLastName, c.LastName)
+	syncValue(s, "customer_name", &o.CustomerName, c.Name)
+	syncValue(s, "customer_lexical_name", &o.CustomerLexicalName, c.LexicalName)
+	syncValue(s, "customer_gender", &o.CustomerGender, c.Gender)
+	syncValue(s, "customer_gender_other", &o.CustomerGenderOther, c.GenderOther)
+	syncTime(s, "customer_birthdate", &o.CustomerBirthdate, c.BirthDate)
+	syncValue(s, "customer_email", &o.CustomerEmail, c.Email)
+	syncValue(s, "customer_phone", &o.CustomerPhone, c.Phone)
+	syncValue(s, "customer_phone_type", &o.CustomerPhoneType, c.PhoneType)
+	syncValue(s, "customer_phone_extension", &o.CustomerPhoneExtension, c.PhoneExtension)
+	syncValue(s, "customer_other_phone", &o.CustomerOtherPhone, c.OtherPhone)
+	syncValue(s, "customer_other_phone_extension", &o.CustomerOtherPhoneExtension, c.OtherPhoneExtension)
+	syncValue(s, "customer_other_phone_type", &o.CustomerOtherPhoneType, c.OtherPhoneType)
+	syncValue(s, "customer_full_address_without_postal_code", &o.CustomerFullAddressWithoutPostalCode, c.FullAddressWithoutPostalCode)
+	syncValue(s, "customer_full_address_url", &o.CustomerFullAddressURL, c.FullAddressURL)
+	syncSlice(s, "customer_tags", &o.CustomerTags, convert.ToOrderTagsFromCustomerTags(c.Tags))
+	return s.fields
+}
+
+func applyCustomerToTaskItem(ti *ti_ds.TaskItem, c *c_ds.Customer) []string {
+	s := &fieldSync{}
+	syncValue(s, "customer_organization_name", &ti.CustomerOrganizationName, c.OrganizationName)
+	syncValue(s, "customer_organization_type", &ti.CustomerOrganizationType, c.OrganizationType)
+	syncValue(s, "customer_public_id", &ti.CustomerPublicID, c.PublicID)
+	syncValue(s, "customer_first_name", &ti.CustomerFirstName, c.FirstName)
+	syncValue(s, "customer_last_name", &ti.CustomerLastName, c.LastName)
+	syncValue(s, "customer_name", &ti.CustomerName, c.Name)
+	syncValue(s, "customer_lexical_name", &ti.CustomerLexicalName, c.LexicalName)
+	syncValue(s, "customer_gender", &ti.CustomerGender, c.Gender)
+	syncValue(s, "customer_gender_other", &ti.CustomerGenderOther, c.GenderOther)
+	syncTime(s, "customer_birthdate", &ti.CustomerBirthdate, c.BirthDate)
+	syncValue(s, "customer_email", &ti.CustomerEmail, c.Email)
+	syncValue(s, "customer_phone", &ti.CustomerPhone, c.Phone)
+	syncValue(s, "customer_phone_type", &ti.CustomerPhoneType, c.PhoneType)
+	syncValue(s, "customer_phone_extension", &ti.CustomerPhoneExtension, c.PhoneExtension)
+	syncValue(s, "customer_other_phone", &ti.CustomerOtherPhone, c.OtherPhone)
+	syncValue(s, "customer_other_phone_extension", &ti.CustomerOtherPhoneExtension, c.OtherPhoneExtension)
+	syncValue(s, "customer_other_phone_type", &ti.CustomerOtherPhoneType, c.OtherPhoneType)
+	syncValue(s, "customer_full_address_without_postal_code", &ti.CustomerFullAddressWithoutPostalCode, c.FullAddressWithoutPostalCode)
+	syncValue(s, "customer_full_address_url", &ti.CustomerFullAddressURL, c.FullAddressURL)
+	syncSlice(s, "customer_tags", &ti.CustomerTags, convert.ToTaskItemTagsFromCustomerTags(c.Tags))
+	return s.fields
+}
+
+func applyCustomerToComment(com *com_ds.Comment, c *c_ds.Customer) []string {
+	s := &fieldSync{}
+	syncValue(s, "customer_name", &com.CustomerName, c.Name)
+	return s.fields
+}
+
+// ------------------------------------------------ ASSOCIATE ------------------------------------------------ //
+
+func applyAssociateToOrder(o *o_ds.Order, a *a_ds.Associate) []string {
+	s := &fieldSync{}
+	syncValue(s, "associate_organization_name", &o.AssociateOrganizationName, a.OrganizationName)
+	syncValue(s, "associate_organization_type", &o.AssociateOrganizationType, a.OrganizationType)
+	syncValue(s, "associate_public_id", &o.AssociatePublicID, a.PublicID)
+	syncValue(s, "associate_first_name", &o.AssociateFirstName, a.FirstName)
+	syncValue(s, "associate_last_name", &o.AssociateLastName, a.LastName)
+	syncValue(s, "associate_name", &o.AssociateName, a.Name)
+	syncValue(s, "associate_lexical_name", &o.AssociateLexicalName, a.LexicalName)
+	syncValue(s, "associate_gender", &o.AssociateGender, a.Gender)
+	syncValue(s, "associate_gender_other", &o.AssociateGenderOther, a.GenderOther)
+	syncTime(s, "associate_birthdate", &o.AssociateBirthdate, a.BirthDate)
+	syncValue(s, "associate_email", &o.AssociateEmail, a.Email)
+	syncValue(s, "associate_phone", &o.AssociatePhone, a.Phone)
+	syncValue(s, "associate_phone_type", &o.AssociatePhoneType, a.PhoneType)
+	syncValue(s, "associate_phone_extension", &o.AssociatePhoneExtension, a.PhoneExtension)
+	syncValue(s, "associate_other_phone", &o.AssociateOtherPhone, a.OtherPhone)
+	syncValue(s, "associate_other_phone_extension", &o.AssociateOtherPhoneExtension, a.OtherPhoneExtension)
+	syncValue(s, "associate_other_phone_type", &o.AssociateOtherPhoneType, a.OtherPhoneType)
+	syncValue(s, "associate_full_address_without_postal_code", &o.AssociateFullAddressWithoutPostalCode, a.FullAddressWithoutPostalCode)
+	syncValue(s, "associate_full_address_url", &o.AssociateFullAddressURL, a.FullAddressURL)
+	syncSlice(s, "associate_tags", &o.AssociateTags, convert.ToOrderTagsFromAssociateTags(a.Tags))
+	syncSlice(s, "associate_skill_sets", &o.AssociateSkillSets, convert.ToOrderSkillSetsFromAssociateSkillSets(a.SkillSets))
+	syncSlice(s, "associate_insurance_requirements", &o.AssociateInsuranceRequirements, convert.ToOrderInsuranceRequirementsFromAssociateInsuranceRequirements(a.InsuranceRequirements))
+	syncSlice(s, "associate_vehicle_types", &o.AssociateVehicleTypes, convert.ToOrderVehicleTypesFromAssociateVehicleTypes(a.VehicleTypes))
+	syncValue(s, "associate_tax_id", &o.AssociateTaxID, a.TaxID)
+	syncValue(s, "associate_service_fee_id", &o.AssociateServiceFeeID, a.ServiceFeeID)
+	syncValue(s, "associate_service_fee_name", &o.AssociateServiceFeeName, a.ServiceFeeName)
+	syncValue(s, "associate_service_fee_percentage", &o.AssociateServiceFeePercentage, a.ServiceFeePercentage)
+	return s.fields
+}
+
+func applyAssociateToTaskItem(ti *ti_ds.TaskItem, a *a_ds.Associate) []string {
+	s := &fieldSync{}
+	syncValue(s, "associate_organization_name", &ti.AssociateOrganizationName, a.OrganizationName)
+	syncValue(s, "associate_organization_type", &ti.AssociateOrganizationType, a.OrganizationType)
+	syncValue(s, "associate_public_id", &ti.AssociatePublicID, a.PublicID)
+	syncValue(s, "associate_first_name", &ti.AssociateFirstName, a.FirstName)
+	syncValue(s, "associate_last_name", &ti.AssociateLastName, a.LastName)
+	syncValue(s, "associate_name", &ti.AssociateName, a.Name)
+	syncValue(s, "associate_lexical_name", &ti.AssociateLexicalName, a.LexicalName)
+	syncValue(s, "associate_gender", &ti.AssociateGender, a.Gender)
+	syncValue(s, "associate_gender_other", &ti.AssociateGenderOther, a.GenderOther)
+	syncTime(s, "associate_birthdate", &ti.AssociateBirthdate, a.BirthDate)
+	syncValue(s, "associate_email", &ti.AssociateEmail, a.Email)
+	syncValue(s, "associate_phone", &ti.AssociatePhone, a.Phone)
+	syncValue(s, "associate_phone_type", &ti.AssociatePhoneType, a.PhoneType)
+	syncValue(s, "associate_phone_extension", &ti.AssociatePhoneExtension, a.PhoneExtension)
+	syncValue(s, "associate_other_phone", &ti.AssociateOtherPhone, a.OtherPhone)
+	syncValue(s, "associate_other_phone_extension", &ti.AssociateOtherPhoneExtension, a.OtherPhoneExtension)
+	syncValue(s, "associate_other_phone_type", &ti.AssociateOtherPhoneType, a.OtherPhoneType)
+	syncValue(s, "associate_full_address_without_postal_code", &ti.AssociateFullAddressWithoutPostalCode, a.FullAddressWithoutPostalCode)
+	syncValue(s, "associate_full_address_url", &ti.AssociateFullAddressURL, a.FullAddressURL)
+	syncSlice(s, "associate_tags", &ti.AssociateTags, convert.ToTaskItemTagsFromAssociateTags(a.Tags))
+	syncSlice(s, "associate_skill_sets", &ti.AssociateSkillSets, convert.ToTaskItemSkillSetsFromAssociateSkillSets(a.SkillSets))
+	syncSlice(s, "associate_insurance_requirements", &ti.AssociateInsuranceRequirements, convert.ToTaskItemInsuranceRequirementsFromAssociateInsuranceRequirements(a.InsuranceRequirements))
+	syncSlice(s, "associate_vehicle_types", &ti.AssociateVehicleTypes, convert.ToTaskItemVehicleTypesFromAssociateVehicleTypes(a.VehicleTypes))
+	syncValue(s, "associate_tax_id", &ti.AssociateTaxID, a.TaxID)
+	syncValue(s, "associate_service_fee_id", &ti.AssociateServiceFeeID, a.ServiceFeeID)
+	syncValue(s, "associate_service_fee_name", &ti.AssociateServiceFeeName, a.ServiceFeeName)
+	syncValue(s, "associate_service_fee_percentage", &ti.AssociateServiceFeePercentage, a.ServiceFeePercentage)
+	return s.fields
+}
+
+func applyAssociateToActivitySheet(as *as_ds.ActivitySheet, a *a_ds.Associate) []string {
+	s := &fieldSync{}
+	syncValue(s, "associate_organization_name", &as.AssociateOrganizationName, a.OrganizationName)
+	syncValue(s, "associate_organization_type", &as.AssociateOrganizationType, a.OrganizationType)
+	syncValue(s, "associate_name", &as.AssociateName, a.Name)
+	syncValue(s, "associate_lexical_name", &as.AssociateLexicalName, a.LexicalName)
+	return s.fields
+}
+
+func applyAssociateToComment(com *com_ds.Comment, a *a_ds.Associate) []string {
+	s := &fieldSync{}
+	syncValue(s, "associate_name", &com.AssociateName, a.Name)
+	return s.fields
+}
+
+// ------------------------------------------------ ORDER ------------------------------------------------ //
+
+func applyOrderToTaskItem(ti *ti_ds.TaskItem, o *o_ds.Order) []string {
+	s := &fieldSync{}
+	syncValue(s, "order_type", &ti.OrderType, o.Type)
+	syncValue(s, "order_wjid", &ti.OrderWJID, o.WJID)
+	syncTime(s, "order_start_date", &ti.OrderStartDate, o.StartDate)
+	syncValue(s, "order_description", &ti.OrderDescription, o.Description)
+	syncSlice(s, "order_skill_sets", &ti.OrderSkillSets, convert.ToTaskItemSkillSetsFromOrderSkillSets(o.SkillSets))
+	syncSlice(s, "order_tags", &ti.OrderTags, convert.ToTaskItemTagsFromOrderTags(o.Tags))
+	return s.fields
+}
+
+// ------------------------------------------------ SERVICE FEE ------------------------------------------------ //
+
+func applyServiceFeeToAssociate(a *a_ds.Associate, sf *sf_ds.ServiceFee) []string {
+	s := &fieldSync{}
+	if a.ServiceFeeID != sf.ID {
+		return s.fields
+	}
+	syncValue(s, "service_fee_name", &a.ServiceFeeName, sf.Name)
+	syncValue(s, "service_fee_percentage", &a.ServiceFeePercentage, sf.Percentage)
+	return s.fields
+}
+
+// ------------------------------------------------ SKILL SET ------------------------------------------------ //
+
+func applySkillSetToAssociate(a *a_ds.Associate, ss *ss_ds.SkillSet) []string {
+	s := &fieldSync{}
+	for _, item := range a.SkillSets {
+		if item.ID != ss.ID {
+			continue
+		}
+		syncValue(s, "skill_sets.category", &item.Category, ss.Category)
+		syncValue(s, "skill_sets.sub_category", &item.SubCategory, ss.SubCategory)
+		syncValue(s, "skill_sets.description", &item.Description, ss.Description)
+		syncValue(s, "skill_sets.status", &item.Status, ss.Status)
+	}
+	return s.fields
+}
+
+func applySkillSetToOrder(o *o_ds.Order, ss *ss_ds.SkillSet) []string {
+	s := &fieldSync{}
+	for _, item := range o.SkillSets {
+		if item.ID != ss.ID {
+			continue
+		}
+		syncValue(s, "skill_sets.category", &item.Category, ss.Category)
+		syncValue(s, "skill_sets.sub_category", &item.SubCategory, ss.SubCategory)
+		syncValue(s, "skill_sets.description", &item.Description, ss.Description)
+		syncValue(s, "skill_sets.status", &item.Status, ss.Status)
+	}
+	return s.fields
+}
+
+// ------------------------------------------------ TAG ------------------------------------------------ //
+
+func applyTagToCustomer(c *c_ds.Customer, t *tag_ds.Tag) []string {
+	s := &fieldSync{}
+	for _, item := range c.Tags {
+		if item.ID != t.ID {
+			continue
+		}
+		syncValue(s, "tags.text", &item.Text, t.Text)
+		syncValue(s, "tags.description", &item.Description, t.Description)
+		syncValue(s, "tags.status", &item.Status, t.Status)
+	}
+	return s.fields
+}
+
+func applyTagToAssociate(a *a_ds.Associate, t *tag_ds.Tag) []string {
+	s := &fieldSync{}
+	for _, item := range a.Tags {
+		if item.ID != t.ID {
+			continue
+		}
+		syncValue(s, "tags.text", &item.Text, t.Text)
+		syncValue(s, "tags.description", &item.Description, t.Description)
+		syncValue(s, "tags.status", &item.Status, t.Status)
+	}
+	return s.fields
+}
+
+func applyTagToOrder(o *o_ds.Order, t *tag_ds.Tag) []string {
+	s := &fieldSync{}
+	for _, item := range o.Tags {
+		if item.ID != t.ID {
+			continue
+		}
+		syncValue(s, "tags.text", &item.Text, t.Text)
+		syncValue(s, "tags.description", &item.Description, t.Description)
+		syncValue(s, "tags.status", &item.Status, t.Status)
+	}
+	return s.fields
+}
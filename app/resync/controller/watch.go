@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	rt_ds "github.com/over55/workery-cli/app/resync/datastore"
+)
+
+// changeEvent is the subset of a change stream event we need.
+type changeEvent struct {
+	OperationType string `bson:"operationType"`
+	DocumentKey   struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	UpdateDescription struct {
+		UpdatedFields bson.M   `bson:"updatedFields"`
+		RemovedFields []string `bson:"removedFields"`
+	} `bson:"updateDescription"`
+}
+
+// watchedCollection describes how changes to a source collection are pushed
+// into the documents which embed copies of it.
+type watchedCollection struct {
+	name string
+
+	// fields are the top-level fields of the source which are copied into
+	// other documents; updates to any other field are ignored.
+	fields map[string]bool
+
+	resync func(ctx context.Context, id primitive.ObjectID) (*ResyncReport, error)
+}
+
+func newFieldSet(fields ...string) map[string]bool {
+	m := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		m[f] = true
+	}
+	return m
+}
+
+func (impl *ResyncControllerImpl) watchedCollections() []*watchedCollection {
+	return []*watchedCollection{
+		{
+			name: customersCollectionName,
+			fields: newFieldSet("organization_name", "organization_type", "public_id", "first_name", "last_name", "name", "lexical_name",
+				"gender", "gender_other", "birth_date", "email", "phone", "phone_type", "phone_extension", "other_phone",
+				"other_phone_extension", "other_phone_type", "full_address_without_postal_code", "full_address_url", "tags"),
+			resync: func(ctx context.Context, id primitive.ObjectID) (*ResyncReport, error) {
+				return impl.ResyncCustomer(ctx, id, false)
+			},
+		},
+		{
+			name: associatesCollectionName,
+			fields: newFieldSet("organization_name", "organization_type", "public_id", "first_name", "last_name", "name", "lexical_name",
+				"gender", "gender_other", "birth_date", "email", "phone", "phone_type", "phone_extension", "other_phone",
+				"other_phone_extension", "other_phone_type", "full_address_without_postal_code", "full_address_url", "tags",
+				"skill_sets", "insurance_requirements", "vehicle_types", "tax_id", "service_fee_id", "service_fee_name",
+				"service_fee_percentage"),
+			resync: func(ctx context.Context, id primitive.ObjectID) (*ResyncReport, error) {
+				return impl.ResyncAssociate(ctx, id, false)
+			},
+		},
+		{
+			name:   serviceFeesCollectionName,
+			fields: newFieldSet("name", "percentage"),
+			resync: func(ctx context.Context, id primitive.ObjectID) (*ResyncReport, error) {
+				return impl.ResyncServiceFee(ctx, id, false)
+			},
+		},
+		{
+			name:   skillSetsCollectionName,
+			fields: newFieldSet("category", "sub_category", "description", "status"),
+			resync: func(ctx context.Context, id primitive.ObjectID) (*ResyncReport, error) {
+				return impl.ResyncSkillSet(ctx, id, false)
+			},
+		},
+		{
+			name:   tagsCollectionName,
+			fields: newFieldSet("text", "description", "status"),
+			resync: func(ctx context.Context, id primitive.ObjectID) (*ResyncReport, error) {
+				return impl.ResyncTag(ctx, id, false)
+			},
+		},
+	}
+}
+
+// Watch opens a change stream on every source collection and resyncs the
+// embedded copies of each document as soon as it is updated. The position
+// of every stream is saved after each event is handled so a restart picks
+// up where the last run stopped. When `fromNow` is true the saved positions
+// are discarded and only new changes are processed. Watch blocks until the
+// context is cancelled or one of the streams fails.
+func (impl *ResyncControllerImpl) Watch(ctx context.Context, fromNow bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wcs := impl.watchedCollections()
+	errCh := make(chan error, len(wcs))
+	for _, wc := range wcs {
+		go func(wc *watchedCollection) {
+			errCh <- impl.watchCollection(ctx, wc, fromNow)
+		}(wc)
+	}
+
+	// Stop every stream once the first one returns.
+	err := <-errCh
+	cancel()
+	for i := 1; i < len(wcs); i++ {
+		<-errCh
+	}
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+func (impl *ResyncControllerImpl) watchCollection(ctx context.Context, wc *watchedCollection, fromNow bool) error {
+	if fromNow {
+		if err := impl.ResumeTokenStorer.DeleteByStreamName(ctx, wc.name); err != nil {
+			return err
+		}
+	}
+
+	opts := options.ChangeStream()
+	rt, err := impl.ResumeTokenStorer.GetByStreamName(ctx, wc.name)
+	if err != nil {
+		return err
+	}
+	if rt != nil {
+		opts.SetResumeAfter(rt.Token)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"operationType": bson.M{"$in": bson.A{"update", "replace"}}}}},
+	}
+	coll := impl.DbClient.Database(impl.Config.DB.Name).Collection(wc.name)
+	cs, err := coll.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return fmt.Errorf("failed opening change stream on %v: %w", wc.name, err)
+	}
+	defer cs.Close(context.Background())
+
+	impl.Logger.Info("watching for changes",
+		slog.String("collection", wc.name),
+		slog.Bool("resumed", rt != nil))
+
+	for cs.Next(ctx) {
+		var event changeEvent
+		if err := cs.Decode(&event); err != nil {
+			return err
+		}
+
+		if wc.isRelevant(&event) {
+			report, err := wc.resync(ctx, event.DocumentKey.ID)
+			if errors.Is(err, ErrSourceNotFound) {
+				// The document was removed before we got to it so there is
+				// nothing left to copy.
+				impl.Logger.Warn("skipping change to missing document",
+					slog.String("collection", wc.name),
+					slog.Any("id", event.DocumentKey.ID))
+				report, err = &ResyncReport{}, nil
+			}
+			if err != nil {
+				// Do not save the position so this event is handled again
+				// on the next run.
+				return fmt.Errorf("failed resyncing %v %v: %w", wc.name, event.DocumentKey.ID.Hex(), err)
+			}
+			impl.Logger.Info("resynced embedded copies",
+				slog.String("collection", wc.name),
+				slog.Any("id", event.DocumentKey.ID),
+				slog.Int("documents", len(report.Drifts)))
+		}
+
+		if err := impl.ResumeTokenStorer.UpsertByStreamName(ctx, &rt_ds.ResumeToken{
+			StreamName: wc.name,
+			Token:      cs.ResumeToken(),
+		}); err != nil {
+			return err
+		}
+	}
+	if err := cs.Err(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// isRelevant returns true if the event changed any of the fields which are
+// copied into other documents. Replacements are always relevant.
+func (wc *watchedCollection) isRelevant(event *changeEvent) bool {
+	if event.OperationType != "update" {
+		return true
+	}
+	for field := range event.UpdateDescription.UpdatedFields {
+		if wc.fields[strings.SplitN(field, ".", 2)[0]] {
+			return true
+		}
+	}
+	for _, field := range event.UpdateDescription.RemovedFields {
+		if wc.fields[strings.SplitN(field, ".", 2)[0]] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	c "github.com/over55/workery-cli/config"
+)
+
+// ResumeToken is the position a change stream reached so that a restarted
+// listener can continue from where the previous one stopped.
+type ResumeToken struct {
+	StreamName string    `bson:"_id" json:"stream_name"`
+	Token      bson.Raw  `bson:"token" json:"token"`
+	ModifiedAt time.Time `bson:"modified_at" json:"modified_at"`
+}
+
+// ResumeTokenStorer Interface for change stream resume tokens.
+type ResumeTokenStorer interface {
+	GetByStreamName(ctx context.Context, streamName string) (*ResumeToken, error)
+	UpsertByStreamName(ctx context.Context, m *ResumeToken) error
+	DeleteByStreamName(ctx context.Context, streamName string) error
+}
+
+type ResumeTokenStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) ResumeTokenStorer {
+	// ctx := context.Background()
+	uc := client.Database(appCfg.DB.Name).Collection("change_stream_resume_tokens")
+
+	s := &ResumeTokenStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+	return s
+}
@@ -0,0 +1,16 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (impl ResumeTokenStorerImpl) DeleteByStreamName(ctx context.Context, streamName string) error {
+	if _, err := impl.Collection.DeleteOne(ctx, bson.M{"_id": streamName}); err != nil {
+		impl.Logger.Error("database delete resume token error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
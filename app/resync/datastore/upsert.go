@@ -0,0 +1,26 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (impl ResumeTokenStorerImpl) UpsertByStreamName(ctx context.Context, m *ResumeToken) error {
+	m.ModifiedAt = time.Now()
+
+	opts := options.Update().SetUpsert(true) // Use upsert option
+
+	filter := bson.M{"_id": m.StreamName}
+
+	update := bson.M{"$set": m}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update, opts); err != nil {
+		impl.Logger.Error("database upsert resume token error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
@@ -11,7 +11,7 @@ import (
 )
 
 func (impl ServiceFeeStorerImpl) ListByFilter(ctx context.Context, f *ServiceFeePaginationListFilter) (*ServiceFeePaginationListResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Create the paginated filter based on the cursor
@@ -48,6 +48,11 @@ func (impl ServiceFeeStorerImpl) ListByFilter(ctx context.Context, f *ServiceFee
 	// 	options.SetSort(bson.D{{"score", bson.M{"$meta": "textScore"}}})
 	// }
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Execute the query
 	cursor, err := impl.Collection.Find(ctx, filter, options)
 	if err != nil {
@@ -27,6 +27,9 @@ type ServiceFeePaginationListFilter struct {
 	TenantID   primitive.ObjectID
 	Status     int8
 	SearchText string
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 // ServiceFeePaginationListResult represents the paginated list results for
@@ -11,7 +11,7 @@ import (
 )
 
 func (impl StaffStorerImpl) ListByFilter(ctx context.Context, f *StaffPaginationListFilter) (*StaffPaginationListResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	filter, err := impl.newPaginationFilter(f)
@@ -70,6 +70,11 @@ func (impl StaffStorerImpl) ListByFilter(ctx context.Context, f *StaffPagination
 		options.SetSort(bson.D{{"score", bson.M{"$meta": "textScore"}}})
 	}
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Execute the query
 	cursor, err := impl.Collection.Find(ctx, filter, options)
 	if err != nil {
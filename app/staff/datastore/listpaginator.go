@@ -44,6 +44,9 @@ type StaffPaginationListFilter struct {
 	// AllVehicleTypeIDs filter is used if you want to find all tag ids for
 	// the associate.
 	AllVehicleTypeIDs []primitive.ObjectID
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 // StaffPaginationLiteListResult represents the paginated list results for
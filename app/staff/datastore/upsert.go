@@ -2,22 +2,35 @@ package datastore
 
 import (
 	"context"
+	"log/slog"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
+
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 )
 
 func (impl StaffStorerImpl) UpsertByID(ctx context.Context, user *Staff) error {
+	// Get the previous state of the document so we can audit the change.
+	prev, err := impl.GetByID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
 	opts := options.Update().SetUpsert(true) // Use upsert option
 
 	filter := bson.M{"_id": user.ID}
 
 	update := bson.M{"$set": user}
 
-	_, err := impl.Collection.UpdateOne(ctx, filter, update, opts)
+	_, err = impl.Collection.UpdateOne(ctx, filter, update, opts)
 	if err != nil {
 		return err
 	}
 
+	if err := impl.AuditLogStorer.RecordChange(ctx, impl.Collection.Name(), auditlog_ds.AuditLogActionUpsert, user.TenantID, user.ID, prev, user); err != nil {
+		impl.Logger.Warn("failed recording audit log", slog.Any("error", err))
+	}
+
 	return nil
 }
@@ -0,0 +1,182 @@
+package builder
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	st_c "github.com/over55/workery-cli/app/statement/controller"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatPDF  = "pdf"
+
+	pageMargin = 15.0
+	dateLayout = "2006-01-02"
+)
+
+// Build returns the statements in the format, either `csv`, `json` or `pdf`.
+func Build(format string, statements []*st_c.Statement) ([]byte, error) {
+	switch format {
+	case FormatCSV:
+		return BuildCSV(statements)
+	case FormatJSON:
+		return BuildJSON(statements)
+	case FormatPDF:
+		return BuildPDF(statements)
+	}
+	return nil, fmt.Errorf("unsupported format: %v", format)
+}
+
+// BuildCSV returns one row per statement line.
+func BuildCSV(statements []*st_c.Statement) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{
+		"associate_id", "associate_name", "wjid", "completion_date", "customer_name", "labour_amount", "material_amount",
+		"service_fee_name", "service_fee_percentage", "service_fee_amount", "service_fee_paid", "service_fee_payment_date",
+		"balance_owing",
+	})
+	for _, s := range statements {
+		for _, l := range s.Lines {
+			w.Write([]string{
+				s.AssociateID.Hex(),
+				s.AssociateName,
+				fmt.Sprint(l.WJID),
+				formatDate(l.CompletionDate),
+				l.CustomerName,
+				formatAmount(l.LabourAmount),
+				formatAmount(l.MaterialAmount),
+				l.ServiceFeeName,
+				fmt.Sprint(l.ServiceFeePercentage),
+				formatAmount(l.ServiceFeeAmount),
+				formatAmount(l.ServiceFeePaid),
+				formatDate(l.ServiceFeePaymentDate),
+				formatAmount(l.BalanceOwing),
+			})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// BuildJSON returns the statements as a JSON array.
+func BuildJSON(statements []*st_c.Statement) ([]byte, error) {
+	if statements == nil {
+		statements = []*st_c.Statement{}
+	}
+	return json.MarshalIndent(statements, "", "  ")
+}
+
+// BuildPDF returns a document with every statement starting on a new page.
+func BuildPDF(statements []*st_c.Statement) ([]byte, error) {
+	pdf := fpdf.New("L", "mm", "Letter", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	cols := []struct {
+		title string
+		width float64
+		align string
+	}{
+		{"Job #", 18, "L"},
+		{"Completed", 24, "L"},
+		{"Customer", 60, "L"},
+		{"Labour", 24, "R"},
+		{"Material", 24, "R"},
+		{"Fee %", 16, "R"},
+		{"Fee", 24, "R"},
+		{"Paid", 24, "R"},
+		{"Owing", 24, "R"},
+	}
+	header := func() {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for _, c := range cols {
+			pdf.CellFormat(c.width, 7, c.title, "1", 0, c.align, true, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	if len(statements) == 0 {
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.CellFormat(0, 8, "No statements for the period.", "", 1, "L", false, 0, "")
+	}
+	for _, s := range statements {
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "B", 16)
+		pdf.CellFormat(0, 9, tr("Service Fee Statement - "+s.AssociateName), "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, fmt.Sprintf("Period: %v to %v", formatDate(s.From), formatDate(s.To.AddDate(0, 0, -1))), "", 1, "L", false, 0, "")
+		pdf.CellFormat(0, 6, "Generated: "+formatDate(s.GeneratedAt), "", 1, "L", false, 0, "")
+		pdf.Ln(4)
+
+		header()
+		pdf.SetFont("Helvetica", "", 9)
+		_, pageHeight := pdf.GetPageSize()
+		for _, l := range s.Lines {
+			if pdf.GetY()+6 > pageHeight-pageMargin {
+				pdf.AddPage()
+				header()
+				pdf.SetFont("Helvetica", "", 9)
+			}
+			for i, v := range []string{
+				fmt.Sprint(l.WJID),
+				formatDate(l.CompletionDate),
+				tr(l.CustomerName),
+				formatAmount(l.LabourAmount),
+				formatAmount(l.MaterialAmount),
+				fmt.Sprint(l.ServiceFeePercentage),
+				formatAmount(l.ServiceFeeAmount),
+				formatAmount(l.ServiceFeePaid),
+				formatAmount(l.BalanceOwing),
+			} {
+				pdf.CellFormat(cols[i].width, 6, v, "1", 0, cols[i].align, false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "B", 10)
+		for _, t := range []struct {
+			label string
+			value float64
+		}{
+			{"Service fees for the period", s.TotalServiceFee},
+			{"Paid for the period", s.TotalPaid},
+			{"Owing for the period", s.TotalOwing},
+			{"Total balance owing", s.BalanceOwingAmount},
+		} {
+			pdf.CellFormat(70, 6, t.label, "", 0, "L", false, 0, "")
+			pdf.CellFormat(30, 6, "$"+formatAmount(t.value), "", 1, "R", false, 0, "")
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+func formatAmount(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	sf_ds "github.com/over55/workery-cli/app/servicefee/datastore"
+	c "github.com/over55/workery-cli/config"
+)
+
+// Statement lists the service fees an associate was charged for the orders
+// they completed during a period.
+type Statement struct {
+	AssociateID   primitive.ObjectID `json:"associate_id"`
+	AssociateName string             `json:"associate_name"`
+	From          time.Time          `json:"from"`
+	To            time.Time          `json:"to"`
+	Lines         []*StatementLine   `json:"lines"`
+
+	// Totals of the lines of the statement.
+	TotalServiceFee float64 `json:"total_service_fee"`
+	TotalPaid       float64 `json:"total_paid"`
+	TotalOwing      float64 `json:"total_owing"`
+
+	// BalanceOwingAmount is what the associate owes across every completed
+	// order, not only the ones in the period.
+	BalanceOwingAmount float64   `json:"balance_owing_amount"`
+	GeneratedAt        time.Time `json:"generated_at"`
+}
+
+// StatementLine is the service fee of a single completed order.
+type StatementLine struct {
+	OrderID               primitive.ObjectID `json:"order_id"`
+	WJID                  uint64             `json:"wjid"`
+	CompletionDate        time.Time          `json:"completion_date"`
+	CustomerName          string             `json:"customer_name"`
+	LabourAmount          float64            `json:"labour_amount"`
+	MaterialAmount        float64            `json:"material_amount"`
+	ServiceFeeName        string             `json:"service_fee_name"`
+	ServiceFeePercentage  float64            `json:"service_fee_percentage"`
+	ServiceFeeAmount      float64            `json:"service_fee_amount"`
+	ServiceFeePaid        float64            `json:"service_fee_paid"`
+	ServiceFeePaymentDate time.Time          `json:"service_fee_payment_date"`
+	BalanceOwing          float64            `json:"balance_owing"`
+}
+
+// StatementController Interface for computing the service fees associates
+// owe on their completed orders and producing their statements.
+type StatementController interface {
+	Generate(ctx context.Context, associateID primitive.ObjectID, from time.Time, to time.Time, dryRun bool) (*Statement, error)
+	GenerateAll(ctx context.Context, from time.Time, to time.Time, dryRun bool) ([]*Statement, error)
+}
+
+type StatementControllerImpl struct {
+	Config          *c.Conf
+	Logger          *slog.Logger
+	AssociateStorer a_ds.AssociateStorer
+	OrderStorer     o_ds.OrderStorer
+	ServiceFeeStore sf_ds.ServiceFeeStorer
+}
+
+func NewController(
+	appCfg *c.Conf,
+	loggerp *slog.Logger,
+	aStorer a_ds.AssociateStorer,
+	oStorer o_ds.OrderStorer,
+	sfStorer sf_ds.ServiceFeeStorer,
+) StatementController {
+	s := &StatementControllerImpl{
+		Config:          appCfg,
+		Logger:          loggerp,
+		AssociateStorer: aStorer,
+		OrderStorer:     oStorer,
+		ServiceFeeStore: sfStorer,
+	}
+	return s
+}
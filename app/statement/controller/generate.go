@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	"github.com/over55/workery-cli/app/order/ledger"
+	"github.com/over55/workery-cli/app/order/lifecycle"
+	sf_ds "github.com/over55/workery-cli/app/servicefee/datastore"
+)
+
+var ErrAssociateNotFound = errors.New("associate does not exist")
+
+// Generate computes the service fee of every completed order of the associate,
+// updates their balance owing and returns the statement of the orders which
+// were completed from `from` (inclusive) to `to` (exclusive). When `dryRun`
+// is true nothing is saved.
+func (impl *StatementControllerImpl) Generate(ctx context.Context, associateID primitive.ObjectID, from time.Time, to time.Time, dryRun bool) (*Statement, error) {
+	a, err := impl.AssociateStorer.GetByID(ctx, associateID)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrAssociateNotFound
+	}
+	return impl.generate(ctx, a, from, to, dryRun, map[primitive.ObjectID]*sf_ds.ServiceFee{})
+}
+
+// GenerateAll returns the statement of every associate who completed an order
+// during the period or still owes a balance.
+func (impl *StatementControllerImpl) GenerateAll(ctx context.Context, from time.Time, to time.Time, dryRun bool) ([]*Statement, error) {
+	aa, err := impl.AssociateStorer.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fees := map[primitive.ObjectID]*sf_ds.ServiceFee{}
+	var statements []*Statement
+	for _, a := range aa.Results {
+		s, err := impl.generate(ctx, a, from, to, dryRun, fees)
+		if err != nil {
+			return nil, err
+		}
+		if len(s.Lines) > 0 || s.BalanceOwingAmount != 0 {
+			statements = append(statements, s)
+		}
+	}
+	return statements, nil
+}
+
+func (impl *StatementControllerImpl) generate(ctx context.Context, a *a_ds.Associate, from time.Time, to time.Time, dryRun bool, fees map[primitive.ObjectID]*sf_ds.ServiceFee) (*Statement, error) {
+	s := &Statement{
+		AssociateID:   a.ID,
+		AssociateName: a.Name,
+		From:          from,
+		To:            to,
+		Lines:         []*StatementLine{},
+		GeneratedAt:   time.Now(),
+	}
+
+	oo, err := impl.OrderStorer.ListByAssociateID(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var balanceOwing float64
+	for _, o := range oo.Results {
+		if !isBillable(o) {
+			continue
+		}
+
+		changed, err := impl.applyServiceFee(ctx, a, o, fees)
+		if err != nil {
+			return nil, err
+		}
+		if changed && !dryRun {
+			if err := impl.OrderStorer.UpdateByID(ctx, o); err != nil {
+				return nil, fmt.Errorf("failed saving service fee of order %v: %w", o.WJID, err)
+			}
+		}
+		balanceOwing += o.InvoiceBalanceOwingAmount
+
+		if o.CompletionDate.Before(from) || !o.CompletionDate.Before(to) {
+			continue
+		}
+		s.Lines = append(s.Lines, &StatementLine{
+			OrderID:               o.ID,
+			WJID:                  o.WJID,
+			CompletionDate:        o.CompletionDate,
+			CustomerName:          o.CustomerName,
+			LabourAmount:          o.InvoiceLabourAmount,
+			MaterialAmount:        o.InvoiceMaterialAmount,
+			ServiceFeeName:        o.InvoiceServiceFeeName,
+			ServiceFeePercentage:  o.InvoiceServiceFeePercentage,
+			ServiceFeeAmount:      o.InvoiceServiceFeeAmount,
+			ServiceFeePaid:        o.InvoiceActualServiceFeeAmountPaid,
+			ServiceFeePaymentDate: o.InvoiceServiceFeePaymentDate,
+			BalanceOwing:          o.InvoiceBalanceOwingAmount,
+		})
+		s.TotalServiceFee += o.InvoiceServiceFeeAmount
+		s.TotalPaid += o.InvoiceActualServiceFeeAmountPaid
+		s.TotalOwing += o.InvoiceBalanceOwingAmount
+	}
+	s.TotalServiceFee = o_ds.RoundToCents(s.TotalServiceFee)
+	s.TotalPaid = o_ds.RoundToCents(s.TotalPaid)
+	s.TotalOwing = o_ds.RoundToCents(s.TotalOwing)
+	s.BalanceOwingAmount = o_ds.RoundToCents(balanceOwing)
+
+	if a.BalanceOwingAmount != s.BalanceOwingAmount {
+		impl.Logger.Info("associate balance owing changed",
+			slog.Any("associate_id", a.ID),
+			slog.Float64("from", a.BalanceOwingAmount),
+			slog.Float64("to", s.BalanceOwingAmount))
+		if !dryRun {
+			a.BalanceOwingAmount = s.BalanceOwingAmount
+			if err := impl.AssociateStorer.UpdateByID(ctx, a); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s, nil
+}
+
+// applyServiceFee sets the service fee of the order and returns true if any
+// of its service fee fields changed. Orders which were already invoiced a
+// service fee are left as they are; orders with an invoice service fee but no
+// amount yet keep the percentage they were given, otherwise the current
+// service fee of the associate is copied onto the order.
+func (impl *StatementControllerImpl) applyServiceFee(ctx context.Context, a *a_ds.Associate, o *o_ds.Order, fees map[primitive.ObjectID]*sf_ds.ServiceFee) (bool, error) {
+	if !o.InvoiceServiceFeeID.IsZero() && o.InvoiceServiceFeeAmount != 0 {
+		return false, nil
+	}
+	before := [...]float64{o.InvoiceServiceFeeAmount, o.InvoiceBalanceOwingAmount}
+	changed := false
+
+	if o.InvoiceServiceFeeID.IsZero() {
+		sfID := o.AssociateServiceFeeID
+		if sfID.IsZero() {
+			sfID = a.ServiceFeeID
+		}
+		if sfID.IsZero() {
+			impl.Logger.Warn("skipping order without service fee", slog.Uint64("wjid", o.WJID))
+			return false, nil
+		}
+		sf, ok := fees[sfID]
+		if !ok {
+			var err error
+			if sf, err = impl.ServiceFeeStore.GetByID(ctx, sfID); err != nil {
+				return false, err
+			}
+			fees[sfID] = sf
+		}
+		if sf == nil {
+			impl.Logger.Warn("skipping order with missing service fee",
+				slog.Uint64("wjid", o.WJID),
+				slog.Any("service_fee_id", sfID))
+			return false, nil
+		}
+		o.InvoiceServiceFeeID = sf.ID
+		o.InvoiceServiceFeeName = sf.Name
+		o.InvoiceServiceFeeDescription = sf.Description
+		o.InvoiceServiceFeePercentage = sf.Percentage
+		changed = true
+	}
+
+	ledger.ApplyServiceFee(o, o.InvoiceServiceFeePercentage)
+	after := [...]float64{o.InvoiceServiceFeeAmount, o.InvoiceBalanceOwingAmount}
+	return changed || before != after, nil
+}
+
+// isBillable returns true if the associate owes a service fee on the order.
+func isBillable(o *o_ds.Order) bool {
+	if lifecycle.IsCompleted(o.Status) {
+		return true
+	}
+	return o.Status == o_ds.OrderStatusArchived && !o.CompletionDate.IsZero()
+}
@@ -27,6 +27,9 @@ type TagPaginationListFilter struct {
 	TenantID   primitive.ObjectID
 	Status     int8
 	SearchText string
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 // TagPaginationListResult represents the paginated list results for
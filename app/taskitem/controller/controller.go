@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	"github.com/over55/workery-cli/app/taskitem/digest"
+	"github.com/over55/workery-cli/app/taskitem/workflow"
+	c "github.com/over55/workery-cli/config"
+)
+
+// SyncChange represents an order whose task items were (or, on a dry run,
+// would be) brought in line with its status.
+type SyncChange struct {
+	OrderID primitive.ObjectID `json:"order_id"`
+	WJID    uint64             `json:"wjid"`
+	Status  int8               `json:"status"`
+	Result  *workflow.Result   `json:"result"`
+}
+
+// TaskItemController Interface for the follow up task items of orders.
+type TaskItemController interface {
+	Sync(ctx context.Context, dryRun bool) ([]*SyncChange, error)
+	Digest(ctx context.Context, postponedThreshold int) (*digest.Digest, error)
+}
+
+type TaskItemControllerImpl struct {
+	Config         *c.Conf
+	Logger         *slog.Logger
+	OrderStorer    o_ds.OrderStorer
+	TaskItemStorer ti_ds.TaskItemStorer
+	AuditLogStorer auditlog_ds.AuditLogStorer
+	Workflow       *workflow.Engine
+}
+
+func NewController(
+	appCfg *c.Conf,
+	loggerp *slog.Logger,
+	oStorer o_ds.OrderStorer,
+	tiStorer ti_ds.TaskItemStorer,
+	alStorer auditlog_ds.AuditLogStorer,
+) TaskItemController {
+	s := &TaskItemControllerImpl{
+		Config:         appCfg,
+		Logger:         loggerp,
+		OrderStorer:    oStorer,
+		TaskItemStorer: tiStorer,
+		AuditLogStorer: alStorer,
+		Workflow:       workflow.NewEngine(appCfg, loggerp, tiStorer),
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	"github.com/over55/workery-cli/app/taskitem/digest"
+)
+
+// Digest returns the open task items which are overdue, due today or were
+// postponed more than `postponedThreshold` times, grouped by owner and type.
+func (impl *TaskItemControllerImpl) Digest(ctx context.Context, postponedThreshold int) (*digest.Digest, error) {
+	res, err := impl.TaskItemStorer.ListByFilter(ctx, &ti_ds.TaskItemPaginationListFilter{
+		PageSize:  1_000_000,
+		SortField: "due_date",
+		SortOrder: ti_ds.SortOrderAscending,
+		Status:    ti_ds.TaskItemStatusActive,
+		IsClosed:  2,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	postponed := map[primitive.ObjectID]int{}
+	for _, ti := range res.Results {
+		if !ti.WasPostponed {
+			continue
+		}
+		count, err := impl.countPostponed(ctx, ti)
+		if err != nil {
+			return nil, err
+		}
+		postponed[ti.ID] = count
+	}
+
+	return digest.Build(res.Results, postponed, postponedThreshold, time.Now()), nil
+}
+
+// countPostponed returns how many times the due date of the task item was
+// moved later according to the audit log. Task items imported from the legacy
+// system have no history so being postponed counts as once.
+func (impl *TaskItemControllerImpl) countPostponed(ctx context.Context, ti *ti_ds.TaskItem) (int, error) {
+	logs, err := impl.AuditLogStorer.ListByDocumentID(ctx, "task_items", ti.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, l := range logs.Results {
+		for _, c := range l.Changes {
+			if c.Field != "due_date" {
+				continue
+			}
+			from, ok1 := toTime(c.OldValue)
+			to, ok2 := toTime(c.NewValue)
+			if ok1 && ok2 && to.After(from) {
+				count++
+			}
+		}
+	}
+	if count == 0 && ti.WasPostponed {
+		count = 1
+	}
+	return count, nil
+}
+
+func toTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case primitive.DateTime:
+		return t.Time(), true
+	case time.Time:
+		return t, true
+	}
+	return time.Time{}, false
+}
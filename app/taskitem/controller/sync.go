@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+)
+
+// Sync applies the task item workflow to every order, ex: orders imported
+// from the legacy system or changed before the workflow existed. When
+// `dryRun` is true nothing is saved.
+func (impl *TaskItemControllerImpl) Sync(ctx context.Context, dryRun bool) ([]*SyncChange, error) {
+	oo, err := impl.OrderStorer.ListByFilter(ctx, &o_ds.OrderPaginationListFilter{
+		PageSize:  1_000_000,
+		SortField: "start_date",
+		SortOrder: o_ds.SortOrderAscending,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	changes := make([]*SyncChange, 0)
+	for _, o := range oo.Results {
+		res, err := impl.Workflow.Apply(ctx, o, now, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		if res.Created == nil && len(res.Closed) == 0 && !res.OrderChanged {
+			continue
+		}
+		if res.OrderChanged && !dryRun {
+			if err := impl.OrderStorer.UpdateByID(ctx, o); err != nil {
+				return nil, err
+			}
+		}
+		changes = append(changes, &SyncChange{
+			OrderID: o.ID,
+			WJID:    o.WJID,
+			Status:  o.Status,
+			Result:  res,
+		})
+	}
+	return changes, nil
+}
@@ -9,7 +9,7 @@ import (
 )
 
 func (impl TaskItemStorerImpl) CountByFilter(ctx context.Context, f *TaskItemListFilter) (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Create the filter based on the cursor
@@ -32,6 +32,11 @@ func (impl TaskItemStorerImpl) CountByFilter(ctx context.Context, f *TaskItemLis
 	impl.Logger.Debug("counting w/ filter:",
 		slog.Any("filter", filter))
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Use the CountDocuments method to count the matching documents.
 	count, err := impl.Collection.CountDocuments(ctx, filter)
 	if err != nil {
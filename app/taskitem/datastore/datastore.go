@@ -11,6 +11,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 	c "github.com/over55/workery-cli/config"
 )
 
@@ -105,6 +107,9 @@ type TaskItem struct {
 	AssociateServiceFeeID                 primitive.ObjectID              `bson:"associate_service_fee_id" json:"associate_service_fee_id"`
 	AssociateServiceFeeName               string                          `bson:"associate_service_fee_name" json:"associate_service_fee_name"`
 	AssociateServiceFeePercentage         float64                         `bson:"associate_service_fee_percentage" json:"associate_service_fee_percentage"`
+	DeletedAt                             time.Time                       `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedByUserID                       primitive.ObjectID              `bson:"deleted_by_user_id,omitempty" json:"deleted_by_user_id,omitempty"`
+	DeletedByUserName                     string                          `bson:"deleted_by_user_name,omitempty" json:"deleted_by_user_name,omitempty"`
 }
 
 type TaskItemTag struct {
@@ -164,6 +169,9 @@ type TaskItemListFilter struct {
 	ExcludeArchived bool
 	SearchText      string
 	IsClosed        int8
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 type TaskItemListResult struct {
@@ -190,19 +198,27 @@ type TaskItemStorer interface {
 	ListByFilter(ctx context.Context, f *TaskItemPaginationListFilter) (*TaskItemPaginationListResult, error)
 	ListAsSelectOptionByFilter(ctx context.Context, f *TaskItemListFilter) ([]*TaskItemAsSelectOption, error)
 	ListByCustomerID(ctx context.Context, customerID primitive.ObjectID) (*TaskItemPaginationListResult, error)
+	ListByCustomerIDWithDeleted(ctx context.Context, customerID primitive.ObjectID) (*TaskItemPaginationListResult, error)
 	ListByAssociateID(ctx context.Context, associateID primitive.ObjectID) (*TaskItemPaginationListResult, error)
+	ListByAssociateIDWithDeleted(ctx context.Context, associateID primitive.ObjectID) (*TaskItemPaginationListResult, error)
 	ListByOrderID(ctx context.Context, orderID primitive.ObjectID) (*TaskItemPaginationListResult, error)
 	ListByOrderWJID(ctx context.Context, orderWJID uint64) (*TaskItemPaginationListResult, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
-	PermanentlyDeleteAllByCustomerID(ctx context.Context, customerID primitive.ObjectID) error
-	PermanentlyDeleteAllByAssociateID(ctx context.Context, associateID primitive.ObjectID) error
+	PermanentlyDeleteByID(ctx context.Context, id primitive.ObjectID) error
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	ListDeletedBefore(ctx context.Context, deletedBefore time.Time) ([]*TaskItem, error)
+	DeleteAllByCustomerID(ctx context.Context, customerID primitive.ObjectID) error
+	DeleteAllByAssociateID(ctx context.Context, associateID primitive.ObjectID) error
 	CountByFilter(ctx context.Context, f *TaskItemListFilter) (int64, error)
+	ForTenant(tenantID primitive.ObjectID) TaskItemStorer
+	AllTenants() TaskItemStorer
 }
 
 type TaskItemStorerImpl struct {
-	Logger     *slog.Logger
-	DbClient   *mongo.Client
-	Collection *mongo.Collection
+	Logger         *slog.Logger
+	DbClient       *mongo.Client
+	Collection     *mongodb.TenantCollection
+	AuditLogStorer auditlog_ds.AuditLogStorer
 }
 
 func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) TaskItemStorer {
@@ -220,6 +236,7 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Ta
 	// }
 
 	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
 		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
 		{Keys: bson.D{{Key: "public_id", Value: -1}}},
 		{Keys: bson.D{{Key: "is_closed", Value: 1}}},
@@ -256,9 +273,10 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Ta
 	}
 
 	s := &TaskItemStorerImpl{
-		Logger:     loggerp,
-		DbClient:   client,
-		Collection: uc,
+		Logger:         loggerp,
+		DbClient:       client,
+		Collection:     mongodb.NewTenantCollection(uc),
+		AuditLogStorer: auditlog_ds.NewDatastore(appCfg, loggerp, client),
 	}
 	return s
 }
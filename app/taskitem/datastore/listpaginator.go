@@ -42,6 +42,9 @@ type TaskItemPaginationListFilter struct {
 	// AllSkillSetIDs filter is used if you want to find all skill set ids for
 	// the associate.
 	AllSkillSetIDs []primitive.ObjectID
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 // TaskItemPaginationListResult represents the paginated list results for
@@ -21,6 +21,24 @@ func (impl TaskItemStorerImpl) ListByCustomerID(ctx context.Context, customerID
 	return res, nil
 }
 
+// ListByCustomerIDWithDeleted does the same as `ListByCustomerID` along
+// with the task items in the trash.
+func (impl TaskItemStorerImpl) ListByCustomerIDWithDeleted(ctx context.Context, customerID primitive.ObjectID) (*TaskItemPaginationListResult, error) {
+	f := &TaskItemPaginationListFilter{
+		Cursor:         "",
+		PageSize:       1_000_00,
+		SortField:      "", // Setting this empty to ignore any sorting.
+		SortOrder:      SortOrderAscending,
+		CustomerID:     customerID,
+		IncludeDeleted: true,
+	}
+	res, err := impl.ListByFilter(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (impl TaskItemStorerImpl) ListByAssociateID(ctx context.Context, associateID primitive.ObjectID) (*TaskItemPaginationListResult, error) {
 	f := &TaskItemPaginationListFilter{
 		Cursor:      "",
@@ -36,6 +54,24 @@ func (impl TaskItemStorerImpl) ListByAssociateID(ctx context.Context, associateI
 	return res, nil
 }
 
+// ListByAssociateIDWithDeleted does the same as `ListByAssociateID` along
+// with the task items in the trash.
+func (impl TaskItemStorerImpl) ListByAssociateIDWithDeleted(ctx context.Context, associateID primitive.ObjectID) (*TaskItemPaginationListResult, error) {
+	f := &TaskItemPaginationListFilter{
+		Cursor:         "",
+		PageSize:       1_000_00,
+		SortField:      "", // Setting this empty to ignore any sorting.
+		SortOrder:      SortOrderAscending,
+		AssociateID:    associateID,
+		IncludeDeleted: true,
+	}
+	res, err := impl.ListByFilter(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (impl TaskItemStorerImpl) ListByOrderID(ctx context.Context, orderID primitive.ObjectID) (*TaskItemPaginationListResult, error) {
 	f := &TaskItemPaginationListFilter{
 		Cursor:    "",
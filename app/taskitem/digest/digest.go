@@ -0,0 +1,151 @@
+package digest
+
+import (
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+)
+
+// UnassignedOwnerName is the owner of task items which were not created by a
+// staff member.
+const UnassignedOwnerName = "Unassigned"
+
+var TaskItemTypeLabels = map[int8]string{
+	ti_ds.TaskItemTypeAssignedAssociate:                           "Assign associate",
+	ti_ds.TaskItemTypeFollowUpDidAssociateAndCustomerAgreedToMeet: "Did associate and customer agree to meet?",
+	ti_ds.TaskItemTypeFollowUpCustomerSurvey:                      "Customer survey",
+	ti_ds.TaskItemTypeFollowUpDidAssociateAcceptJob:               "Did associate accept job?",
+	ti_ds.TaskItemTypeUpdateOngoingJob:                            "Update ongoing job",
+	ti_ds.TaskItemTypeFollowUpDidAssociateCompleteJob:             "Did associate complete job?",
+	ti_ds.TaskItemTypeFollowUpDidCustomerReviewAssociateAfterJob:  "Did customer review associate?",
+}
+
+// Digest lists the open task items which need attention grouped by the staff
+// member who owns them.
+type Digest struct {
+	GeneratedAt        time.Time `json:"generated_at"`
+	PostponedThreshold int       `json:"postponed_threshold"`
+	Owners             []*Owner  `json:"owners"`
+}
+
+// Owner holds the sections of the digest of a single staff member. A task
+// item may be in more than one section, ex: overdue and postponed.
+type Owner struct {
+	OwnerID   primitive.ObjectID `json:"owner_id"`
+	OwnerName string             `json:"owner_name"`
+	Overdue   []*TypeGroup       `json:"overdue"`
+	DueToday  []*TypeGroup       `json:"due_today"`
+	Postponed []*TypeGroup       `json:"postponed"`
+}
+
+// TypeGroup holds the task items of a single type.
+type TypeGroup struct {
+	Type  int8    `json:"type"`
+	Label string  `json:"label"`
+	Items []*Item `json:"items"`
+}
+
+// Item is a task item in the digest.
+type Item struct {
+	TaskItemID     primitive.ObjectID `json:"task_item_id"`
+	OrderWJID      uint64             `json:"order_wjid"`
+	Title          string             `json:"title"`
+	CustomerName   string             `json:"customer_name"`
+	AssociateName  string             `json:"associate_name"`
+	DueDate        time.Time          `json:"due_date"`
+	DaysOverdue    int                `json:"days_overdue"`
+	PostponedCount int                `json:"postponed_count"`
+}
+
+// IsEmpty returns true if nothing needs attention.
+func (d *Digest) IsEmpty() bool {
+	return len(d.Owners) == 0
+}
+
+// Build returns the digest of the open task items. Task items are overdue if
+// they were due before today and postponed if `postponed` holds a count
+// greater than the threshold for them.
+func Build(tasks []*ti_ds.TaskItem, postponed map[primitive.ObjectID]int, threshold int, now time.Time) *Digest {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tomorrow := today.AddDate(0, 0, 1)
+
+	owners := map[primitive.ObjectID]*Owner{}
+	ownerOf := func(ti *ti_ds.TaskItem) *Owner {
+		o, ok := owners[ti.CreatedByUserID]
+		if !ok {
+			name := ti.CreatedByUserName
+			if ti.CreatedByUserID.IsZero() || name == "" {
+				name = UnassignedOwnerName
+			}
+			o = &Owner{OwnerID: ti.CreatedByUserID, OwnerName: name}
+			owners[ti.CreatedByUserID] = o
+		}
+		return o
+	}
+
+	for _, ti := range tasks {
+		if ti.IsClosed || ti.Status != ti_ds.TaskItemStatusActive {
+			continue
+		}
+		item := &Item{
+			TaskItemID:     ti.ID,
+			OrderWJID:      ti.OrderWJID,
+			Title:          ti.Title,
+			CustomerName:   ti.CustomerName,
+			AssociateName:  ti.AssociateName,
+			DueDate:        ti.DueDate,
+			PostponedCount: postponed[ti.ID],
+		}
+
+		due := ti.DueDate.In(now.Location())
+		switch {
+		case due.Before(today):
+			item.DaysOverdue = int(today.Sub(due).Hours()/24) + 1
+			o := ownerOf(ti)
+			o.Overdue = addToGroup(o.Overdue, ti.Type, item)
+		case due.Before(tomorrow):
+			o := ownerOf(ti)
+			o.DueToday = addToGroup(o.DueToday, ti.Type, item)
+		}
+		if item.PostponedCount > threshold {
+			o := ownerOf(ti)
+			o.Postponed = addToGroup(o.Postponed, ti.Type, item)
+		}
+	}
+
+	d := &Digest{GeneratedAt: now, PostponedThreshold: threshold, Owners: make([]*Owner, 0, len(owners))}
+	for _, o := range owners {
+		for _, groups := range [][]*TypeGroup{o.Overdue, o.DueToday, o.Postponed} {
+			sortGroups(groups)
+		}
+		d.Owners = append(d.Owners, o)
+	}
+	sort.Slice(d.Owners, func(i, j int) bool {
+		return d.Owners[i].OwnerName < d.Owners[j].OwnerName
+	})
+	return d
+}
+
+func addToGroup(groups []*TypeGroup, typeOf int8, item *Item) []*TypeGroup {
+	for _, g := range groups {
+		if g.Type == typeOf {
+			g.Items = append(g.Items, item)
+			return groups
+		}
+	}
+	label, ok := TaskItemTypeLabels[typeOf]
+	if !ok {
+		label = "Other"
+	}
+	return append(groups, &TypeGroup{Type: typeOf, Label: label, Items: []*Item{item}})
+}
+
+func sortGroups(groups []*TypeGroup) {
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Type < groups[j].Type })
+	for _, g := range groups {
+		sort.SliceStable(g.Items, func(i, j int) bool { return g.Items[i].DueDate.Before(g.Items[j].DueDate) })
+	}
+}
@@ -0,0 +1,130 @@
+package digest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatJSON     = "json"
+
+	dateLayout = "2006-01-02"
+)
+
+// Render returns the digest in the format, either `markdown`, `html` or
+// `json`.
+func Render(d *Digest, format string) ([]byte, error) {
+	switch format {
+	case FormatMarkdown:
+		return []byte(Markdown(d)), nil
+	case FormatHTML:
+		return HTML(d)
+	case FormatJSON:
+		return json.MarshalIndent(d, "", "  ")
+	}
+	return nil, fmt.Errorf("unsupported format: %v", format)
+}
+
+// ContentType returns the MIME type of the format.
+func ContentType(format string) string {
+	switch format {
+	case FormatHTML:
+		return "text/html"
+	case FormatJSON:
+		return "application/json"
+	}
+	return "text/markdown"
+}
+
+type section struct {
+	Title  string
+	Groups []*TypeGroup
+}
+
+func sections(d *Digest, o *Owner) []section {
+	return []section{
+		{"Overdue", o.Overdue},
+		{"Due today", o.DueToday},
+		{fmt.Sprintf("Postponed more than %v time(s)", d.PostponedThreshold), o.Postponed},
+	}
+}
+
+// Markdown returns the digest as a Markdown document.
+func Markdown(d *Digest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Task digest for %v\n\n", d.GeneratedAt.Format(dateLayout))
+	if d.IsEmpty() {
+		b.WriteString("Nothing is overdue, due today or repeatedly postponed.\n")
+		return b.String()
+	}
+	for _, o := range d.Owners {
+		fmt.Fprintf(&b, "## %v\n\n", o.OwnerName)
+		for _, s := range sections(d, o) {
+			if len(s.Groups) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "### %v\n\n", s.Title)
+			for _, g := range s.Groups {
+				fmt.Fprintf(&b, "#### %v\n\n", g.Label)
+				b.WriteString("| Job # | Task | Customer | Associate | Due | Days overdue | Postponed |\n")
+				b.WriteString("|---|---|---|---|---|---|---|\n")
+				for _, i := range g.Items {
+					fmt.Fprintf(&b, "| %v | %v | %v | %v | %v | %v | %v |\n",
+						i.OrderWJID, escapeMarkdown(i.Title), escapeMarkdown(i.CustomerName), escapeMarkdown(i.AssociateName),
+						i.DueDate.Format(dateLayout), i.DaysOverdue, i.PostponedCount)
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
+
+var htmlTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"date":     func(d interface{ Format(string) string }) string { return d.Format(dateLayout) },
+	"sections": sections,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Task digest for {{date .GeneratedAt}}</title></head>
+<body style="font-family: sans-serif;">
+<h1>Task digest for {{date .GeneratedAt}}</h1>
+{{- if .IsEmpty}}
+<p>Nothing is overdue, due today or repeatedly postponed.</p>
+{{- end}}
+{{- $d := .}}
+{{- range $o := .Owners}}
+<h2>{{$o.OwnerName}}</h2>
+{{- range sections $d $o}}{{if .Groups}}
+<h3>{{.Title}}</h3>
+{{- range .Groups}}
+<h4>{{.Label}}</h4>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Job #</th><th>Task</th><th>Customer</th><th>Associate</th><th>Due</th><th>Days overdue</th><th>Postponed</th></tr>
+{{- range .Items}}
+<tr><td>{{.OrderWJID}}</td><td>{{.Title}}</td><td>{{.CustomerName}}</td><td>{{.AssociateName}}</td><td>{{date .DueDate}}</td><td>{{.DaysOverdue}}</td><td>{{.PostponedCount}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}{{end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// HTML returns the digest as an HTML document.
+func HTML(d *Digest) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, d); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,281 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	"github.com/over55/workery-cli/app/convert"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	c "github.com/over55/workery-cli/config"
+)
+
+// SupersededReason is the closing reason of the task items which were closed
+// because their order moved on.
+const SupersededReason = "Superseded by a change of the order status"
+
+// Step is a task item the workflow creates.
+type Step struct {
+	Type        int8
+	Title       string
+	Description string
+}
+
+var Steps = map[int8]*Step{
+	ti_ds.TaskItemTypeAssignedAssociate: {
+		Type:        ti_ds.TaskItemTypeAssignedAssociate,
+		Title:       "Assign associate",
+		Description: "Please assign an associate to this job.",
+	},
+	ti_ds.TaskItemTypeFollowUpDidAssociateAcceptJob: {
+		Type:        ti_ds.TaskItemTypeFollowUpDidAssociateAcceptJob,
+		Title:       "Did associate accept job?",
+		Description: "Please follow up with the associate to find out if they accepted the job.",
+	},
+	ti_ds.TaskItemTypeUpdateOngoingJob: {
+		Type:        ti_ds.TaskItemTypeUpdateOngoingJob,
+		Title:       "Update ongoing job",
+		Description: "Please review the ongoing job with the associate and update it.",
+	},
+	ti_ds.TaskItemTypeFollowUpDidAssociateCompleteJob: {
+		Type:        ti_ds.TaskItemTypeFollowUpDidAssociateCompleteJob,
+		Title:       "Did associate complete job?",
+		Description: "Please follow up with the associate to find out if they completed the job.",
+	},
+	ti_ds.TaskItemTypeFollowUpDidCustomerReviewAssociateAfterJob: {
+		Type:        ti_ds.TaskItemTypeFollowUpDidCustomerReviewAssociateAfterJob,
+		Title:       "Did customer review associate?",
+		Description: "Please follow up with the customer to get their review of the associate.",
+	},
+}
+
+// Next returns the type of the task item the order is waiting on, or zero if
+// the order is closed and nothing is left to do.
+func Next(o *o_ds.Order) int8 {
+	switch o.Status {
+	case o_ds.OrderStatusNew:
+		if o.AssociateID.IsZero() {
+			return ti_ds.TaskItemTypeAssignedAssociate
+		}
+		return ti_ds.TaskItemTypeFollowUpDidAssociateAcceptJob
+	case o_ds.OrderStatusPending:
+		return ti_ds.TaskItemTypeFollowUpDidAssociateAcceptJob
+	case o_ds.OrderStatusInProgress:
+		return ti_ds.TaskItemTypeFollowUpDidAssociateCompleteJob
+	case o_ds.OrderStatusOngoing:
+		return ti_ds.TaskItemTypeUpdateOngoingJob
+	case o_ds.OrderStatusCompletedButUnpaid, o_ds.OrderStatusCompletedAndPaid:
+		return ti_ds.TaskItemTypeFollowUpDidCustomerReviewAssociateAfterJob
+	}
+	return 0
+}
+
+// Result lists what applying the workflow to an order changed.
+type Result struct {
+	Created *ti_ds.TaskItem   `json:"created,omitempty"`
+	Closed  []*ti_ds.TaskItem `json:"closed"`
+
+	// OrderChanged is true if the latest pending task fields of the order
+	// were updated, the order still needs to be saved.
+	OrderChanged bool `json:"order_changed"`
+}
+
+// Engine creates the task items of orders as they move through their
+// lifecycle.
+type Engine struct {
+	Logger         *slog.Logger
+	DueDays        map[int8]int
+	TaskItemStorer ti_ds.TaskItemStorer
+}
+
+func NewEngine(appCfg *c.Conf, loggerp *slog.Logger, tiStorer ti_ds.TaskItemStorer) *Engine {
+	return &Engine{
+		Logger: loggerp,
+		DueDays: map[int8]int{
+			ti_ds.TaskItemTypeAssignedAssociate:                          appCfg.TaskItem.AssignedAssociateDueDays,
+			ti_ds.TaskItemTypeFollowUpDidAssociateAcceptJob:              appCfg.TaskItem.AssociateAcceptJobDueDays,
+			ti_ds.TaskItemTypeUpdateOngoingJob:                           appCfg.TaskItem.UpdateOngoingJobDueDays,
+			ti_ds.TaskItemTypeFollowUpDidAssociateCompleteJob:            appCfg.TaskItem.AssociateCompleteJobDueDays,
+			ti_ds.TaskItemTypeFollowUpDidCustomerReviewAssociateAfterJob: appCfg.TaskItem.CustomerReviewAssociateDueDays,
+		},
+		TaskItemStorer: tiStorer,
+	}
+}
+
+// Apply closes the open task items of the order which the order has moved
+// past, creates the task item it is waiting on unless the order already had
+// one of that type and updates the latest pending task fields of the order.
+// The order itself is not saved. When `dryRun` is true nothing is saved.
+func (e *Engine) Apply(ctx context.Context, o *o_ds.Order, now time.Time, dryRun bool) (*Result, error) {
+	res, err := e.TaskItemStorer.ListByOrderID(ctx, o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	next := Next(o)
+	userID, userName, ipAddress := auditlog_ds.GetActor(ctx)
+	result := &Result{Closed: make([]*ti_ds.TaskItem, 0)}
+
+	var hasNext bool
+	var open []*ti_ds.TaskItem
+	for _, ti := range res.Results {
+		if ti.Type == next {
+			hasNext = true
+		}
+		if ti.IsClosed || ti.Status != ti_ds.TaskItemStatusActive {
+			continue
+		}
+		if ti.Type == next {
+			open = append(open, ti)
+			continue
+		}
+
+		ti.IsClosed = true
+		ti.ClosingReasonOther = SupersededReason
+		ti.ModifiedAt = now
+		ti.ModifiedByUserID = userID
+		ti.ModifiedByUserName = userName
+		ti.ModifiedFromIPAddress = ipAddress
+		if !dryRun {
+			if err := e.TaskItemStorer.UpdateByID(ctx, ti); err != nil {
+				return nil, err
+			}
+		}
+		result.Closed = append(result.Closed, ti)
+	}
+
+	if next != 0 && !hasNext {
+		ti := e.newTaskItem(o, Steps[next], now)
+		ti.CreatedByUserID = userID
+		ti.CreatedByUserName = userName
+		ti.CreatedFromIPAddress = ipAddress
+		ti.ModifiedByUserID = userID
+		ti.ModifiedByUserName = userName
+		ti.ModifiedFromIPAddress = ipAddress
+		if !dryRun {
+			if err := e.TaskItemStorer.Create(ctx, ti); err != nil {
+				return nil, err
+			}
+		}
+		result.Created = ti
+		open = append(open, ti)
+
+		e.Logger.Debug("task item created",
+			slog.Uint64("wjid", o.WJID),
+			slog.Int("type", int(ti.Type)))
+	}
+
+	result.OrderChanged = setLatestPendingTask(o, open)
+	return result, nil
+}
+
+// setLatestPendingTask points the order at the open task item which is due
+// first and returns true if it changed.
+func setLatestPendingTask(o *o_ds.Order, open []*ti_ds.TaskItem) bool {
+	var latest *ti_ds.TaskItem
+	for _, ti := range open {
+		if latest == nil || ti.DueDate.Before(latest.DueDate) {
+			latest = ti
+		}
+	}
+
+	var (
+		id          primitive.ObjectID
+		title       string
+		description string
+		dueDate     time.Time
+		typeOf      int8
+	)
+	if latest != nil {
+		id, title, description, dueDate, typeOf = latest.ID, latest.Title, latest.Description, latest.DueDate, latest.Type
+	}
+	if o.LatestPendingTaskID == id &&
+		o.LatestPendingTaskTitle == title &&
+		o.LatestPendingTaskDescription == description &&
+		o.LatestPendingTaskDueDate.Equal(dueDate) &&
+		o.LatestPendingTaskType == typeOf {
+		return false
+	}
+	o.LatestPendingTaskID = id
+	o.LatestPendingTaskTitle = title
+	o.LatestPendingTaskDescription = description
+	o.LatestPendingTaskDueDate = dueDate
+	o.LatestPendingTaskType = typeOf
+	return true
+}
+
+func (e *Engine) newTaskItem(o *o_ds.Order, step *Step, now time.Time) *ti_ds.TaskItem {
+	return &ti_ds.TaskItem{
+		ID:                                    primitive.NewObjectID(),
+		TenantID:                              o.TenantID,
+		Type:                                  step.Type,
+		Title:                                 step.Title,
+		Description:                           step.Description,
+		DueDate:                               now.AddDate(0, 0, e.DueDays[step.Type]),
+		OrderID:                               o.ID,
+		OrderType:                             o.Type,
+		OrderWJID:                             o.WJID,
+		OrderTenantIDWithWJID:                 fmt.Sprintf("%v_%v", o.TenantID.Hex(), o.WJID),
+		OrderStartDate:                        o.StartDate,
+		OrderDescription:                      o.Description,
+		OrderSkillSets:                        convert.ToTaskItemSkillSetsFromOrderSkillSets(o.SkillSets),
+		OrderTags:                             convert.ToTaskItemTagsFromOrderTags(o.Tags),
+		CreatedAt:                             now,
+		ModifiedAt:                            now,
+		Status:                                ti_ds.TaskItemStatusActive,
+		CustomerID:                            o.CustomerID,
+		CustomerOrganizationName:              o.CustomerOrganizationName,
+		CustomerOrganizationType:              o.CustomerOrganizationType,
+		CustomerPublicID:                      o.CustomerPublicID,
+		CustomerFirstName:                     o.CustomerFirstName,
+		CustomerLastName:                      o.CustomerLastName,
+		CustomerName:                          o.CustomerName,
+		CustomerLexicalName:                   o.CustomerLexicalName,
+		CustomerGender:                        o.CustomerGender,
+		CustomerGenderOther:                   o.CustomerGenderOther,
+		CustomerBirthdate:                     o.CustomerBirthdate,
+		CustomerEmail:                         o.CustomerEmail,
+		CustomerPhone:                         o.CustomerPhone,
+		CustomerPhoneType:                     o.CustomerPhoneType,
+		CustomerPhoneExtension:                o.CustomerPhoneExtension,
+		CustomerOtherPhone:                    o.CustomerOtherPhone,
+		CustomerOtherPhoneExtension:           o.CustomerOtherPhoneExtension,
+		CustomerOtherPhoneType:                o.CustomerOtherPhoneType,
+		CustomerFullAddressWithoutPostalCode:  o.CustomerFullAddressWithoutPostalCode,
+		CustomerFullAddressURL:                o.CustomerFullAddressURL,
+		CustomerTags:                          convert.ToTaskItemTagsFromOrderTags(o.CustomerTags),
+		AssociateID:                           o.AssociateID,
+		AssociateOrganizationName:             o.AssociateOrganizationName,
+		AssociateOrganizationType:             o.AssociateOrganizationType,
+		AssociatePublicID:                     o.AssociatePublicID,
+		AssociateFirstName:                    o.AssociateFirstName,
+		AssociateLastName:                     o.AssociateLastName,
+		AssociateName:                         o.AssociateName,
+		AssociateLexicalName:                  o.AssociateLexicalName,
+		AssociateGender:                       o.AssociateGender,
+		AssociateGenderOther:                  o.AssociateGenderOther,
+		AssociateBirthdate:                    o.AssociateBirthdate,
+		AssociateEmail:                        o.AssociateEmail,
+		AssociatePhone:                        o.AssociatePhone,
+		AssociatePhoneType:                    o.AssociatePhoneType,
+		AssociatePhoneExtension:               o.AssociatePhoneExtension,
+		AssociateOtherPhone:                   o.AssociateOtherPhone,
+		AssociateOtherPhoneExtension:          o.AssociateOtherPhoneExtension,
+		AssociateOtherPhoneType:               o.AssociateOtherPhoneType,
+		AssociateFullAddressWithoutPostalCode: o.AssociateFullAddressWithoutPostalCode,
+		AssociateFullAddressURL:               o.AssociateFullAddressURL,
+		AssociateTags:                         convert.ToTaskItemTagsFromOrderTags(o.AssociateTags),
+		AssociateSkillSets:                    convert.ToTaskItemSkillSetsFromOrderSkillSets(o.AssociateSkillSets),
+		AssociateInsuranceRequirements:        convert.ToTaskItemInsuranceRequirementsFromOrderInsuranceRequirements(o.AssociateInsuranceRequirements),
+		AssociateVehicleTypes:                 convert.ToTaskItemVehicleTypesFromOrderVehicleTypes(o.AssociateVehicleTypes),
+		AssociateTaxID:                        o.AssociateTaxID,
+		AssociateServiceFeeID:                 o.AssociateServiceFeeID,
+		AssociateServiceFeeName:               o.AssociateServiceFeeName,
+		AssociateServiceFeePercentage:         o.AssociateServiceFeePercentage,
+	}
+}
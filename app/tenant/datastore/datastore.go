@@ -11,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 	c "github.com/over55/workery-cli/config"
 )
 
@@ -64,6 +65,9 @@ type Tenant struct {
 	OtherTelephoneType      int8               `bson:"other_telephone_type" json:"other_telephone_type"`
 	PublicID                uint64             `bson:"public_id" json:"public_id"`
 	Comments                []*TenantComment   `bson:"comments" json:"comments"`
+	DeletedAt               time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedByUserID         primitive.ObjectID `bson:"deleted_by_user_id,omitempty" json:"deleted_by_user_id,omitempty"`
+	DeletedByUserName       string             `bson:"deleted_by_user_name,omitempty" json:"deleted_by_user_name,omitempty"`
 }
 
 type TenantComment struct {
@@ -94,6 +98,9 @@ type TenantListFilter struct {
 	ExcludeArchived bool
 	SearchText      string
 	CreatedAtGTE    time.Time
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 type TenantListResult struct {
@@ -118,12 +125,16 @@ type TenantStorer interface {
 	ListByFilter(ctx context.Context, m *TenantListFilter) (*TenantListResult, error)
 	ListAsSelectOptionByFilter(ctx context.Context, f *TenantListFilter) ([]*TenantAsSelectOption, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	PermanentlyDeleteByID(ctx context.Context, id primitive.ObjectID) error
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	ListDeletedBefore(ctx context.Context, deletedBefore time.Time) ([]*Tenant, error)
 }
 
 type TenantStorerImpl struct {
-	Logger     *slog.Logger
-	DbClient   *mongo.Client
-	Collection *mongo.Collection
+	Logger         *slog.Logger
+	DbClient       *mongo.Client
+	Collection     *mongo.Collection
+	AuditLogStorer auditlog_ds.AuditLogStorer
 }
 
 func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) TenantStorer {
@@ -131,6 +142,7 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Te
 	uc := client.Database(appCfg.DB.Name).Collection("tenants")
 
 	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
 		{Keys: bson.D{{Key: "public_id", Value: -1}}},
 		{Keys: bson.D{{Key: "schema_name", Value: 1}}},
 		{Keys: bson.D{{Key: "status", Value: 1}}},
@@ -146,9 +158,10 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Te
 	}
 
 	s := &TenantStorerImpl{
-		Logger:     loggerp,
-		DbClient:   client,
-		Collection: uc,
+		Logger:         loggerp,
+		DbClient:       client,
+		Collection:     uc,
+		AuditLogStorer: auditlog_ds.NewDatastore(appCfg, loggerp, client),
 	}
 	return s
 }
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+)
+
+// ParseRole returns the role with the label, ignoring case.
+func ParseRole(label string) (int8, error) {
+	for role, l := range user_ds.UserRoleLabels {
+		if strings.EqualFold(l, strings.TrimSpace(label)) {
+			return role, nil
+		}
+	}
+	return 0, ErrInvalidRole
+}
+
+func (impl *UserControllerImpl) Create(ctx context.Context, req *CreateRequest) (*user_ds.User, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email == "" {
+		return nil, ErrInvalidEmail
+	}
+	if _, ok := user_ds.UserRoleLabels[req.Role]; !ok {
+		return nil, ErrInvalidRole
+	}
+	if req.Password == "" {
+		return nil, ErrInvalidPassword
+	}
+	if err := impl.Password.ValidatePassword(req.Password, email, req.FirstName, req.LastName); err != nil {
+		return nil, err
+	}
+	exists, err := impl.UserStorer.CheckIfExistsByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrEmailTaken
+	}
+
+	passwordHash, err := impl.Password.GenerateHashFromPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, userName, ipAddress := auditlog_ds.GetActor(ctx)
+	now := time.Now()
+	u := &user_ds.User{
+		ID:                    primitive.NewObjectID(),
+		TenantID:              req.TenantID,
+		Email:                 email,
+		FirstName:             req.FirstName,
+		LastName:              req.LastName,
+		Name:                  strings.TrimSpace(req.FirstName + " " + req.LastName),
+		LexicalName:           strings.Trim(req.LastName+", "+req.FirstName, ", "),
+		Role:                  req.Role,
+		HasStaffRole:          req.Role <= user_ds.UserRoleStaff,
+		PasswordHashAlgorithm: impl.Password.AlgorithmName(),
+		PasswordHash:          passwordHash,
+		Status:                user_ds.UserStatusActive,
+		Comments:              make([]*user_ds.UserComment, 0),
+		JoinedTime:            now,
+		CreatedAt:             now,
+		CreatedByUserID:       userID,
+		CreatedByUserName:     userName,
+		CreatedFromIPAddress:  ipAddress,
+		ModifiedAt:            now,
+		ModifiedByUserID:      userID,
+		ModifiedByUserName:    userName,
+		ModifiedFromIPAddress: ipAddress,
+	}
+	if err := impl.UserStorer.Create(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (impl *UserControllerImpl) List(ctx context.Context, role int8, status int8) ([]*user_ds.User, error) {
+	res, err := impl.UserStorer.ListByFilter(ctx, &user_ds.UserListFilter{
+		PageSize:  1_000_000,
+		SortField: "lexical_name",
+		SortOrder: 1,
+		Role:      role,
+		Status:    status,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Results, nil
+}
+
+// Get returns the user by either its ID, public ID or email.
+func (impl *UserControllerImpl) Get(ctx context.Context, ref string) (*user_ds.User, error) {
+	ref = strings.TrimSpace(ref)
+
+	var u *user_ds.User
+	var err error
+	if id, perr := primitive.ObjectIDFromHex(ref); perr == nil {
+		u, err = impl.UserStorer.GetByID(ctx, id)
+	} else if publicID, perr := strconv.ParseUint(ref, 10, 64); perr == nil {
+		u, err = impl.UserStorer.GetByPublicID(ctx, publicID)
+	} else {
+		u, err = impl.UserStorer.GetByEmail(ctx, ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (impl *UserControllerImpl) SetRole(ctx context.Context, ref string, role int8) (*user_ds.User, error) {
+	if _, ok := user_ds.UserRoleLabels[role]; !ok {
+		return nil, ErrInvalidRole
+	}
+	return impl.update(ctx, ref, func(u *user_ds.User) {
+		u.Role = role
+		u.HasStaffRole = role <= user_ds.UserRoleStaff
+	})
+}
+
+func (impl *UserControllerImpl) SetStatus(ctx context.Context, ref string, status int8) (*user_ds.User, error) {
+	return impl.update(ctx, ref, func(u *user_ds.User) {
+		u.Status = status
+	})
+}
+
+func (impl *UserControllerImpl) ResetPassword(ctx context.Context, ref string, plainPassword string) (*user_ds.User, error) {
+	if plainPassword == "" {
+		return nil, ErrInvalidPassword
+	}
+	u, err := impl.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := impl.Password.ValidatePassword(plainPassword, u.Email, u.FirstName, u.LastName); err != nil {
+		return nil, err
+	}
+	passwordHash, err := impl.Password.GenerateHashFromPassword(plainPassword)
+	if err != nil {
+		return nil, err
+	}
+	return impl.update(ctx, u.ID.Hex(), func(u *user_ds.User) {
+		u.PasswordHashAlgorithm = impl.Password.AlgorithmName()
+		u.PasswordHash = passwordHash
+		u.PrAccessCode = ""
+		u.PrExpiryTime = time.Time{}
+	})
+}
+
+func (impl *UserControllerImpl) VerifyEmail(ctx context.Context, ref string) (*user_ds.User, error) {
+	return impl.update(ctx, ref, func(u *user_ds.User) {
+		u.WasEmailVerified = true
+		u.EmailVerificationCode = ""
+		u.EmailVerificationExpiry = time.Time{}
+	})
+}
+
+func (impl *UserControllerImpl) Unlock(ctx context.Context, ref string) (*user_ds.User, error) {
+	return impl.update(ctx, ref, func(u *user_ds.User) {
+		u.FailedLoginAttempts = 0
+		u.LockedUntil = time.Time{}
+	})
+}
+
+// update looks up the user, applies the change and saves it with the actor
+// of the context as the modifier.
+func (impl *UserControllerImpl) update(ctx context.Context, ref string, change func(u *user_ds.User)) (*user_ds.User, error) {
+	u, err := impl.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	change(u)
+
+	userID, userName, ipAddress := auditlog_ds.GetActor(ctx)
+	u.ModifiedAt = time.Now()
+	u.ModifiedByUserID = userID
+	u.ModifiedByUserName = userName
+	u.ModifiedFromIPAddress = ipAddress
+	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/over55/workery-cli/app/user/credentials"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	"github.com/over55/workery-cli/provider/password"
+)
+
+const (
+	PasswordAuditOK          = "ok"
+	PasswordAuditRehash      = "rehash"
+	PasswordAuditPlaceholder = "placeholder"
+	PasswordAuditUnknown     = "unknown"
+)
+
+// PasswordAudit is the state of the password hash of a user. Hashes which
+// need rehashing are replaced the next time the user logs in.
+type PasswordAudit struct {
+	UserID    primitive.ObjectID `json:"user_id"`
+	PublicID  uint64             `json:"public_id"`
+	Email     string             `json:"email"`
+	Name      string             `json:"name"`
+	Role      string             `json:"role"`
+	Status    string             `json:"status"`
+	Algorithm string             `json:"algorithm"`
+}
+
+// AuditPasswords inspects the password hash of every user without needing
+// their password.
+func (impl *UserControllerImpl) AuditPasswords(ctx context.Context) ([]*PasswordAudit, error) {
+	users, err := impl.List(ctx, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	audits := make([]*PasswordAudit, 0, len(users))
+	for _, u := range users {
+		a := &PasswordAudit{
+			UserID:    u.ID,
+			PublicID:  u.PublicID,
+			Email:     u.Email,
+			Name:      u.Name,
+			Role:      user_ds.UserRoleLabels[u.Role],
+			Algorithm: password.Algorithm(u.PasswordHash),
+		}
+		switch {
+		case u.PasswordHash == "" || u.PasswordHash == credentials.PlaceholderPasswordHash:
+			a.Status = PasswordAuditPlaceholder
+		case a.Algorithm == password.AlgorithmUnknown:
+			a.Status = PasswordAuditUnknown
+		case impl.Password.NeedsRehash(u.PasswordHash):
+			a.Status = PasswordAuditRehash
+		default:
+			a.Status = PasswordAuditOK
+		}
+		audits = append(audits, a)
+	}
+	return audits, nil
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	c "github.com/over55/workery-cli/config"
+	"github.com/over55/workery-cli/provider/password"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user does not exist")
+	ErrEmailTaken      = errors.New("email is already used by another user")
+	ErrInvalidRole     = errors.New("role must be either executive, management, staff, associate or customer")
+	ErrInvalidEmail    = errors.New("email is required")
+	ErrInvalidPassword = errors.New("password is required")
+)
+
+// CreateRequest is the account to create.
+type CreateRequest struct {
+	TenantID  primitive.ObjectID
+	Email     string
+	FirstName string
+	LastName  string
+	Role      int8
+	Password  string
+}
+
+// UserController Interface for managing the accounts users log in with.
+type UserController interface {
+	Create(ctx context.Context, req *CreateRequest) (*user_ds.User, error)
+	List(ctx context.Context, role int8, status int8) ([]*user_ds.User, error)
+	Get(ctx context.Context, ref string) (*user_ds.User, error)
+	SetRole(ctx context.Context, ref string, role int8) (*user_ds.User, error)
+	SetStatus(ctx context.Context, ref string, status int8) (*user_ds.User, error)
+	ResetPassword(ctx context.Context, ref string, plainPassword string) (*user_ds.User, error)
+	VerifyEmail(ctx context.Context, ref string) (*user_ds.User, error)
+	Unlock(ctx context.Context, ref string) (*user_ds.User, error)
+	ListPendingCredentials(ctx context.Context) ([]*Invitation, error)
+	IssueAccessCodes(ctx context.Context, validFor time.Duration, reissue bool, dryRun bool) ([]*Invitation, error)
+	AuditPasswords(ctx context.Context) ([]*PasswordAudit, error)
+	EnrollOTP(ctx context.Context, ref string, issuer string, force bool) (*OTPEnrolment, error)
+	VerifyOTP(ctx context.Context, ref string, code string) (*user_ds.User, error)
+	DisableOTP(ctx context.Context, ref string) (*user_ds.User, error)
+}
+
+type UserControllerImpl struct {
+	Config     *c.Conf
+	Logger     *slog.Logger
+	Password   password.Provider
+	UserStorer user_ds.UserStorer
+}
+
+func NewController(
+	appCfg *c.Conf,
+	loggerp *slog.Logger,
+	passp password.Provider,
+	uStorer user_ds.UserStorer,
+) UserController {
+	s := &UserControllerImpl{
+		Config:     appCfg,
+		Logger:     loggerp,
+		Password:   passp,
+		UserStorer: uStorer,
+	}
+	return s
+}
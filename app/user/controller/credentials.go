@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/over55/workery-cli/app/user/credentials"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+)
+
+// Invitation is an active user who cannot log in and the reset token they
+// can set their password with. `AccessCode` is only set on the invitations
+// which were just issued.
+type Invitation struct {
+	UserID     primitive.ObjectID `json:"user_id"`
+	PublicID   uint64             `json:"public_id"`
+	Email      string             `json:"email"`
+	Name       string             `json:"name"`
+	Phone      string             `json:"phone"`
+	Role       string             `json:"role"`
+	Reasons    []string           `json:"reasons"`
+	AccessCode string             `json:"access_code,omitempty"`
+	ExpiresAt  time.Time          `json:"expires_at,omitempty"`
+	Issued     bool               `json:"issued"`
+}
+
+func (impl *UserControllerImpl) ListPendingCredentials(ctx context.Context) ([]*Invitation, error) {
+	users, err := impl.List(ctx, 0, user_ds.UserStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]*Invitation, 0)
+	for _, u := range users {
+		reasons := credentials.Reasons(u)
+		if len(reasons) == 0 {
+			continue
+		}
+		inv := newInvitation(u, reasons)
+		if credentials.HasValidAccessCode(u, time.Now()) {
+			inv.ExpiresAt = u.PrExpiryTime
+		}
+		invitations = append(invitations, inv)
+	}
+	return invitations, nil
+}
+
+// IssueAccessCodes gives every active user who cannot log in a one-time reset
+// token valid for the duration. Users who already have a token which has not
+// expired are skipped unless `reissue` is set.
+func (impl *UserControllerImpl) IssueAccessCodes(ctx context.Context, validFor time.Duration, reissue bool, dryRun bool) ([]*Invitation, error) {
+	users, err := impl.List(ctx, 0, user_ds.UserStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitations := make([]*Invitation, 0)
+	for _, u := range users {
+		reasons := credentials.Reasons(u)
+		if len(reasons) == 0 {
+			continue
+		}
+		if !reissue && credentials.HasValidAccessCode(u, now) {
+			continue
+		}
+
+		inv := newInvitation(u, reasons)
+		inv.ExpiresAt = now.Add(validFor)
+		if !dryRun {
+			code, err := credentials.NewAccessCode()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := impl.update(ctx, u.ID.Hex(), func(u *user_ds.User) {
+				u.PrAccessCode = code
+				u.PrExpiryTime = inv.ExpiresAt
+			}); err != nil {
+				return nil, err
+			}
+			inv.AccessCode = code
+			inv.Issued = true
+		}
+		invitations = append(invitations, inv)
+	}
+	return invitations, nil
+}
+
+func newInvitation(u *user_ds.User, reasons []string) *Invitation {
+	return &Invitation{
+		UserID:   u.ID,
+		PublicID: u.PublicID,
+		Email:    u.Email,
+		Name:     u.Name,
+		Phone:    u.Phone,
+		Role:     user_ds.UserRoleLabels[u.Role],
+		Reasons:  reasons,
+	}
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	"github.com/over55/workery-cli/provider/otp"
+)
+
+const otpRecoveryCodeCount = 10
+
+var (
+	ErrOTPNotEnrolled  = errors.New("user is not enrolled in two-factor authentication")
+	ErrInvalidOTPCode  = errors.New("code does not match the authenticator or a recovery code")
+	ErrOTPAlreadyInUse = errors.New("user already verified two-factor authentication, disable it first")
+)
+
+// OTPEnrolment is what the user needs to set up their authenticator. The
+// recovery codes are only stored hashed so they cannot be shown again.
+type OTPEnrolment struct {
+	User          *user_ds.User `json:"user"`
+	Secret        string        `json:"secret"`
+	AuthURL       string        `json:"auth_url"`
+	RecoveryCodes []string      `json:"recovery_codes"`
+}
+
+// EnrollOTP generates a new secret and recovery codes for the user. Two-factor
+// authentication is enforced once the user verifies a code of the secret.
+func (impl *UserControllerImpl) EnrollOTP(ctx context.Context, ref string, issuer string, force bool) (*OTPEnrolment, error) {
+	u, err := impl.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if u.OTPEnabled && u.OTPVerified && !force {
+		return nil, ErrOTPAlreadyInUse
+	}
+
+	secret, err := otp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	codes, hashes, err := otp.GenerateRecoveryCodes(otpRecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	authURL := otp.AuthURL(issuer, u.Email, secret)
+
+	u, err = impl.update(ctx, u.ID.Hex(), func(u *user_ds.User) {
+		u.OTPEnabled = true
+		u.OTPVerified = false
+		u.OTPValidated = false
+		u.OTPSecret = secret
+		u.OTPAuthURL = authURL
+		u.OTPRecoveryCodeHashes = hashes
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &OTPEnrolment{
+		User:          u,
+		Secret:        secret,
+		AuthURL:       authURL,
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// VerifyOTP checks the code against the secret of the user, turning on
+// two-factor authentication the first time. A recovery code is accepted in
+// place of the code and is used up.
+func (impl *UserControllerImpl) VerifyOTP(ctx context.Context, ref string, code string) (*user_ds.User, error) {
+	u, err := impl.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if !u.OTPEnabled || u.OTPSecret == "" {
+		return nil, ErrOTPNotEnrolled
+	}
+
+	if otp.Validate(u.OTPSecret, code, time.Now()) {
+		return impl.update(ctx, u.ID.Hex(), func(u *user_ds.User) {
+			u.OTPVerified = true
+		})
+	}
+	if remaining, ok := otp.ConsumeRecoveryCode(u.OTPRecoveryCodeHashes, code); ok {
+		return impl.update(ctx, u.ID.Hex(), func(u *user_ds.User) {
+			u.OTPRecoveryCodeHashes = remaining
+		})
+	}
+	return nil, ErrInvalidOTPCode
+}
+
+// DisableOTP turns off two-factor authentication and forgets the secret and
+// recovery codes, so a user who lost their authenticator can log in again.
+func (impl *UserControllerImpl) DisableOTP(ctx context.Context, ref string) (*user_ds.User, error) {
+	return impl.update(ctx, ref, func(u *user_ds.User) {
+		u.OTPEnabled = false
+		u.OTPVerified = false
+		u.OTPValidated = false
+		u.OTPSecret = ""
+		u.OTPAuthURL = ""
+		u.OTPRecoveryCodeHashes = nil
+	})
+}
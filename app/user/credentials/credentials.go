@@ -0,0 +1,55 @@
+package credentials
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"time"
+
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+)
+
+// PlaceholderPasswordHash is the password hash the import commands give the
+// users they create, no password matches it so these users cannot log in.
+const PlaceholderPasswordHash = "MongoDB Primitive"
+
+const (
+	ReasonPlaceholderPassword = "placeholder_password"
+	ReasonSyntheticEmail      = "synthetic_email"
+)
+
+// syntheticEmail matches the emails the import commands make up for the
+// customers, associates and staff who did not have one.
+var syntheticEmail = regexp.MustCompile(`^(customer|associate|staff)_[0-9A-Za-z]+@workery\.ca$`)
+
+// IsSyntheticEmail returns true if the email was made up during the import.
+func IsSyntheticEmail(email string) bool {
+	return syntheticEmail.MatchString(email)
+}
+
+// Reasons returns why the user cannot log in, or nothing if they can.
+func Reasons(u *user_ds.User) []string {
+	reasons := make([]string, 0)
+	if u.PasswordHash == "" || u.PasswordHash == PlaceholderPasswordHash {
+		reasons = append(reasons, ReasonPlaceholderPassword)
+	}
+	if IsSyntheticEmail(u.Email) {
+		reasons = append(reasons, ReasonSyntheticEmail)
+	}
+	return reasons
+}
+
+// HasValidAccessCode returns true if the user was issued a reset token which
+// has not expired yet.
+func HasValidAccessCode(u *user_ds.User, now time.Time) bool {
+	return u.PrAccessCode != "" && u.PrExpiryTime.After(now)
+}
+
+// NewAccessCode returns a random one-time reset token.
+func NewAccessCode() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
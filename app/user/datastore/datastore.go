@@ -10,6 +10,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 	c "github.com/over55/workery-cli/config"
 )
 
@@ -24,6 +26,19 @@ const (
 	UserRoleCustomer       = 5
 )
 
+var UserStatusLabels = map[int8]string{
+	UserStatusActive:   "Active",
+	UserStatusArchived: "Archived",
+}
+
+var UserRoleLabels = map[int8]string{
+	UserRoleExecutive:  "Executive",
+	UserRoleManagement: "Management",
+	UserRoleStaff:      "Staff",
+	UserRoleAssociate:  "Associate",
+	UserRoleCustomer:   "Customer",
+}
+
 type User struct {
 	ID                      primitive.ObjectID `bson:"_id" json:"id"`
 	Email                   string             `bson:"email" json:"email"`
@@ -82,6 +97,19 @@ type User struct {
 
 	// OTPAuthURL is the URL used to share.
 	OTPAuthURL string `bson:"otp_auth_url" json:"-"`
+
+	// OTPRecoveryCodeHashes are the hashes of the one-time codes the user can
+	// log in with when they lose their authenticator, each is removed once used.
+	OTPRecoveryCodeHashes []string `bson:"otp_recovery_code_hashes" json:"-"`
+
+	// FailedLoginAttempts counts the consecutive failed logins, the account is
+	// locked until `LockedUntil` once too many have been made.
+	FailedLoginAttempts int       `bson:"failed_login_attempts" json:"failed_login_attempts"`
+	LockedUntil         time.Time `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+
+	DeletedAt         time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedByUserID   primitive.ObjectID `bson:"deleted_by_user_id,omitempty" json:"deleted_by_user_id,omitempty"`
+	DeletedByUserName string             `bson:"deleted_by_user_name,omitempty" json:"deleted_by_user_name,omitempty"`
 }
 
 type UserComment struct {
@@ -114,6 +142,9 @@ type UserListFilter struct {
 	Email           string
 	Phone           string
 	CreatedAtGTE    time.Time
+
+	// Include documents which have been moved to the trash.
+	IncludeDeleted bool
 }
 
 type UserListResult struct {
@@ -145,12 +176,18 @@ type UserStorer interface {
 	ListAllStaffForTenantID(ctx context.Context, tenantID primitive.ObjectID) (*UserListResult, error)
 	CountByFilter(ctx context.Context, f *UserListFilter) (int64, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	PermanentlyDeleteByID(ctx context.Context, id primitive.ObjectID) error
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	ListDeletedBefore(ctx context.Context, deletedBefore time.Time) ([]*User, error)
+	ForTenant(tenantID primitive.ObjectID) UserStorer
+	AllTenants() UserStorer
 }
 
 type UserStorerImpl struct {
-	Logger     *slog.Logger
-	DbClient   *mongo.Client
-	Collection *mongo.Collection
+	Logger         *slog.Logger
+	DbClient       *mongo.Client
+	Collection     *mongodb.TenantCollection
+	AuditLogStorer auditlog_ds.AuditLogStorer
 }
 
 func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) UserStorer {
@@ -158,6 +195,7 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Us
 	uc := client.Database(appCfg.DB.Name).Collection("users")
 
 	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
 		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
 		{Keys: bson.D{{Key: "email", Value: 1}}},
 		{Keys: bson.D{{Key: "last_name", Value: 1}}},
@@ -188,9 +226,10 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Us
 	}
 
 	s := &UserStorerImpl{
-		Logger:     loggerp,
-		DbClient:   client,
-		Collection: uc,
+		Logger:         loggerp,
+		DbClient:       client,
+		Collection:     mongodb.NewTenantCollection(uc),
+		AuditLogStorer: auditlog_ds.NewDatastore(appCfg, loggerp, client),
 	}
 	return s
 }
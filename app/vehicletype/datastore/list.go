@@ -10,7 +10,7 @@ import (
 )
 
 func (impl VehicleTypeStorerImpl) ListByFilter(ctx context.Context, f *VehicleTypePaginationListFilter) (*VehicleTypePaginationListResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
 	defer cancel()
 
 	// Create the paginated filter based on the cursor
@@ -44,6 +44,11 @@ func (impl VehicleTypeStorerImpl) ListByFilter(ctx context.Context, f *VehicleTy
 		options.SetSort(bson.D{{"score", bson.M{"$meta": "textScore"}}})
 	}
 
+	// Exclude documents in the trash unless requested otherwise.
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	// Execute the query
 	cursor, err := impl.Collection.Find(ctx, filter, options)
 	if err != nil {
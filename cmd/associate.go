@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	as_ds "github.com/over55/workery-cli/app/activitysheet/datastore"
+	a_c "github.com/over55/workery-cli/app/associate/controller"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+	att_ds "github.com/over55/workery-cli/app/attachment/datastore"
+	com_ds "github.com/over55/workery-cli/app/comment/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go associate dedupe --output=review.csv
+// $ go run main.go associate merge 512 768 --dry-run
+// $ go run main.go associate merge --review=review.csv
+
+var (
+	associateDedupeThreshold float64
+	associateDedupeFormat    string
+	associateDedupeOutput    string
+	associateMergeReview     string
+	associateMergeDryRun     bool
+)
+
+func init() {
+	associateDedupeCmd.Flags().Float64VarP(&associateDedupeThreshold, "threshold", "t", 0.5, "Minimum score, from 0 to 1, for a pair to be listed")
+	associateDedupeCmd.Flags().StringVarP(&associateDedupeFormat, "format", "m", "csv", "Output format, either table, csv or json")
+	associateDedupeCmd.Flags().StringVarP(&associateDedupeOutput, "output", "o", "", "File to write the review list to, defaults to stdout")
+	associateDedupeCmd.Annotations = requirePermission("associate:read")
+	associateCmd.AddCommand(associateDedupeCmd)
+
+	associateMergeCmd.Flags().StringVarP(&associateMergeReview, "review", "r", "", "Review file whose rows with a merge decision are merged")
+	associateMergeCmd.Flags().BoolVarP(&associateMergeDryRun, "dry-run", "d", false, "Report what would be moved without saving the changes")
+	associateMergeCmd.Annotations = requirePermission("associate:update")
+	associateCmd.AddCommand(associateMergeCmd)
+
+	associateCmd.Annotations = requirePermission("associate:read")
+	rootCmd.AddCommand(associateCmd)
+}
+
+var associateCmd = &cobra.Command{
+	Use:   "associate",
+	Short: "Find and merge duplicate associates",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var associateDedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "List the pairs of active associates which are likely the same person",
+	Long: `Scores the pairs of active associates on their emails, phones, name and
+address and writes the ones scoring at least --threshold to a review file,
+best first. The older associate of each pair is suggested to be kept. Write
+"merge" in the decision column of the pairs to merge and pass the file to
+"associate merge --review".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		pairs, err := newAssociateController(cfg, mc, getOrderTenant(cfg, mc)).FindDuplicates(context.Background(), associateDedupeThreshold)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := writeDuplicates(pairs, associateDedupeFormat, associateDedupeOutput); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "%v possible duplicate(s) found\n", len(pairs))
+	},
+}
+
+var associateMergeCmd = &cobra.Command{
+	Use:   "merge [keep] [drop]",
+	Short: "Merge a duplicate associate into the associate to keep",
+	Long: `Reassigns the orders, task items, activity sheets, away logs, comments and
+attachments of the dropped associate to the kept associate, including the ones
+in the trash, unions their skill sets, insurance requirements, vehicle types
+and tags, keeps the latest compliance dates, relinks the dropped associate's
+login and archives the dropped associate. The associate details copied into
+the reassigned orders and task items are then refreshed, all in one
+transaction. The kept associate must not be archived. Associates may be given
+by ID or public ID.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if associateMergeReview != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		ctrl := newAssociateController(cfg, mc, getOrderTenant(cfg, mc))
+
+		merges := [][2]string{}
+		if associateMergeReview != "" {
+			var err error
+			if merges, err = readReviewDecisions(associateMergeReview); err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			merges = append(merges, [2]string{args[0], args[1]})
+		}
+
+		failed := 0
+		for _, m := range merges {
+			report, err := ctrl.Merge(context.Background(), m[0], m[1], associateMergeDryRun)
+			if err != nil {
+				// Keep going through the review file as an earlier row may
+				// have already archived either associate.
+				if associateMergeReview == "" || !errors.Is(err, a_c.ErrAlreadyArchived) && !errors.Is(err, a_c.ErrKeepArchived) && !errors.Is(err, a_c.ErrAssociateNotFound) {
+					log.Fatal(err)
+				}
+				fmt.Fprintf(os.Stderr, "skipped %v into %v: %v\n", m[1], m[0], err)
+				failed++
+				continue
+			}
+			printAssociateMergeReport(report)
+		}
+		if associateMergeReview != "" {
+			fmt.Printf("%v of %v merge(s) done\n", len(merges)-failed, len(merges))
+		}
+	},
+}
+
+func newAssociateController(cfg *config.Conf, mc *mongo.Client, tenant *tenant_ds.Tenant) a_c.AssociateController {
+	defaultLogger := slog.Default()
+	return a_c.NewController(
+		cfg,
+		defaultLogger,
+		mc,
+		a_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		o_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		ti_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		as_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		aal_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		com_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		att_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		user_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		newResyncController(cfg, mc, tenant),
+	)
+}
+
+func printAssociateMergeReport(r *a_c.MergeReport) {
+	verb := "Merged"
+	if r.DryRun {
+		verb = "Would merge"
+	}
+	fmt.Printf("%v associate %v into %v: %v order(s), %v task item(s), %v activity sheet(s), %v away log(s), %v comment(s), %v attachment(s)\n",
+		verb, r.DropPublicID, r.KeepPublicID, r.Orders, r.TaskItems, r.ActivitySheets, r.AwayLogs, r.Comments, r.Attachments)
+	fmt.Printf("\tadded %v skill set(s), %v insurance requirement(s), %v vehicle type(s), %v tag(s)\n",
+		r.SkillSets, r.InsuranceRequirements, r.VehicleTypes, r.Tags)
+	if len(r.FilledFields) > 0 {
+		fmt.Printf("\ttaken from the dropped associate: %v\n", strings.Join(r.FilledFields, ", "))
+	}
+	if !r.RelinkedUserID.IsZero() {
+		fmt.Printf("\trelinked user %v\n", r.RelinkedUserID.Hex())
+	}
+	if !r.ArchivedUserID.IsZero() {
+		fmt.Printf("\tarchived user %v\n", r.ArchivedUserID.Hex())
+	}
+	if !r.DryRun {
+		fmt.Printf("\trefreshed %v copied record(s)\n", r.RefreshedCopies)
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go audit --collection="orders" --id="64d0f8c2a1b2c3d4e5f60718"
+
+var (
+	auditCollection string
+	auditDocumentID string
+	auditLimit      int64
+)
+
+func init() {
+	auditCmd.Flags().StringVarP(&auditCollection, "collection", "c", "", "Name of the collection the document belongs to, ex: orders")
+	auditCmd.MarkFlagRequired("collection")
+	auditCmd.Flags().StringVarP(&auditDocumentID, "id", "i", "", "ID of the document to show the history of")
+	auditCmd.MarkFlagRequired("id")
+	auditCmd.Flags().Int64VarP(&auditLimit, "limit", "l", 0, "Maximum number of entries to show, zero shows all")
+	rootCmd.AddCommand(auditCmd)
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the change history of a document",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		defaultLogger := slog.Default()
+		alStorer := auditlog_ds.NewDatastore(cfg, defaultLogger, mc)
+
+		RunAudit(alStorer)
+	},
+}
+
+func RunAudit(alStorer auditlog_ds.AuditLogStorer) {
+	ctx := context.Background()
+
+	documentID, err := primitive.ObjectIDFromHex(auditDocumentID)
+	if err != nil {
+		log.Fatal("invalid document id:", err)
+	}
+
+	res, err := alStorer.ListByFilter(ctx, &auditlog_ds.AuditLogListFilter{
+		PageSize:       auditLimit,
+		SortOrder:      1,
+		CollectionName: auditCollection,
+		DocumentID:     documentID,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(res.Results) == 0 {
+		fmt.Println("No history found")
+		return
+	}
+
+	for _, al := range res.Results {
+		actor := al.ActorUserName
+		if !al.ActorUserID.IsZero() {
+			actor = fmt.Sprintf("%s (%s)", actor, al.ActorUserID.Hex())
+		}
+		fmt.Printf("%s\t%s\tby %s\tvia %s\n",
+			al.CreatedAt.Format("2006-01-02 15:04:05 MST"),
+			auditlog_ds.AuditLogActionLabels[al.Action],
+			actor,
+			al.Command)
+		for _, ch := range al.Changes {
+			fmt.Printf("\t%s: %v -> %v\n", ch.Field, ch.OldValue, ch.NewValue)
+		}
+	}
+}
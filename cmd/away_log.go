@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_c "github.com/over55/workery-cli/app/associateawaylog/controller"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go away-log schedule
+// $ go run main.go away-log schedule --dry-run
+//
+// ex (crontab, every hour):
+// 0 * * * * workery-cli away-log schedule
+
+var (
+	awayLogDryRun bool
+)
+
+func init() {
+	awayLogScheduleCmd.Flags().BoolVarP(&awayLogDryRun, "dry-run", "d", false, "Report the changes without saving them")
+	awayLogScheduleCmd.Annotations = requirePermission("associate:update")
+	awayLogCmd.AddCommand(awayLogScheduleCmd)
+
+	awayLogCmd.Annotations = requirePermission("associate:read")
+	rootCmd.AddCommand(awayLogCmd)
+}
+
+var awayLogCmd = &cobra.Command{
+	Use:   "away-log",
+	Short: "Manage the away logs of associates",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var awayLogScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Activate and archive away logs as their dates pass and list who is away",
+	Long: `Activates the away logs whose start date has arrived, archives the ones
+whose until date has passed (unless they last until further notice), refreshes
+the copies embedded in the associates and lists every associate who is away.
+Meant to be run from cron.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		tenant := getOrderTenant(cfg, mc)
+
+		defaultLogger := slog.Default()
+		ctrl := aal_c.NewController(
+			cfg,
+			defaultLogger,
+			a_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			aal_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		)
+		RunAwayLogSchedule(ctrl)
+	},
+}
+
+func RunAwayLogSchedule(ctrl aal_c.AssociateAwayLogController) {
+	report, err := ctrl.Schedule(context.Background(), awayLogDryRun)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, c := range report.Changes {
+		fmt.Printf("%v\t%v\t%v -> %v\n", c.AwayLogID.Hex(), c.AssociateName, aal_ds.AssociateAwayLogStatusLabels[c.From], aal_ds.AssociateAwayLogStatusLabels[c.To])
+	}
+	if report.DryRun {
+		fmt.Printf("%v away log(s) and %v associate(s) would be updated, no changes were saved\n", len(report.Changes), report.SyncedAssociates)
+	} else {
+		fmt.Printf("%v away log(s) and %v associate(s) updated\n", len(report.Changes), report.SyncedAssociates)
+	}
+
+	fmt.Println()
+	for _, a := range report.Away {
+		until := "until further notice"
+		if !a.UntilFurtherNotice && !a.UntilDate.IsZero() {
+			until = "until " + a.UntilDate.Format("2006-01-02")
+		}
+		fmt.Printf("%v\t%v\tsince %v\t%v\n", a.AssociateID.Hex(), a.AssociateName, a.StartDate.Format("2006-01-02"), until)
+	}
+	fmt.Printf("%v associate(s) away\n", len(report.Away))
+}
@@ -33,17 +33,22 @@ func init() {
 }
 
 var changePasswordCmd = &cobra.Command{
-	Use:   "change_password",
-	Short: "Change user password",
-	Long:  ``,
+	Use:        "change_password",
+	Short:      "Change user password",
+	Long:       ``,
+	Deprecated: "the password ends up in the shell history, use: user reset-password",
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := config.New()
-		pass := password.NewProvider()
+		pass, err := password.NewProviderFromConfig(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
 		mc := mongodb.NewStorage(cfg)
 		ppc := postgres.NewStorage(cfg, cfg.PostgresDB.DatabasePublicSchemaName)
 		lpc := postgres.NewStorage(cfg, cfg.PostgresDB.DatabasePublicSchemaName)
 		defaultLogger := slog.Default()
-		userStorer := user_ds.NewDatastore(cfg, defaultLogger, mc)
+		// Users are looked up by email regardless of which tenant they belong to.
+		userStorer := user_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()
 		runChangePassword(cfg, ppc, lpc, pass, userStorer)
 	},
 }
@@ -59,14 +64,20 @@ func runChangePassword(cfg *config.Conf, public *sql.DB, london *sql.DB, pass p.
 		log.Fatal("User D.N.E.")
 	}
 
+	if err := pass.ValidatePassword(changePassPassword, user.Email, user.FirstName, user.LastName); err != nil {
+		log.Fatal(err)
+	}
+
 	passwordHash, err := pass.GenerateHashFromPassword(changePassPassword)
 	if err != nil {
 		log.Fatal("HashPassword:", err)
 	}
+	user.PasswordHashAlgorithm = pass.AlgorithmName()
 	user.PasswordHash = passwordHash
 
-	us.UpdateByID(ctx, user)
+	if err := us.UpdateByID(ctx, user); err != nil {
+		log.Fatal("UpdateByID:", err)
+	}
 
-	fmt.Print("\033[H\033[2J")
 	fmt.Println("Password successfully changed")
 }
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	comp_c "github.com/over55/workery-cli/app/compliance/controller"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go compliance report
+// $ go run main.go compliance report --within=60 --format=csv --output=compliance.csv
+
+var (
+	complianceWithinDays int
+	complianceFormat     string
+	complianceOutput     string
+)
+
+func init() {
+	complianceReportCmd.Flags().IntVarP(&complianceWithinDays, "within", "w", -1, "Number of days ahead to list expiries for, defaults to WORKERY_COMPLIANCE_EXPIRING_WITHIN_DAYS")
+	complianceReportCmd.Flags().StringVarP(&complianceFormat, "format", "m", "table", "Output format, either table, csv or json")
+	complianceReportCmd.Flags().StringVarP(&complianceOutput, "output", "o", "", "File to write the report to, defaults to stdout")
+	complianceReportCmd.Annotations = requirePermission("report:read")
+	complianceCmd.AddCommand(complianceReportCmd)
+
+	complianceCmd.Annotations = requirePermission("report:read")
+	rootCmd.AddCommand(complianceCmd)
+}
+
+var complianceCmd = &cobra.Command{
+	Use:   "compliance",
+	Short: "Track the insurance, police check and dues expiries of associates",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var complianceReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "List the active associates whose requirements are missing, expired or expiring",
+	Long: `Lists every active associate whose commercial insurance, auto insurance (if
+they have a vehicle), WSIB, police check or dues is missing, expired or expires
+within the number of days. Associates with open orders who are not compliant
+are flagged with OPEN ORDERS.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		tenant := getOrderTenant(cfg, mc)
+
+		defaultLogger := slog.Default()
+		ctrl := comp_c.NewController(
+			cfg,
+			defaultLogger,
+			a_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			o_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		)
+		if complianceWithinDays < 0 {
+			complianceWithinDays = cfg.Compliance.ExpiringWithinDays
+		}
+		RunComplianceReport(ctrl)
+	},
+}
+
+func RunComplianceReport(ctrl comp_c.ComplianceController) {
+	report, err := ctrl.Report(context.Background(), complianceWithinDays)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w := io.Writer(os.Stdout)
+	if complianceOutput != "" {
+		f, err := os.Create(complianceOutput)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch complianceFormat {
+	case "table":
+		err = writeComplianceTable(w, report)
+	case "csv":
+		err = writeComplianceCSV(w, report)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	default:
+		log.Fatalf("unsupported format: %v", complianceFormat)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeComplianceTable(w io.Writer, report []*comp_c.AssociateCompliance) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tREQUIREMENT\tSTATUS\tEXPIRES\tDAYS LEFT\tOPEN ORDERS\t")
+	var flagged int
+	for _, ac := range report {
+		flag := ""
+		if ac.HasOpenOrdersWhileNonCompliant() {
+			flag = "OPEN ORDERS"
+			flagged++
+		}
+		for _, f := range ac.Findings {
+			expires, daysLeft := "-", "-"
+			if !f.ExpiresAt.IsZero() {
+				expires = f.ExpiresAt.Format("2006-01-02")
+				daysLeft = fmt.Sprint(f.DaysLeft)
+			}
+			fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", ac.PublicID, ac.AssociateName, f.Requirement, f.Status, expires, daysLeft, ac.OpenOrders, flag)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%v associate(s) listed, %v non-compliant with open orders\n", len(report), flagged)
+	return err
+}
+
+func writeComplianceCSV(w io.Writer, report []*comp_c.AssociateCompliance) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"associate_id", "public_id", "associate_name", "email", "phone", "requirement", "status", "expires_at", "days_left", "open_orders", "compliant", "open_orders_while_non_compliant"})
+	for _, ac := range report {
+		for _, f := range ac.Findings {
+			expires, daysLeft := "", ""
+			if !f.ExpiresAt.IsZero() {
+				expires = f.ExpiresAt.Format("2006-01-02")
+				daysLeft = fmt.Sprint(f.DaysLeft)
+			}
+			cw.Write([]string{
+				ac.AssociateID.Hex(),
+				fmt.Sprint(ac.PublicID),
+				ac.AssociateName,
+				ac.Email,
+				ac.Phone,
+				f.Requirement,
+				f.Status,
+				expires,
+				daysLeft,
+				fmt.Sprint(ac.OpenOrders),
+				fmt.Sprint(ac.Compliant),
+				fmt.Sprint(ac.HasOpenOrdersWhileNonCompliant()),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	user_c "github.com/over55/workery-cli/app/user/controller"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go credentials list
+// $ go run main.go credentials issue --dry-run
+// $ go run main.go credentials issue --valid-for=14 --format=csv --output=invitations.csv
+
+var (
+	credentialsValidForDays int
+	credentialsReissue      bool
+	credentialsDryRun       bool
+	credentialsFormat       string
+	credentialsOutput       string
+)
+
+func init() {
+	credentialsListCmd.Flags().StringVarP(&credentialsFormat, "format", "m", "table", "Output format, either table, csv or json")
+	credentialsListCmd.Flags().StringVarP(&credentialsOutput, "output", "o", "", "File to write the list to, defaults to stdout")
+	credentialsListCmd.Annotations = requirePermission("user:read")
+	credentialsCmd.AddCommand(credentialsListCmd)
+
+	credentialsIssueCmd.Flags().IntVarP(&credentialsValidForDays, "valid-for", "v", 14, "Number of days the reset tokens are valid for")
+	credentialsIssueCmd.Flags().BoolVarP(&credentialsReissue, "reissue", "r", false, "Replace the reset tokens which have not expired yet")
+	credentialsIssueCmd.Flags().BoolVarP(&credentialsDryRun, "dry-run", "d", false, "List who would be issued a reset token without saving them")
+	credentialsIssueCmd.Flags().StringVarP(&credentialsFormat, "format", "m", "table", "Output format, either table, csv or json")
+	credentialsIssueCmd.Flags().StringVarP(&credentialsOutput, "output", "o", "", "File to write the invitation list to, defaults to stdout")
+	credentialsIssueCmd.Annotations = requirePermission("user:update")
+	credentialsCmd.AddCommand(credentialsIssueCmd)
+
+	credentialsCmd.Annotations = requirePermission("user:read")
+	rootCmd.AddCommand(credentialsCmd)
+}
+
+var credentialsCmd = &cobra.Command{
+	Use:   "credentials",
+	Short: "Onboard the imported users who cannot log in",
+	Long: `The import commands create users with a placeholder password hash, and make
+up an email for the customers, associates and staff who did not have one, so
+these users cannot log in until they are given a way to set their password.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var credentialsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the active users with a placeholder password or a made up email",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		invitations, err := newUserController(cfg, mc, getOrderTenant(cfg, mc)).ListPendingCredentials(context.Background())
+		if err != nil {
+			log.Fatal(err)
+		}
+		writeInvitations(invitations)
+	},
+}
+
+var credentialsIssueCmd = &cobra.Command{
+	Use:   "issue",
+	Short: "Issue one-time reset tokens to the users who cannot log in and export the invitation list",
+	Long: `Gives every active user with a placeholder password or a made up email a
+one-time password reset token (skipping the ones whose token has not expired
+yet unless --reissue is set) and writes the invitation list staff can use to
+reach the people behind these accounts. The list contains the tokens, keep
+it private.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if credentialsValidForDays <= 0 {
+			log.Fatal("--valid-for must be at least one day")
+		}
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		ctrl := newUserController(cfg, mc, getOrderTenant(cfg, mc))
+		validFor := time.Duration(credentialsValidForDays) * 24 * time.Hour
+		invitations, err := ctrl.IssueAccessCodes(context.Background(), validFor, credentialsReissue, credentialsDryRun)
+		if err != nil {
+			log.Fatal(err)
+		}
+		writeInvitations(invitations)
+		if credentialsDryRun {
+			fmt.Fprintf(os.Stderr, "%v reset token(s) would be issued, no changes were saved\n", len(invitations))
+			return
+		}
+		fmt.Fprintf(os.Stderr, "%v reset token(s) issued\n", len(invitations))
+	},
+}
+
+func writeInvitations(invitations []*user_c.Invitation) {
+	w := io.Writer(os.Stdout)
+	if credentialsOutput != "" {
+		f, err := os.OpenFile(credentialsOutput, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	var err error
+	switch credentialsFormat {
+	case "table":
+		err = writeInvitationsTable(w, invitations)
+	case "csv":
+		err = writeInvitationsCSV(w, invitations)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(invitations)
+	default:
+		log.Fatalf("unsupported format: %v", credentialsFormat)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeInvitationsTable(w io.Writer, invitations []*user_c.Invitation) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tEMAIL\tPHONE\tROLE\tREASONS\tEXPIRES\tACCESS CODE\t")
+	for _, inv := range invitations {
+		expires, code := "-", "-"
+		if !inv.ExpiresAt.IsZero() {
+			expires = inv.ExpiresAt.Format("2006-01-02")
+		}
+		if inv.AccessCode != "" {
+			code = inv.AccessCode
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t\n", inv.PublicID, inv.Name, inv.Email, inv.Phone, inv.Role, strings.Join(inv.Reasons, ", "), expires, code)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%v user(s) listed\n", len(invitations))
+	return err
+}
+
+func writeInvitationsCSV(w io.Writer, invitations []*user_c.Invitation) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"user_id", "public_id", "name", "email", "phone", "role", "reasons", "access_code", "expires_at"})
+	for _, inv := range invitations {
+		expires := ""
+		if !inv.ExpiresAt.IsZero() {
+			expires = inv.ExpiresAt.Format(time.RFC3339)
+		}
+		cw.Write([]string{
+			inv.UserID.Hex(),
+			fmt.Sprint(inv.PublicID),
+			inv.Name,
+			inv.Email,
+			inv.Phone,
+			inv.Role,
+			strings.Join(inv.Reasons, ";"),
+			inv.AccessCode,
+			expires,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	att_ds "github.com/over55/workery-cli/app/attachment/datastore"
+	com_ds "github.com/over55/workery-cli/app/comment/datastore"
+	c_c "github.com/over55/workery-cli/app/customer/controller"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	"github.com/over55/workery-cli/app/dedupe"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go customer dedupe --output=review.csv
+// $ go run main.go customer merge 1024 2048 --dry-run
+// $ go run main.go customer merge --review=review.csv
+
+var (
+	customerDedupeThreshold float64
+	customerDedupeFormat    string
+	customerDedupeOutput    string
+	customerMergeReview     string
+	customerMergeDryRun     bool
+)
+
+// reviewDecisionMerge is what staff write in the decision column of the
+// review file for the pairs which are to be merged.
+const reviewDecisionMerge = "merge"
+
+func init() {
+	customerDedupeCmd.Flags().Float64VarP(&customerDedupeThreshold, "threshold", "t", 0.5, "Minimum score, from 0 to 1, for a pair to be listed")
+	customerDedupeCmd.Flags().StringVarP(&customerDedupeFormat, "format", "m", "csv", "Output format, either table, csv or json")
+	customerDedupeCmd.Flags().StringVarP(&customerDedupeOutput, "output", "o", "", "File to write the review list to, defaults to stdout")
+	customerDedupeCmd.Annotations = requirePermission("customer:read")
+	customerCmd.AddCommand(customerDedupeCmd)
+
+	customerMergeCmd.Flags().StringVarP(&customerMergeReview, "review", "r", "", "Review file whose rows with a merge decision are merged")
+	customerMergeCmd.Flags().BoolVarP(&customerMergeDryRun, "dry-run", "d", false, "Report what would be moved without saving the changes")
+	customerMergeCmd.Annotations = requirePermission("customer:update")
+	customerCmd.AddCommand(customerMergeCmd)
+
+	customerCmd.Annotations = requirePermission("customer:read")
+	rootCmd.AddCommand(customerCmd)
+}
+
+var customerCmd = &cobra.Command{
+	Use:   "customer",
+	Short: "Find and merge duplicate customers",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var customerDedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "List the pairs of active customers which are likely the same person",
+	Long: `Scores the pairs of active customers on their email, phone, name and address
+and writes the ones scoring at least --threshold to a review file, best first.
+The older customer of each pair is suggested to be kept. Write "merge" in the
+decision column of the pairs to merge and pass the file to
+"customer merge --review".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		pairs, err := newCustomerController(cfg, mc, getOrderTenant(cfg, mc)).FindDuplicates(context.Background(), customerDedupeThreshold)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := writeDuplicates(pairs, customerDedupeFormat, customerDedupeOutput); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "%v possible duplicate(s) found\n", len(pairs))
+	},
+}
+
+var customerMergeCmd = &cobra.Command{
+	Use:   "merge [keep] [drop]",
+	Short: "Merge a duplicate customer into the customer to keep",
+	Long: `Moves the orders, task items, comments, attachments and tags of the dropped
+customer onto the kept customer, including the ones in the trash, relinks the
+dropped customer's login and archives the dropped customer, all in one
+transaction. The kept customer must not be archived. Customers may be given by
+ID or public ID.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if customerMergeReview != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		ctrl := newCustomerController(cfg, mc, getOrderTenant(cfg, mc))
+
+		merges := [][2]string{}
+		if customerMergeReview != "" {
+			var err error
+			if merges, err = readReviewDecisions(customerMergeReview); err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			merges = append(merges, [2]string{args[0], args[1]})
+		}
+
+		failed := 0
+		for _, m := range merges {
+			report, err := ctrl.Merge(context.Background(), m[0], m[1], customerMergeDryRun)
+			if err != nil {
+				// Keep going through the review file as an earlier row may
+				// have already archived either customer.
+				if customerMergeReview == "" || !errors.Is(err, c_c.ErrAlreadyArchived) && !errors.Is(err, c_c.ErrKeepArchived) && !errors.Is(err, c_c.ErrCustomerNotFound) {
+					log.Fatal(err)
+				}
+				fmt.Fprintf(os.Stderr, "skipped %v into %v: %v\n", m[1], m[0], err)
+				failed++
+				continue
+			}
+			printMergeReport(report)
+		}
+		if customerMergeReview != "" {
+			fmt.Printf("%v of %v merge(s) done\n", len(merges)-failed, len(merges))
+		}
+	},
+}
+
+func newCustomerController(cfg *config.Conf, mc *mongo.Client, tenant *tenant_ds.Tenant) c_c.CustomerController {
+	defaultLogger := slog.Default()
+	return c_c.NewController(
+		cfg,
+		defaultLogger,
+		mc,
+		c_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		o_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		ti_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		com_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		att_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		user_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		newResyncController(cfg, mc, tenant),
+	)
+}
+
+// writeDuplicates writes the pairs found by the dedupe subcommands in the
+// given format, the csv format being the review file read back by the merge
+// subcommands.
+func writeDuplicates(pairs []*dedupe.Pair, format string, output string) error {
+	w := io.Writer(os.Stdout)
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "SCORE\tREASONS\tKEEP\tKEEP NAME\tDROP\tDROP NAME\t")
+		for _, p := range pairs {
+			fmt.Fprintf(tw, "%.2f\t%v\t%v\t%v\t%v\t%v\t\n", p.Score, strings.Join(p.Reasons, ", "), p.KeepPublicID, p.KeepName, p.DropPublicID, p.DropName)
+		}
+		return tw.Flush()
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"decision", "score", "reasons", "keep_id", "keep_public_id", "keep_name", "drop_id", "drop_public_id", "drop_name"})
+		for _, p := range pairs {
+			cw.Write([]string{
+				"",
+				fmt.Sprintf("%.2f", p.Score),
+				strings.Join(p.Reasons, ";"),
+				p.KeepID.Hex(),
+				fmt.Sprint(p.KeepPublicID),
+				p.KeepName,
+				p.DropID.Hex(),
+				fmt.Sprint(p.DropPublicID),
+				p.DropName,
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pairs)
+	}
+	return fmt.Errorf("unsupported format: %v", format)
+}
+
+// readReviewDecisions returns the keep and drop IDs of the rows of the review
+// file marked to be merged. Staff may swap the two ID columns to keep the
+// newer record instead.
+func readReviewDecisions(filePath string) ([][2]string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("review file is empty: %v", filePath)
+	}
+
+	col := make(map[string]int, len(rows[0]))
+	for i, name := range rows[0] {
+		col[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"decision", "keep_id", "drop_id"} {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("review file has no %v column: %v", name, filePath)
+		}
+	}
+
+	merges := [][2]string{}
+	for _, row := range rows[1:] {
+		if !strings.EqualFold(strings.TrimSpace(row[col["decision"]]), reviewDecisionMerge) {
+			continue
+		}
+		merges = append(merges, [2]string{strings.TrimSpace(row[col["keep_id"]]), strings.TrimSpace(row[col["drop_id"]])})
+	}
+	return merges, nil
+}
+
+func printMergeReport(r *c_c.MergeReport) {
+	verb := "Merged"
+	if r.DryRun {
+		verb = "Would merge"
+	}
+	fmt.Printf("%v customer %v into %v: %v order(s), %v task item(s), %v comment(s), %v attachment(s), %v tag(s)\n",
+		verb, r.DropPublicID, r.KeepPublicID, r.Orders, r.TaskItems, r.Comments, r.Attachments, r.Tags)
+	if len(r.FilledFields) > 0 {
+		fmt.Printf("\tfilled in: %v\n", strings.Join(r.FilledFields, ", "))
+	}
+	if !r.RelinkedUserID.IsZero() {
+		fmt.Printf("\trelinked user %v\n", r.RelinkedUserID.Hex())
+	}
+	if !r.ArchivedUserID.IsZero() {
+		fmt.Printf("\tarchived user %v\n", r.ArchivedUserID.Hex())
+	}
+	if !r.DryRun {
+		fmt.Printf("\trefreshed %v copied record(s)\n", r.RefreshedCopies)
+	}
+}
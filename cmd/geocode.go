@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	addr_c "github.com/over55/workery-cli/app/address/controller"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	"github.com/over55/workery-cli/config"
+	"github.com/over55/workery-cli/provider/geocoder"
+)
+
+// ex:
+// $ export WORKERY_GEOCODER_POSTAL_CODE_FILE_PATH=./postal_codes.csv
+// $ go run main.go geocode --dry-run
+// $ go run main.go geocode --customers --unresolved=unresolved.csv
+// $ go run main.go geocode --normalize --dry-run --verbose
+
+var (
+	geocodeCustomers  bool
+	geocodeAssociates bool
+	geocodeRefresh    bool
+	geocodeNormalize  bool
+	geocodeDryRun     bool
+	geocodeVerbose    bool
+	geocodeUnresolved string
+)
+
+func init() {
+	geocodeCmd.Flags().BoolVarP(&geocodeCustomers, "customers", "c", false, "Only geocode the customers")
+	geocodeCmd.Flags().BoolVarP(&geocodeAssociates, "associates", "a", false, "Only geocode the associates")
+	geocodeCmd.Flags().BoolVarP(&geocodeRefresh, "refresh", "r", false, "Geocode the records which already have coordinates again")
+	geocodeCmd.Flags().BoolVarP(&geocodeNormalize, "normalize", "n", false, "Also rewrite the stored addresses in their normalized form")
+	geocodeCmd.Flags().BoolVarP(&geocodeDryRun, "dry-run", "d", false, "Report what would change without saving the changes")
+	geocodeCmd.Flags().BoolVarP(&geocodeVerbose, "verbose", "v", false, "List every changed record along with the fields which changed")
+	geocodeCmd.Flags().StringVarP(&geocodeUnresolved, "unresolved", "u", "", "CSV file to write the addresses which could not be geocoded to")
+	rootCmd.AddCommand(geocodeCmd)
+}
+
+var geocodeCmd = &cobra.Command{
+	Use:   "geocode",
+	Short: "Fill in the coordinates of customers and associates, optionally normalizing their addresses",
+	Long: `Fills in the latitude and longitude of the active customers and associates
+which have none using the geocoder set by WORKERY_GEOCODER_PROVIDER, and
+reports the addresses which could not be resolved. The offline geocoder looks
+up postal code centroids in the CSV file set by
+WORKERY_GEOCODER_POSTAL_CODE_FILE_PATH.
+
+With --normalize the addresses themselves are also rewritten (postal code
+format, province codes, street types, unit and PO box), their full address
+and Google Maps link recompiled, and the copies of the changed addresses held
+by orders and task items refreshed. Review them with --dry-run --verbose
+first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		geo, err := geocoder.NewProviderFromConfig(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		mc := mongodb.NewStorage(cfg)
+		tenant := getOrderTenant(cfg, mc)
+		defaultLogger := slog.Default()
+		ctrl := addr_c.NewController(
+			cfg,
+			defaultLogger,
+			geo,
+			c_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			a_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			newResyncController(cfg, mc, tenant),
+		)
+
+		// Both kinds of records are geocoded unless one was picked.
+		opts := &addr_c.GeocodeOptions{
+			Customers:  geocodeCustomers || !geocodeAssociates,
+			Associates: geocodeAssociates || !geocodeCustomers,
+			Refresh:    geocodeRefresh,
+			Normalize:  geocodeNormalize,
+			DryRun:     geocodeDryRun,
+		}
+		report, err := ctrl.Geocode(context.Background(), opts)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if geocodeVerbose {
+			for _, ch := range report.Changes {
+				fmt.Printf("%v %v %v: %v\n", ch.Kind, ch.PublicID, ch.Name, strings.Join(ch.Fields, ", "))
+			}
+		}
+		if geocodeUnresolved != "" {
+			if err := writeUnresolvedAddresses(geocodeUnresolved, report.Unresolved); err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			for _, u := range report.Unresolved {
+				fmt.Printf("unresolved %v %v %v: %v (%v)\n", u.Kind, u.PublicID, u.Name, u.Address, strings.Join(u.Problems, ", "))
+			}
+		}
+
+		verb := "updated"
+		if report.DryRun {
+			verb = "would be updated"
+		}
+		fmt.Printf("%v record(s) checked, %v geocoded, %v %v, %v unresolved\n",
+			report.Checked, report.Geocoded, len(report.Changes), verb, len(report.Unresolved))
+		if !report.DryRun {
+			fmt.Printf("%v copied record(s) refreshed\n", report.RefreshedCopies)
+		}
+	},
+}
+
+func writeUnresolvedAddresses(filePath string, unresolved []*addr_c.Unresolved) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cw := csv.NewWriter(f)
+	cw.Write([]string{"kind", "id", "public_id", "name", "address", "problems"})
+	for _, u := range unresolved {
+		cw.Write([]string{
+			u.Kind,
+			u.ID.Hex(),
+			fmt.Sprint(u.PublicID),
+			u.Name,
+			u.Address,
+			strings.Join(u.Problems, ";"),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
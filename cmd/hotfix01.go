@@ -48,6 +48,13 @@ var hotfix1Cmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being fixed.
+		userStorer = userStorer.ForTenant(tenant.ID)
+		cStorer = cStorer.ForTenant(tenant.ID)
+		aStorer = aStorer.ForTenant(tenant.ID)
+		sStorer = sStorer.ForTenant(tenant.ID)
+		hhStorer = hhStorer.ForTenant(tenant.ID)
+
 		RunHotfix01(cfg, ppc, lpc, mc, tenantStorer, userStorer, cStorer, aStorer, sStorer, hhStorer, tenant)
 
 	},
@@ -52,6 +52,15 @@ var hotfix02Cmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being fixed.
+		userStorer = userStorer.ForTenant(tenant.ID)
+		cStorer = cStorer.ForTenant(tenant.ID)
+		aStorer = aStorer.ForTenant(tenant.ID)
+		sStorer = sStorer.ForTenant(tenant.ID)
+		oStorer = oStorer.ForTenant(tenant.ID)
+		hhStorer = hhStorer.ForTenant(tenant.ID)
+		attachStorer = attachStorer.ForTenant(tenant.ID)
+
 		RunHotfix02(cfg, ppc, lpc, mc, tenantStorer, userStorer, cStorer, aStorer, sStorer, oStorer, hhStorer, attachStorer, tenant)
 
 	},
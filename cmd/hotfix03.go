@@ -59,6 +59,14 @@ var hotfix03Cmd = &cobra.Command{
 			panic("get schema name")
 		}
 
+		// Constrain every storer to the tenant being fixed.
+		aStorer = aStorer.ForTenant(tenant.ID)
+		uStorer = uStorer.ForTenant(tenant.ID)
+		cStorer = cStorer.ForTenant(tenant.ID)
+		asStorer = asStorer.ForTenant(tenant.ID)
+		oStorer = oStorer.ForTenant(tenant.ID)
+		sStorer = sStorer.ForTenant(tenant.ID)
+
 		RunHotfix03(cfg, defaultLogger, ppc, lpc, aStorer, uStorer, cStorer, asStorer, oStorer, sStorer, tenant, s3, oldS3)
 	},
 }
@@ -45,6 +45,14 @@ var hotfix05Cmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being fixed.
+		userStorer = userStorer.ForTenant(tenant.ID)
+		cStorer = cStorer.ForTenant(tenant.ID)
+		aStorer = aStorer.ForTenant(tenant.ID)
+		sStorer = sStorer.ForTenant(tenant.ID)
+		oStorer = oStorer.ForTenant(tenant.ID)
+		tiStorer = tiStorer.ForTenant(tenant.ID)
+
 		RunHotfix05(cfg, mc, tenantStorer, userStorer, cStorer, aStorer, sStorer, oStorer, tiStorer, tenant)
 
 	},
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go hotfix06
+
+func init() {
+	rootCmd.AddCommand(hotfix06Cmd)
+}
+
+var hotfix06Cmd = &cobra.Command{
+	Use:   "hotfix06",
+	Short: "Convert the fixed Line01..Line15 invoice fields into line items",
+	Long: `Converts the current and past invoices of every order from the fifteen
+fixed lines into line items. The two invoice wide notes are joined into the
+invoice notes and the invoice tax is spread across the line items. Invoices
+whose stored totals do not match their line items are reported but keep their
+stored totals, the others have their totals recomputed from the line items.
+The fixed line fields are removed once converted. Invoices which already have
+line items are left untouched so the command can be run more than once.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("hotfix06")
+
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		defaultLogger := slog.Default()
+		tenantStorer := tenant_ds.NewDatastore(cfg, defaultLogger, mc)
+		oStorer := o_ds.NewDatastore(cfg, defaultLogger, mc)
+		tenant, err := tenantStorer.GetBySchemaName(context.Background(), cfg.PostgresDB.DatabaseLondonSchemaName)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// Constrain every storer to the tenant being fixed.
+		oStorer = oStorer.ForTenant(tenant.ID)
+
+		RunHotfix06(cfg, mc, oStorer, tenant)
+	},
+}
+
+// legacyInvoiceLines holds the fixed line fields of invoices which were saved
+// before invoices had line items.
+type legacyInvoiceLines struct {
+	Line01Qty    int64   `bson:"line_01_qty"`
+	Line01Desc   string  `bson:"line_01_desc"`
+	Line01Price  float64 `bson:"line_01_price"`
+	Line01Amount float64 `bson:"line_01_amount"`
+	Line02Qty    int64   `bson:"line_02_qty"`
+	Line02Desc   string  `bson:"line_02_desc"`
+	Line02Price  float64 `bson:"line_02_price"`
+	Line02Amount float64 `bson:"line_02_amount"`
+	Line03Qty    int64   `bson:"line_03_qty"`
+	Line03Desc   string  `bson:"line_03_desc"`
+	Line03Price  float64 `bson:"line_03_price"`
+	Line03Amount float64 `bson:"line_03_amount"`
+	Line04Qty    int64   `bson:"line_04_qty"`
+	Line04Desc   string  `bson:"line_04_desc"`
+	Line04Price  float64 `bson:"line_04_price"`
+	Line04Amount float64 `bson:"line_04_amount"`
+	Line05Qty    int64   `bson:"line_05_qty"`
+	Line05Desc   string  `bson:"line_05_desc"`
+	Line05Price  float64 `bson:"line_05_price"`
+	Line05Amount float64 `bson:"line_05_amount"`
+	Line06Qty    int64   `bson:"line_06_qty"`
+	Line06Desc   string  `bson:"line_06_desc"`
+	Line06Price  float64 `bson:"line_06_price"`
+	Line06Amount float64 `bson:"line_06_amount"`
+	Line07Qty    int64   `bson:"line_07_qty"`
+	Line07Desc   string  `bson:"line_07_desc"`
+	Line07Price  float64 `bson:"line_07_price"`
+	Line07Amount float64 `bson:"line_07_amount"`
+	Line08Qty    int64   `bson:"line_08_qty"`
+	Line08Desc   string  `bson:"line_08_desc"`
+	Line08Price  float64 `bson:"line_08_price"`
+	Line08Amount float64 `bson:"line_08_amount"`
+	Line09Qty    int64   `bson:"line_09_qty"`
+	Line09Desc   string  `bson:"line_09_desc"`
+	Line09Price  float64 `bson:"line_09_price"`
+	Line09Amount float64 `bson:"line_09_amount"`
+	Line10Qty    int64   `bson:"line_10_qty"`
+	Line10Desc   string  `bson:"line_10_desc"`
+	Line10Price  float64 `bson:"line_10_price"`
+	Line10Amount float64 `bson:"line_10_amount"`
+	Line11Qty    int64   `bson:"line_11_qty"`
+	Line11Desc   string  `bson:"line_11_desc"`
+	Line11Price  float64 `bson:"line_11_price"`
+	Line11Amount float64 `bson:"line_11_amount"`
+	Line12Qty    int64   `bson:"line_12_qty"`
+	Line12Desc   string  `bson:"line_12_desc"`
+	Line12Price  float64 `bson:"line_12_price"`
+	Line12Amount float64 `bson:"line_12_amount"`
+	Line13Qty    int64   `bson:"line_13_qty"`
+	Line13Desc   string  `bson:"line_13_desc"`
+	Line13Price  float64 `bson:"line_13_price"`
+	Line13Amount float64 `bson:"line_13_amount"`
+	Line14Qty    int64   `bson:"line_14_qty"`
+	Line14Desc   string  `bson:"line_14_desc"`
+	Line14Price  float64 `bson:"line_14_price"`
+	Line14Amount float64 `bson:"line_14_amount"`
+	Line15Qty    int64   `bson:"line_15_qty"`
+	Line15Desc   string  `bson:"line_15_desc"`
+	Line15Price  float64 `bson:"line_15_price"`
+	Line15Amount float64 `bson:"line_15_amount"`
+	Line01Notes  string  `bson:"line_01_notes"`
+	Line02Notes  string  `bson:"line_02_notes"`
+}
+
+func (li *legacyInvoiceLines) toLineItems() []*o_ds.InvoiceLineItem {
+	return o_ds.CompactInvoiceLineItems([]*o_ds.InvoiceLineItem{
+		{Quantity: li.Line01Qty, Description: li.Line01Desc, UnitPrice: li.Line01Price, Amount: li.Line01Amount},
+		{Quantity: li.Line02Qty, Description: li.Line02Desc, UnitPrice: li.Line02Price, Amount: li.Line02Amount},
+		{Quantity: li.Line03Qty, Description: li.Line03Desc, UnitPrice: li.Line03Price, Amount: li.Line03Amount},
+		{Quantity: li.Line04Qty, Description: li.Line04Desc, UnitPrice: li.Line04Price, Amount: li.Line04Amount},
+		{Quantity: li.Line05Qty, Description: li.Line05Desc, UnitPrice: li.Line05Price, Amount: li.Line05Amount},
+		{Quantity: li.Line06Qty, Description: li.Line06Desc, UnitPrice: li.Line06Price, Amount: li.Line06Amount},
+		{Quantity: li.Line07Qty, Description: li.Line07Desc, UnitPrice: li.Line07Price, Amount: li.Line07Amount},
+		{Quantity: li.Line08Qty, Description: li.Line08Desc, UnitPrice: li.Line08Price, Amount: li.Line08Amount},
+		{Quantity: li.Line09Qty, Description: li.Line09Desc, UnitPrice: li.Line09Price, Amount: li.Line09Amount},
+		{Quantity: li.Line10Qty, Description: li.Line10Desc, UnitPrice: li.Line10Price, Amount: li.Line10Amount},
+		{Quantity: li.Line11Qty, Description: li.Line11Desc, UnitPrice: li.Line11Price, Amount: li.Line11Amount},
+		{Quantity: li.Line12Qty, Description: li.Line12Desc, UnitPrice: li.Line12Price, Amount: li.Line12Amount},
+		{Quantity: li.Line13Qty, Description: li.Line13Desc, UnitPrice: li.Line13Price, Amount: li.Line13Amount},
+		{Quantity: li.Line14Qty, Description: li.Line14Desc, UnitPrice: li.Line14Price, Amount: li.Line14Amount},
+		{Quantity: li.Line15Qty, Description: li.Line15Desc, UnitPrice: li.Line15Price, Amount: li.Line15Amount},
+	})
+}
+
+func (li *legacyInvoiceLines) toNotes() string {
+	var notes []string
+	for _, n := range []string{li.Line01Notes, li.Line02Notes} {
+		if strings.TrimSpace(n) != "" {
+			notes = append(notes, n)
+		}
+	}
+	return strings.Join(notes, "\n")
+}
+
+// legacyInvoiceFields returns the fixed line fields of the invoice stored
+// under `prefix` in the form expected by `$unset`.
+func legacyInvoiceFields(prefix string) bson.M {
+	fields := bson.M{
+		prefix + ".line_01_notes": "",
+		prefix + ".line_02_notes": "",
+	}
+	for i := 1; i <= 15; i++ {
+		for _, name := range []string{"qty", "desc", "price", "amount"} {
+			fields[fmt.Sprintf("%v.line_%02d_%v", prefix, i, name)] = ""
+		}
+	}
+	return fields
+}
+
+type legacyInvoiceOrder struct {
+	ID           primitive.ObjectID    `bson:"_id"`
+	Invoice      *legacyInvoiceLines   `bson:"invoice"`
+	PastInvoices []*legacyInvoiceLines `bson:"past_invoices"`
+}
+
+func RunHotfix06(cfg *config.Conf, mc *mongo.Client, oStorer o_ds.OrderStorer, tenant *tenant_ds.Tenant) {
+	ctx := context.Background()
+
+	// DEVELOPERS NOTE: The order model no longer has the fixed line fields so
+	// we read them from the raw documents.
+	coll := mc.Database(cfg.DB.Name).Collection("orders")
+	cur, err := coll.Find(ctx, bson.M{"tenant_id": tenant.ID})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cur.Close(ctx)
+
+	var converted, mismatched int
+	for cur.Next(ctx) {
+		var legacy legacyInvoiceOrder
+		if err := cur.Decode(&legacy); err != nil {
+			log.Fatal(err)
+		}
+
+		o, err := oStorer.GetByID(ctx, legacy.ID)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if o == nil {
+			continue
+		}
+
+		var changed bool
+		convert := func(oi *o_ds.OrderInvoice, li *legacyInvoiceLines) {
+			if oi == nil || li == nil || oi.LineItems != nil {
+				return // Nothing to convert or already converted.
+			}
+			oi.LineItems = li.toLineItems()
+			oi.Notes = li.toNotes()
+			oi.SpreadTax(oi.Tax)
+			if err := oi.ValidateTotals(); err != nil {
+				log.Println("WJID", o.WJID, "| invoice", oi.ID.Hex(), "|", err)
+				mismatched++
+			} else {
+				oi.ComputeTotals()
+			}
+			changed = true
+			converted++
+		}
+		convert(o.Invoice, legacy.Invoice)
+		for i, oi := range o.PastInvoices {
+			if i < len(legacy.PastInvoices) {
+				convert(oi, legacy.PastInvoices[i])
+			}
+		}
+
+		if changed {
+			if err := oStorer.UpdateByID(ctx, o); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		// Remove the fixed line fields so no stale copy is left behind.
+		unset := legacyInvoiceFields("invoice")
+		for i := range legacy.PastInvoices {
+			for k := range legacyInvoiceFields(fmt.Sprintf("past_invoices.%d", i)) {
+				unset[k] = ""
+			}
+		}
+		if _, err := coll.UpdateOne(ctx, bson.M{"_id": legacy.ID, "tenant_id": tenant.ID}, bson.M{"$unset": unset}); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := cur.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("%v invoice(s) converted, %v with totals which do not match their line items\n", converted, mismatched)
+}
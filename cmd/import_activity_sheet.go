@@ -51,6 +51,12 @@ var importActivitySheetCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		asStorer = asStorer.ForTenant(tenant.ID)
+		uStorer = uStorer.ForTenant(tenant.ID)
+		aStorer = aStorer.ForTenant(tenant.ID)
+		oStorer = oStorer.ForTenant(tenant.ID)
+
 		RunImportActivitySheet(cfg, ppc, lpc, aStorer, asStorer, uStorer, oStorer, tenant)
 	},
 }
@@ -19,6 +19,7 @@ import (
 	hh_ds "github.com/over55/workery-cli/app/howhear/datastore"
 	sf_ds "github.com/over55/workery-cli/app/servicefee/datastore"
 	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	"github.com/over55/workery-cli/app/user/credentials"
 	user_ds "github.com/over55/workery-cli/app/user/datastore"
 	"github.com/over55/workery-cli/config"
 )
@@ -48,6 +49,12 @@ var importAssociateCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		userStorer = userStorer.ForTenant(tenant.ID)
+		aStorer = aStorer.ForTenant(tenant.ID)
+		hhStorer = hhStorer.ForTenant(tenant.ID)
+		sfStorer = sfStorer.ForTenant(tenant.ID)
+
 		RunImportAssociate(cfg, ppc, lpc, tenantStorer, userStorer, aStorer, hhStorer, sfStorer, tenant)
 	},
 }
@@ -391,7 +398,7 @@ func importAssociate(ctx context.Context, ts tenant_ds.TenantStorer, us user_ds.
 			u.Status = user_ds.UserStatusArchived
 		}
 		u.PasswordHashAlgorithm = primitive.NewObjectID().Hex()
-		u.PasswordHash = "MongoDB Primitive"
+		u.PasswordHash = credentials.PlaceholderPasswordHash
 		u.Role = user_ds.UserRoleAssociate
 		u.ReferenceID = associateID // Important!
 		u.WasEmailVerified = true
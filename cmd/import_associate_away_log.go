@@ -49,6 +49,11 @@ var importAssociateAwayLogCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		uStorer = uStorer.ForTenant(tenant.ID)
+		aStorer = aStorer.ForTenant(tenant.ID)
+		aalStorer = aalStorer.ForTenant(tenant.ID)
+
 		RunImportAssociateAwayLog(cfg, ppc, lpc, uStorer, aStorer, aalStorer, tenant)
 	},
 }
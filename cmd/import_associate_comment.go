@@ -46,6 +46,12 @@ var importAssociateCommentCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		userStorer = userStorer.ForTenant(tenant.ID)
+		custStorer = custStorer.ForTenant(tenant.ID)
+		hhStorer = hhStorer.ForTenant(tenant.ID)
+		comStorer = comStorer.ForTenant(tenant.ID)
+
 		RunImportAssociateComment(cfg, ppc, lpc, tenantStorer, userStorer, custStorer, hhStorer, comStorer, tenant)
 	},
 }
@@ -46,6 +46,12 @@ var importAssociateInsuranceRequirementCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		userStorer = userStorer.ForTenant(tenant.ID)
+		aStorer = aStorer.ForTenant(tenant.ID)
+		hhStorer = hhStorer.ForTenant(tenant.ID)
+		irStorer = irStorer.ForTenant(tenant.ID)
+
 		RunImportAssociateInsuranceRequirement(cfg, ppc, lpc, tenantStorer, userStorer, aStorer, hhStorer, irStorer, tenant)
 	},
 }
@@ -45,6 +45,10 @@ var importAssociateSkillSetCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		vtStorer = vtStorer.ForTenant(tenant.ID)
+		aStorer = aStorer.ForTenant(tenant.ID)
+
 		RunImportAssociateSkillSet(cfg, ppc, lpc, vtStorer, aStorer, tenant)
 	},
 }
@@ -45,6 +45,12 @@ var importAssociateStatusCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		userStorer = userStorer.ForTenant(tenant.ID)
+		aStorer = aStorer.ForTenant(tenant.ID)
+		hhStorer = hhStorer.ForTenant(tenant.ID)
+		sfStorer = sfStorer.ForTenant(tenant.ID)
+
 		RunImportAssociateStatus(cfg, ppc, lpc, tenantStorer, userStorer, aStorer, hhStorer, sfStorer, tenant)
 	},
 }
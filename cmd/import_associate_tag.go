@@ -46,6 +46,12 @@ var importAssociateTagCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		userStorer = userStorer.ForTenant(tenant.ID)
+		custStorer = custStorer.ForTenant(tenant.ID)
+		hhStorer = hhStorer.ForTenant(tenant.ID)
+		tagStorer = tagStorer.ForTenant(tenant.ID)
+
 		RunImportAssociateTag(cfg, ppc, lpc, tenantStorer, userStorer, custStorer, hhStorer, tagStorer, tenant)
 	},
 }
@@ -47,6 +47,10 @@ var importBulletinCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		cStorer = cStorer.ForTenant(tenant.ID)
+		userStorer = userStorer.ForTenant(tenant.ID)
+
 		RunImportBulletin(cfg, ppc, lpc, cStorer, userStorer, tenant)
 	},
 }
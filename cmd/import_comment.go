@@ -47,6 +47,10 @@ var importCommentCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		cStorer = cStorer.ForTenant(tenant.ID)
+		userStorer = userStorer.ForTenant(tenant.ID)
+
 		RunImportComment(cfg, ppc, lpc, cStorer, userStorer, tenant)
 	},
 }
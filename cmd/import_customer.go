@@ -19,6 +19,7 @@ import (
 	c_ds "github.com/over55/workery-cli/app/customer/datastore"
 	hh_ds "github.com/over55/workery-cli/app/howhear/datastore"
 	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	"github.com/over55/workery-cli/app/user/credentials"
 	user_ds "github.com/over55/workery-cli/app/user/datastore"
 	"github.com/over55/workery-cli/config"
 )
@@ -47,6 +48,11 @@ var importCustomerCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		userStorer = userStorer.ForTenant(tenant.ID)
+		cStorer = cStorer.ForTenant(tenant.ID)
+		hhStorer = hhStorer.ForTenant(tenant.ID)
+
 		RunImportCustomer(cfg, ppc, lpc, tenantStorer, userStorer, cStorer, hhStorer, tenant)
 	},
 }
@@ -368,7 +374,7 @@ func importCustomer(ctx context.Context, ts tenant_ds.TenantStorer, us user_ds.U
 			u.Status = user_ds.UserStatusArchived
 		}
 		u.PasswordHashAlgorithm = primitive.NewObjectID().Hex()
-		u.PasswordHash = "MongoDB Primitive"
+		u.PasswordHash = credentials.PlaceholderPasswordHash
 		u.Role = user_ds.UserRoleCustomer
 		u.ReferenceID = customerID // Important!
 		u.WasEmailVerified = true
@@ -44,6 +44,9 @@ var importHowHearAboutUsItemCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		hhStorer = hhStorer.ForTenant(tenant.ID)
+
 		RunImportHowHearAboutUsItem(cfg, ppc, lpc, hhStorer, tenant)
 	},
 }
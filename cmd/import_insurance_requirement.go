@@ -43,6 +43,9 @@ var importInsuranceRequirementCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		irStorer = irStorer.ForTenant(tenant.ID)
+
 		RunImportInsuranceRequirement(cfg, ppc, lpc, irStorer, tenant)
 	},
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/over55/workery-cli/adapter/storage/mongodb"
 	"github.com/over55/workery-cli/adapter/storage/postgres"
 	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	"github.com/over55/workery-cli/app/convert"
 	c_ds "github.com/over55/workery-cli/app/customer/datastore"
 	o_ds "github.com/over55/workery-cli/app/order/datastore"
 	sf_ds "github.com/over55/workery-cli/app/servicefee/datastore"
@@ -54,6 +55,14 @@ var importOrderCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		oStorer = oStorer.ForTenant(tenant.ID)
+		uStorer = uStorer.ForTenant(tenant.ID)
+		aStorer = aStorer.ForTenant(tenant.ID)
+		cStorer = cStorer.ForTenant(tenant.ID)
+		ssStorer = ssStorer.ForTenant(tenant.ID)
+		sfStorer = sfStorer.ForTenant(tenant.ID)
+
 		RunImportOrder(cfg, ppc, lpc, oStorer, uStorer, aStorer, cStorer, ssStorer, sfStorer, tenant)
 	},
 }
@@ -471,7 +480,7 @@ func importOrder(
 		CustomerOtherPhoneExtension:           customerOtherPhoneExtension,
 		CustomerFullAddressWithoutPostalCode:  customerFullAddressWithoutPostalCode,
 		CustomerFullAddressURL:                customerFullAddressURL,
-		CustomerTags:                          toOrderTagsFromCustomerTags(customerTags),
+		CustomerTags:                          convert.ToOrderTagsFromCustomerTags(customerTags),
 		AssociateID:                           associateID,
 		AssociateOrganizationName:             associateOrganizationName,
 		AssociateOrganizationType:             associateOrganizationType,
@@ -492,10 +501,10 @@ func importOrder(
 		AssociateOtherPhoneExtension:          associateOtherPhoneExtension,
 		AssociateFullAddressWithoutPostalCode: associateFullAddressWithoutPostalCode,
 		AssociateFullAddressURL:               associateFullAddressURL,
-		AssociateTags:                         toOrderTagsFromAssociateTags(associateTags),
-		AssociateSkillSets:                    toOrderSkillSetsFromAssociateSkillSets(associateSkillSets),
-		AssociateInsuranceRequirements:        toOrderInsuranceRequirementsFromAssociateInsuranceRequirements(associateInsuranceRequirements),
-		AssociateVehicleTypes:                 toOrderVehicleTypesFromAssociateVehicleTypes(associateVehicleTypes),
+		AssociateTags:                         convert.ToOrderTagsFromAssociateTags(associateTags),
+		AssociateSkillSets:                    convert.ToOrderSkillSetsFromAssociateSkillSets(associateSkillSets),
+		AssociateInsuranceRequirements:        convert.ToOrderInsuranceRequirementsFromAssociateInsuranceRequirements(associateInsuranceRequirements),
+		AssociateVehicleTypes:                 convert.ToOrderVehicleTypesFromAssociateVehicleTypes(associateVehicleTypes),
 		AssociateTaxID:                        associateTaxID,
 		Description:                           wo.Description,
 		AssignmentDate:                        wo.AssignmentDate.ValueOrZero(),
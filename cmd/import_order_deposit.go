@@ -50,6 +50,12 @@ var importOrderDepositCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		oStorer = oStorer.ForTenant(tenant.ID)
+		uStorer = uStorer.ForTenant(tenant.ID)
+		cStorer = cStorer.ForTenant(tenant.ID)
+		aStorer = aStorer.ForTenant(tenant.ID)
+
 		RunImportOrderDeposit(cfg, ppc, lpc, oStorer, uStorer, aStorer, cStorer, tenant)
 	},
 }
@@ -170,9 +176,9 @@ func importOrderDeposit(
 	// Status
 	//
 
-	var state int8 = 1
+	var state int8 = order_ds.OrderDepositStatusActive
 	if od.IsArchived == true {
-		state = 2
+		state = order_ds.OrderDepositStatusArchived
 	}
 
 	//
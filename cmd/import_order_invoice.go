@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"log/slog"
@@ -44,6 +45,10 @@ var importOrderInvoiceCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		userStorer = userStorer.ForTenant(tenant.ID)
+		oStorer = oStorer.ForTenant(tenant.ID)
+
 		RunImportOrderInvoice(cfg, ppc, lpc, tenantStorer, userStorer, oStorer, tenant)
 	},
 }
@@ -363,6 +368,36 @@ func importOrderInvoice(
 		paymentMethods = append(paymentMethods, o_ds.PaymentMethodOther)
 	}
 
+	//
+	// Create the line items from the fifteen fixed lines of the old invoice.
+	//
+
+	lineItems := o_ds.CompactInvoiceLineItems([]*o_ds.InvoiceLineItem{
+		{Quantity: int64(oi.Line01Qty), Description: oi.Line01Desc, UnitPrice: oi.Line01Price, Amount: oi.Line01Amount},
+		{Quantity: oi.Line02Qty.ValueOrZero(), Description: oi.Line02Desc.ValueOrZero(), UnitPrice: oi.Line02Price.ValueOrZero(), Amount: oi.Line02Amount.ValueOrZero()},
+		{Quantity: oi.Line03Qty.ValueOrZero(), Description: oi.Line03Desc.ValueOrZero(), UnitPrice: oi.Line03Price.ValueOrZero(), Amount: oi.Line03Amount.ValueOrZero()},
+		{Quantity: oi.Line04Qty.ValueOrZero(), Description: oi.Line04Desc.ValueOrZero(), UnitPrice: oi.Line04Price.ValueOrZero(), Amount: oi.Line04Amount.ValueOrZero()},
+		{Quantity: oi.Line05Qty.ValueOrZero(), Description: oi.Line05Desc.ValueOrZero(), UnitPrice: oi.Line05Price.ValueOrZero(), Amount: oi.Line05Amount.ValueOrZero()},
+		{Quantity: oi.Line06Qty.ValueOrZero(), Description: oi.Line06Desc.ValueOrZero(), UnitPrice: oi.Line06Price.ValueOrZero(), Amount: oi.Line06Amount.ValueOrZero()},
+		{Quantity: oi.Line07Qty.ValueOrZero(), Description: oi.Line07Desc.ValueOrZero(), UnitPrice: oi.Line07Price.ValueOrZero(), Amount: oi.Line07Amount.ValueOrZero()},
+		{Quantity: oi.Line08Qty.ValueOrZero(), Description: oi.Line08Desc.ValueOrZero(), UnitPrice: oi.Line08Price.ValueOrZero(), Amount: oi.Line08Amount.ValueOrZero()},
+		{Quantity: oi.Line09Qty.ValueOrZero(), Description: oi.Line09Desc.ValueOrZero(), UnitPrice: oi.Line09Price.ValueOrZero(), Amount: oi.Line09Amount.ValueOrZero()},
+		{Quantity: oi.Line10Qty.ValueOrZero(), Description: oi.Line10Desc.ValueOrZero(), UnitPrice: oi.Line10Price.ValueOrZero(), Amount: oi.Line10Amount.ValueOrZero()},
+		{Quantity: oi.Line11Qty.ValueOrZero(), Description: oi.Line11Desc.ValueOrZero(), UnitPrice: oi.Line11Price.ValueOrZero(), Amount: oi.Line11Amount.ValueOrZero()},
+		{Quantity: oi.Line12Qty.ValueOrZero(), Description: oi.Line12Desc.ValueOrZero(), UnitPrice: oi.Line12Price.ValueOrZero(), Amount: oi.Line12Amount.ValueOrZero()},
+		{Quantity: oi.Line13Qty.ValueOrZero(), Description: oi.Line13Desc.ValueOrZero(), UnitPrice: oi.Line13Price.ValueOrZero(), Amount: oi.Line13Amount.ValueOrZero()},
+		{Quantity: oi.Line14Qty.ValueOrZero(), Description: oi.Line14Desc.ValueOrZero(), UnitPrice: oi.Line14Price.ValueOrZero(), Amount: oi.Line14Amount.ValueOrZero()},
+		{Quantity: oi.Line15Qty.ValueOrZero(), Description: oi.Line15Desc.ValueOrZero(), UnitPrice: oi.Line15Price.ValueOrZero(), Amount: oi.Line15Amount.ValueOrZero()},
+	})
+
+	// The old invoice had two lines of notes for the whole invoice.
+	var notes []string
+	for _, n := range []string{oi.Line01Notes.ValueOrZero(), oi.Line02Notes.ValueOrZero()} {
+		if strings.TrimSpace(n) != "" {
+			notes = append(notes, n)
+		}
+	}
+
 	//
 	// Create the order invoice.
 	//
@@ -380,72 +415,12 @@ func importOrderInvoice(
 		ClientName:               oi.ClientName,                        // 9
 		ClientPhone:              oi.ClientTelephone,                   // 10
 		ClientEmail:              oi.ClientEmail.ValueOrZero(),         // 11
-		Line01Qty:                int64(oi.Line01Qty),                  // 12
-		Line01Desc:               oi.Line01Desc,                        // 13
-		Line01Price:              oi.Line01Price,                       // 14
-		Line01Amount:             oi.Line01Amount,                      // 15
-		Line02Qty:                oi.Line02Qty.ValueOrZero(),           // 16
-		Line02Desc:               oi.Line02Desc.ValueOrZero(),          // 17
-		Line02Price:              oi.Line02Price.ValueOrZero(),         // 18
-		Line02Amount:             oi.Line02Amount.ValueOrZero(),        // 19
-		Line03Qty:                oi.Line03Qty.ValueOrZero(),           // 20
-		Line03Desc:               oi.Line03Desc.ValueOrZero(),          // 21
-		Line03Price:              oi.Line03Price.ValueOrZero(),         // 22
-		Line03Amount:             oi.Line03Amount.ValueOrZero(),        // 23
-		Line04Qty:                oi.Line04Qty.ValueOrZero(),           // 24
-		Line04Desc:               oi.Line04Desc.ValueOrZero(),          // 25
-		Line04Price:              oi.Line04Price.ValueOrZero(),         // 26
-		Line04Amount:             oi.Line04Amount.ValueOrZero(),        // 27
-		Line05Qty:                oi.Line05Qty.ValueOrZero(),           // 28
-		Line05Desc:               oi.Line05Desc.ValueOrZero(),          // 29
-		Line05Price:              oi.Line05Price.ValueOrZero(),         // 30
-		Line05Amount:             oi.Line05Amount.ValueOrZero(),        // 31
-		Line06Qty:                oi.Line06Qty.ValueOrZero(),           // 32
-		Line06Desc:               oi.Line06Desc.ValueOrZero(),          // 33
-		Line06Price:              oi.Line06Price.ValueOrZero(),         // 34
-		Line06Amount:             oi.Line06Amount.ValueOrZero(),        // 35
-		Line07Qty:                oi.Line07Qty.ValueOrZero(),           // 36
-		Line07Desc:               oi.Line07Desc.ValueOrZero(),          // 37
-		Line07Price:              oi.Line07Price.ValueOrZero(),         // 38
-		Line07Amount:             oi.Line07Amount.ValueOrZero(),        // 39
-		Line08Qty:                oi.Line08Qty.ValueOrZero(),           // 40
-		Line08Desc:               oi.Line08Desc.ValueOrZero(),          // 41
-		Line08Price:              oi.Line08Price.ValueOrZero(),         // 42
-		Line08Amount:             oi.Line08Amount.ValueOrZero(),        // 43
-		Line09Qty:                oi.Line09Qty.ValueOrZero(),           // 44
-		Line09Desc:               oi.Line09Desc.ValueOrZero(),          // 45
-		Line09Price:              oi.Line09Price.ValueOrZero(),         // 46
-		Line09Amount:             oi.Line09Amount.ValueOrZero(),        // 47
-		Line10Qty:                oi.Line10Qty.ValueOrZero(),           // 48
-		Line10Desc:               oi.Line10Desc.ValueOrZero(),          // 49
-		Line10Price:              oi.Line10Price.ValueOrZero(),         // 50
-		Line10Amount:             oi.Line10Amount.ValueOrZero(),        // 51
-		Line11Qty:                oi.Line11Qty.ValueOrZero(),           // 52
-		Line11Desc:               oi.Line11Desc.ValueOrZero(),          // 53
-		Line11Price:              oi.Line11Price.ValueOrZero(),         // 54
-		Line11Amount:             oi.Line11Amount.ValueOrZero(),        // 55
-		Line12Qty:                oi.Line12Qty.ValueOrZero(),           // 56
-		Line12Desc:               oi.Line12Desc.ValueOrZero(),          // 57
-		Line12Price:              oi.Line12Price.ValueOrZero(),         // 58
-		Line12Amount:             oi.Line12Amount.ValueOrZero(),        // 59
-		Line13Qty:                oi.Line13Qty.ValueOrZero(),           // 60
-		Line13Desc:               oi.Line13Desc.ValueOrZero(),          // 61
-		Line13Price:              oi.Line13Price.ValueOrZero(),         // 62
-		Line13Amount:             oi.Line13Amount.ValueOrZero(),        // 63
-		Line14Qty:                oi.Line14Qty.ValueOrZero(),           // 64
-		Line14Desc:               oi.Line14Desc.ValueOrZero(),          // 65
-		Line14Price:              oi.Line14Price.ValueOrZero(),         // 66
-		Line14Amount:             oi.Line14Amount.ValueOrZero(),        // 67
-		Line15Qty:                oi.Line15Qty.ValueOrZero(),           // 68
-		Line15Desc:               oi.Line15Desc.ValueOrZero(),          // 69
-		Line15Price:              oi.Line15Price.ValueOrZero(),         // 70
-		Line15Amount:             oi.Line15Amount.ValueOrZero(),        // 71
+		LineItems:                lineItems,                            // 12-71
 		InvoiceQuoteDays:         oi.InvoiceQuoteDays,                  // 72
 		InvoiceAssociateTax:      oi.InvoiceAssociateTax.ValueOrZero(), // 73
 		InvoiceQuoteDate:         oi.InvoiceQuoteDate,                  // 74
 		InvoiceCustomersApproval: oi.InvoiceCustomersApproval,          // 75
-		Line01Notes:              oi.Line01Notes.ValueOrZero(),         // 76
-		Line02Notes:              oi.Line02Notes.ValueOrZero(),         // 77
+		Notes:                    strings.Join(notes, "\n"),            // 76-77
 		TotalLabour:              oi.TotalLabour,                       // 78
 		TotalMaterials:           oi.TotalMaterials,                    // 79
 		OtherCosts:               oi.OtherCosts,                        // 80
@@ -476,6 +451,16 @@ func importOrderInvoice(
 		// State:                 oi.State,           // 102 (TODO)
 	}
 
+	// The old invoice only recorded the total tax. Totals which do not match
+	// the line items are what the customer was billed so they are reported
+	// and kept, the others are recomputed to the cent.
+	oc.SpreadTax(oi.Tax)
+	if err := oc.ValidateTotals(); err != nil {
+		log.Println("Order Invoice ID#", oc.ID, "for OrderID", order.ID, "warning:", err)
+	} else {
+		oc.ComputeTotals()
+	}
+
 	// Append invoices to order details.
 	order.PastInvoices = append(order.PastInvoices, oc)
 
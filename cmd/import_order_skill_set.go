@@ -44,6 +44,10 @@ var importOrderSkillSetCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		vtStorer = vtStorer.ForTenant(tenant.ID)
+		oStorer = oStorer.ForTenant(tenant.ID)
+
 		RunImportOrderSkillSet(cfg, ppc, lpc, vtStorer, oStorer, tenant)
 	},
 }
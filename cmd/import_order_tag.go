@@ -46,6 +46,12 @@ var importOrderTagCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		userStorer = userStorer.ForTenant(tenant.ID)
+		oStorer = oStorer.ForTenant(tenant.ID)
+		hhStorer = hhStorer.ForTenant(tenant.ID)
+		comStorer = comStorer.ForTenant(tenant.ID)
+
 		RunImportOrderTag(cfg, ppc, lpc, tenantStorer, userStorer, oStorer, hhStorer, comStorer, tenant)
 	},
 }
@@ -45,6 +45,9 @@ var importServiceFeeCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		irStorer = irStorer.ForTenant(tenant.ID)
+
 		RunImportServiceFee(cfg, ppc, lpc, irStorer, tenant)
 	},
 }
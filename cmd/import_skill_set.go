@@ -45,6 +45,9 @@ var importSkillSetCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		ssStorer = ssStorer.ForTenant(tenant.ID)
+
 		if tenant == nil {
 			err := errors.New("tenant does not exist in `importSkillSetCmd` function")
 			log.Fatal(err)
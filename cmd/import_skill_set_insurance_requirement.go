@@ -44,6 +44,10 @@ var importSkillSetInsuranceRequirementCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		ssStorer = ssStorer.ForTenant(tenant.ID)
+		irStorer = irStorer.ForTenant(tenant.ID)
+
 		RunImportSkillSetInsuranceRequirement(cfg, ppc, lpc, ssStorer, irStorer, tenant)
 	},
 }
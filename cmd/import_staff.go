@@ -48,6 +48,11 @@ var importStaffCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		userStorer = userStorer.ForTenant(tenant.ID)
+		sStorer = sStorer.ForTenant(tenant.ID)
+		hhStorer = hhStorer.ForTenant(tenant.ID)
+
 		RunImportStaff(cfg, ppc, lpc, mc, tenantStorer, userStorer, sStorer, hhStorer, tenant)
 	},
 }
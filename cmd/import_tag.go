@@ -43,6 +43,9 @@ var importTagCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		irStorer = irStorer.ForTenant(tenant.ID)
+
 		RunImportTag(cfg, ppc, lpc, irStorer, tenant)
 	},
 }
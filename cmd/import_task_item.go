@@ -15,6 +15,7 @@ import (
 	"github.com/over55/workery-cli/adapter/storage/mongodb"
 	"github.com/over55/workery-cli/adapter/storage/postgres"
 	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	"github.com/over55/workery-cli/app/convert"
 	c_ds "github.com/over55/workery-cli/app/customer/datastore"
 	order_ds "github.com/over55/workery-cli/app/order/datastore"
 	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
@@ -52,6 +53,13 @@ var importTaskItemCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		oStorer = oStorer.ForTenant(tenant.ID)
+		tiStorer = tiStorer.ForTenant(tenant.ID)
+		uStorer = uStorer.ForTenant(tenant.ID)
+		aStorer = aStorer.ForTenant(tenant.ID)
+		cStorer = cStorer.ForTenant(tenant.ID)
+
 		RunImportTaskItem(cfg, ppc, lpc, uStorer, oStorer, tiStorer, aStorer, cStorer, tenant)
 	},
 }
@@ -368,8 +376,8 @@ func importTaskItem(
 		OrderTenantIDWithWJID:                 fmt.Sprintf("%v_%v", order.TenantID.Hex(), order.WJID),
 		OrderStartDate:                        order.StartDate,
 		OrderDescription:                      order.Description,
-		OrderSkillSets:                        toTaskItemSkillSetsFromOrderSkillSets(order.SkillSets),
-		OrderTags:                             toTaskItemTagsFromOrderTags(order.Tags),
+		OrderSkillSets:                        convert.ToTaskItemSkillSetsFromOrderSkillSets(order.SkillSets),
+		OrderTags:                             convert.ToTaskItemTagsFromOrderTags(order.Tags),
 		CreatedAt:                             ti.CreatedAt,
 		CreatedByUserID:                       createdByUserID,
 		CreatedByUserName:                     createdByUserName,
@@ -400,10 +408,10 @@ func importTaskItem(
 		AssociateOtherPhoneExtension:          associateOtherPhoneExtension,
 		AssociateFullAddressWithoutPostalCode: associateFullAddressWithoutPostalCode,
 		AssociateFullAddressURL:               associateFullAddressURL,
-		AssociateTags:                         toTaskItemTagsFromAssociateTags(associateTags),
-		AssociateSkillSets:                    toTaskItemSkillSetsFromAssociateSkillSets(associateSkillSets),
-		AssociateInsuranceRequirements:        toTaskItemInsuranceRequirementsFromAssociateInsuranceRequirements(associateInsuranceRequirements),
-		AssociateVehicleTypes:                 toTaskItemVehicleTypesFromAssociateVehicleTypes(associateVehicleTypes),
+		AssociateTags:                         convert.ToTaskItemTagsFromAssociateTags(associateTags),
+		AssociateSkillSets:                    convert.ToTaskItemSkillSetsFromAssociateSkillSets(associateSkillSets),
+		AssociateInsuranceRequirements:        convert.ToTaskItemInsuranceRequirementsFromAssociateInsuranceRequirements(associateInsuranceRequirements),
+		AssociateVehicleTypes:                 convert.ToTaskItemVehicleTypesFromAssociateVehicleTypes(associateVehicleTypes),
 		AssociateTaxID:                        associateTaxID,
 		CustomerID:                            customerID,
 		CustomerOrganizationName:              customerOrganizationName,
@@ -425,7 +433,7 @@ func importTaskItem(
 		CustomerOtherPhoneExtension:           customerOtherPhoneExtension,
 		CustomerFullAddressWithoutPostalCode:  customerFullAddressWithoutPostalCode,
 		CustomerFullAddressURL:                customerFullAddressURL,
-		CustomerTags:                          toTaskItemTagsFromCustomerTags(customerTags),
+		CustomerTags:                          convert.ToTaskItemTagsFromCustomerTags(customerTags),
 	}
 
 	if err := tiStorer.Create(ctx, m); err != nil {
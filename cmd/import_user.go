@@ -16,8 +16,10 @@ import (
 	"github.com/over55/workery-cli/adapter/storage/mongodb"
 	"github.com/over55/workery-cli/adapter/storage/postgres"
 	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	"github.com/over55/workery-cli/app/user/credentials"
 	user_ds "github.com/over55/workery-cli/app/user/datastore"
 	"github.com/over55/workery-cli/config"
+	"github.com/over55/workery-cli/provider/password"
 )
 
 func init() {
@@ -35,7 +37,8 @@ var importUserCmd = &cobra.Command{
 		lpc := postgres.NewStorage(cfg, cfg.PostgresDB.DatabasePublicSchemaName)
 		defaultLogger := slog.Default()
 		tenantStorer := tenant_ds.NewDatastore(cfg, defaultLogger, mc)
-		userStorer := user_ds.NewDatastore(cfg, defaultLogger, mc)
+		// Users are imported into whichever tenant they belonged to.
+		userStorer := user_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()
 		RunImportUser(cfg, ppc, lpc, tenantStorer, userStorer)
 	},
 }
@@ -43,7 +46,7 @@ var importUserCmd = &cobra.Command{
 type OldUser struct {
 	ID       uint64        `json:"id"`
 	TenantID sql.NullInt64 `json:"franchise_id"`
-	// password character varying(128) COLLATE pg_catalog."default" NOT NULL,
+	Password string        `json:"password"` // Django hash, ex: `pbkdf2_sha256$<iterations>$<salt>$<hash>`.
 	// last_login timestamp with time zone,
 	// is_superuser boolean NOT NULL,
 	Email      string    `json:"email"`
@@ -68,7 +71,7 @@ func ListAllUsers(db *sql.DB) ([]*OldUser, error) {
 
 	query := `
 	SELECT
-	    id, email, first_name, last_name, date_joined, is_active, last_modified, was_email_activated, franchise_id
+	    id, email, first_name, last_name, date_joined, is_active, last_modified, was_email_activated, franchise_id, password
 	FROM
 	    workery_users
 	ORDER BY
@@ -95,6 +98,7 @@ func ListAllUsers(db *sql.DB) ([]*OldUser, error) {
 			&m.LastModified,
 			&m.WasEmailActivated,
 			&m.TenantID,
+			&m.Password,
 		)
 		if err != nil {
 			log.Println("failled querring2 old database")
@@ -196,6 +200,14 @@ func importUser(ctx context.Context, ts tenant_ds.TenantStorer, us user_ds.UserS
 	ou.Email = strings.ToLower(ou.Email)
 	ou.Email = strings.ReplaceAll(ou.Email, " ", "")
 
+	// Users keep their Django hash until they next log in and are rehashed.
+	// Unusable hashes, ex: `!` for accounts without a password, are replaced
+	// with the placeholder.
+	passwordHash := ou.Password
+	if password.Algorithm(passwordHash) == password.AlgorithmUnknown {
+		passwordHash = credentials.PlaceholderPasswordHash
+	}
+
 	m := &user_ds.User{
 		PublicID:              ou.ID,
 		ID:                    primitive.NewObjectID(),
@@ -214,8 +226,8 @@ func importUser(ctx context.Context, ts tenant_ds.TenantStorer, us user_ds.UserS
 		PrAccessCode:          "",
 		PrExpiryTime:          time.Now(),
 		TenantID:              tenant.ID,
-		PasswordHashAlgorithm: primitive.NewObjectID().Hex(),
-		PasswordHash:          "MongoDB Primitive",
+		PasswordHashAlgorithm: password.Algorithm(passwordHash),
+		PasswordHash:          passwordHash,
 	}
 	if err := us.Create(ctx, m); err != nil {
 		log.Panic(err)
@@ -33,7 +33,8 @@ var importUserRoleCmd = &cobra.Command{
 		lpc := postgres.NewStorage(cfg, cfg.PostgresDB.DatabasePublicSchemaName)
 		defaultLogger := slog.Default()
 		tenantStorer := tenant_ds.NewDatastore(cfg, defaultLogger, mc)
-		userStorer := user_ds.NewDatastore(cfg, defaultLogger, mc)
+		// Users are updated in whichever tenant they belong to.
+		userStorer := user_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()
 		RunImportUserRole(cfg, ppc, lpc, tenantStorer, userStorer)
 	},
 }
@@ -43,6 +43,9 @@ var importVehicleTypeCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
+		// Constrain every storer to the tenant being imported into.
+		irStorer = irStorer.ForTenant(tenant.ID)
+
 		RunImportVehicleType(cfg, ppc, lpc, irStorer, tenant)
 	},
 }
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	s3storage "github.com/over55/workery-cli/adapter/storage/s3"
+	inv_c "github.com/over55/workery-cli/app/invoice/controller"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go invoice render 1234
+// $ go run main.go invoice render 1234 --output="./invoice.pdf" --template="./letterhead.pdf"
+// $ go run main.go invoice render 1234 --upload
+
+var (
+	invoiceOutputFilePath   string
+	invoiceTemplateFilePath string
+	invoiceUpload           bool
+)
+
+func init() {
+	invoiceRenderCmd.Flags().StringVarP(&invoiceOutputFilePath, "output", "o", "", "Path to save the PDF to, defaults to the invoice file title in the current directory")
+	invoiceRenderCmd.Flags().StringVarP(&invoiceTemplateFilePath, "template", "t", "", "PDF whose first page is drawn under the invoice, defaults to WORKERY_INVOICEBUILDER_PDF_TEMPLATE_FILE_PATH")
+	invoiceRenderCmd.Flags().BoolVarP(&invoiceUpload, "upload", "u", false, "Upload the PDF to S3 and save its location on the invoice")
+	invoiceRenderCmd.Annotations = requirePermission("invoice:export")
+	invoiceCmd.AddCommand(invoiceRenderCmd)
+
+	invoiceCmd.Annotations = requirePermission("invoice:read")
+	rootCmd.AddCommand(invoiceCmd)
+}
+
+var invoiceCmd = &cobra.Command{
+	Use:   "invoice",
+	Short: "Manage order invoices",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var invoiceRenderCmd = &cobra.Command{
+	Use:   "render <wjid>",
+	Short: "Generate the PDF of the invoice of an order",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		if invoiceTemplateFilePath != "" {
+			cfg.InvoiceBuilder.PDFTemplateFilePath = invoiceTemplateFilePath
+		}
+		mc := mongodb.NewStorage(cfg)
+
+		// Only connect to S3 when it is needed.
+		var s3 s3storage.S3Storager
+		if invoiceUpload {
+			s3 = s3storage.NewStorage(cfg, slog.Default())
+		}
+
+		RunInvoiceRender(newInvoiceController(cfg, mc, s3), args[0])
+	},
+}
+
+func newInvoiceController(cfg *config.Conf, mc *mongo.Client, s3 s3storage.S3Storager) inv_c.InvoiceController {
+	defaultLogger := slog.Default()
+	tenantStorer := tenant_ds.NewDatastore(cfg, defaultLogger, mc)
+	tenant, err := tenantStorer.GetBySchemaName(context.Background(), cfg.PostgresDB.DatabaseLondonSchemaName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if tenant == nil {
+		log.Fatal("tenant does not exist")
+	}
+	return inv_c.NewController(
+		cfg,
+		defaultLogger,
+		s3,
+		tenantStorer,
+		o_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+	)
+}
+
+func RunInvoiceRender(ctrl inv_c.InvoiceController, wjidStr string) {
+	wjid, err := strconv.ParseUint(wjidStr, 10, 64)
+	if err != nil {
+		log.Fatal("invalid wjid:", err)
+	}
+
+	res, err := ctrl.Render(context.Background(), wjid, invoiceUpload)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	filePath := invoiceOutputFilePath
+	if filePath == "" {
+		filePath = res.FileTitle
+	}
+	if err := os.WriteFile(filePath, res.Content, 0644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Saved invoice to", filePath)
+	if res.ObjectKey != "" {
+		fmt.Println("Uploaded invoice to", res.ObjectKey)
+	}
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	contact_c "github.com/over55/workery-cli/app/contact/controller"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	s_ds "github.com/over55/workery-cli/app/staff/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go normalize --dry-run --format=csv --output=diff.csv
+// $ go run main.go normalize --only=customer,associate
+
+var (
+	normalizeOnly   string
+	normalizeDryRun bool
+	normalizeFormat string
+	normalizeOutput string
+)
+
+func init() {
+	normalizeCmd.Flags().StringVarP(&normalizeOnly, "only", "k", strings.Join(contact_c.Kinds, ","), "Comma separated kinds of records to normalize")
+	normalizeCmd.Flags().BoolVarP(&normalizeDryRun, "dry-run", "d", false, "Report what would change without saving the changes")
+	normalizeCmd.Flags().StringVarP(&normalizeFormat, "format", "m", "table", "Report format, either table, csv or json")
+	normalizeCmd.Flags().StringVarP(&normalizeOutput, "output", "o", "", "File to write the report to, defaults to stdout")
+	rootCmd.AddCommand(normalizeCmd)
+}
+
+var normalizeCmd = &cobra.Command{
+	Use:   "normalize",
+	Short: "Normalize the phone numbers and emails of every customer, associate, staff and user",
+	Long: `Converts the phone numbers to E.164 (ex: +15195551234), moves their
+extensions into the extension fields and lower cases and trims the emails.
+Invalid phone numbers and emails, role emails (ex: info@) and placeholder
+emails are flagged and left as they are. The report lists every field
+before and after along with the flagged ones. The copies of the changed
+customers and associates held by orders and task items are refreshed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		kinds := []string{}
+		for _, kind := range strings.Split(normalizeOnly, ",") {
+			kind = strings.TrimSpace(kind)
+			if !isContactKind(kind) {
+				log.Fatalf("unsupported kind: %v, must be one of %v", kind, strings.Join(contact_c.Kinds, ", "))
+			}
+			kinds = append(kinds, kind)
+		}
+
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		tenant := getOrderTenant(cfg, mc)
+		defaultLogger := slog.Default()
+		ctrl := contact_c.NewController(
+			cfg,
+			defaultLogger,
+			c_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			a_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			s_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			user_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			newResyncController(cfg, mc, tenant),
+		)
+		report, err := ctrl.Normalize(context.Background(), kinds, normalizeDryRun)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := writeNormalizeReport(report); err != nil {
+			log.Fatal(err)
+		}
+
+		verb := "updated"
+		if report.DryRun {
+			verb = "would be updated"
+		}
+		fmt.Fprintf(os.Stderr, "%v record(s) checked, %v %v\n", report.Checked, report.Updated, verb)
+		if !report.DryRun {
+			fmt.Fprintf(os.Stderr, "%v copied record(s) refreshed\n", report.RefreshedCopies)
+		}
+	},
+}
+
+func isContactKind(kind string) bool {
+	for _, k := range contact_c.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func writeNormalizeReport(report *contact_c.NormalizeReport) error {
+	w := io.Writer(os.Stdout)
+	if normalizeOutput != "" {
+		f, err := os.Create(normalizeOutput)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch normalizeFormat {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "KIND\tID\tNAME\tFIELD\tBEFORE\tAFTER\tFLAG\t")
+		for _, ch := range report.Changes {
+			fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t\n", ch.Kind, ch.PublicID, ch.Name, ch.Field, ch.Before, ch.After, ch.Flag)
+		}
+		return tw.Flush()
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"kind", "id", "public_id", "name", "field", "before", "after", "flag"})
+		for _, ch := range report.Changes {
+			cw.Write([]string{
+				ch.Kind,
+				ch.ID.Hex(),
+				fmt.Sprint(ch.PublicID),
+				ch.Name,
+				ch.Field,
+				ch.Before,
+				ch.After,
+				ch.Flag,
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return fmt.Errorf("unsupported format: %v", normalizeFormat)
+}
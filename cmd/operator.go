@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	"github.com/over55/workery-cli/app/permission"
+	user_c "github.com/over55/workery-cli/app/user/controller"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	"github.com/over55/workery-cli/config"
+	"github.com/over55/workery-cli/provider/password"
+)
+
+// permissionAnnotation is the command annotation holding the
+// `<resource>:<action>` permission the operator needs to run it. Commands
+// without one may only be run by operators who may do anything on the
+// system, such as the imports and hotfixes.
+const permissionAnnotation = "permission"
+
+// exitForbidden is the exit code when the operator may not run the command.
+const exitForbidden = 6
+
+var operatorRef string
+
+// operator and operatorPolicy are set once the operator is authorized, they
+// stay nil when no operator was supplied.
+var (
+	operator       *user_ds.User
+	operatorPolicy *permission.Policy
+)
+
+func requirePermission(p string) map[string]string {
+	return map[string]string{permissionAnnotation: p}
+}
+
+// authorizeOperator exits unless the operator may run the command, and
+// attributes the changes the command makes to them. Nothing is checked when
+// no operator was supplied.
+func authorizeOperator(cmd *cobra.Command) {
+	if operatorRef == "" {
+		return
+	}
+	resource, action := permission.ResourceSystem, permission.Any
+	if p, ok := cmd.Annotations[permissionAnnotation]; ok {
+		r, a, err := permission.Parse(p)
+		if err != nil {
+			exitOperator(err)
+		}
+		resource, action = r, a
+	}
+
+	cfg := config.New()
+	mc := mongodb.NewStorage(cfg)
+	defaultLogger := slog.Default()
+	policy, err := permission.NewPolicy(cfg)
+	if err != nil {
+		exitOperator(err)
+	}
+
+	// The operator is looked up regardless of which tenant they belong to,
+	// the tenant overrides of their own tenant apply.
+	ctrl := user_c.NewController(cfg, defaultLogger, password.NewProvider(), user_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants())
+	u, err := ctrl.Get(context.Background(), operatorRef)
+	if err != nil {
+		exitOperator(fmt.Errorf("operator %v: %w", operatorRef, err))
+	}
+	if err := policy.Check(u, action, resource); err != nil {
+		exitOperator(err)
+	}
+	operator, operatorPolicy = u, policy
+
+	auditlog_ds.SetDefaultActor(u.Email, cmd.CommandPath())
+}
+
+// authorizeOperatorRole exits unless the operator may manage users with the
+// role, see `permission.Policy.CheckRole`. Nothing is checked when no
+// operator was supplied.
+func authorizeOperatorRole(role int8) {
+	if operator == nil {
+		return
+	}
+	if err := operatorPolicy.CheckRole(operator, role); err != nil {
+		exitOperator(err)
+	}
+}
+
+func exitOperator(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	if errors.Is(err, permission.ErrForbidden) {
+		os.Exit(exitForbidden)
+	}
+	os.Exit(1)
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	o_c "github.com/over55/workery-cli/app/order/controller"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	"github.com/over55/workery-cli/app/order/lifecycle"
+	ss_ds "github.com/over55/workery-cli/app/skillset/datastore"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go order transition 1234 in_progress
+// $ go run main.go order transition 1234 cancelled --closing-reason=5 --closing-reason-comment="Client moved away"
+// $ go run main.go order audit
+// $ go run main.go order match 1234
+// $ go run main.go order match 1234 --vehicle-type=Truck --limit=5
+
+var (
+	orderClosingReason        int8
+	orderClosingReasonOther   string
+	orderClosingReasonComment string
+	orderMatchVehicleTypes    []string
+	orderMatchLimit           int
+)
+
+func init() {
+	orderTransitionCmd.Flags().Int8VarP(&orderClosingReason, "closing-reason", "r", 0, "Reason the order is declined or cancelled, see: order closing-reasons")
+	orderTransitionCmd.Flags().StringVarP(&orderClosingReasonOther, "closing-reason-other", "o", "", "Explanation when the closing reason is Other")
+	orderTransitionCmd.Flags().StringVarP(&orderClosingReasonComment, "closing-reason-comment", "c", "", "Comment about why the order was closed")
+	orderTransitionCmd.Annotations = requirePermission("order:update")
+	orderCmd.AddCommand(orderTransitionCmd)
+	orderClosingReasonsCmd.Annotations = requirePermission("order:read")
+	orderCmd.AddCommand(orderClosingReasonsCmd)
+	orderAuditCmd.Annotations = requirePermission("order:read")
+	orderCmd.AddCommand(orderAuditCmd)
+
+	orderMatchCmd.Flags().StringSliceVarP(&orderMatchVehicleTypes, "vehicle-type", "v", nil, "ID or name of a vehicle type the job requires, may be repeated")
+	orderMatchCmd.Flags().IntVarP(&orderMatchLimit, "limit", "l", 10, "Maximum number of associates to list, zero lists all of them")
+	orderMatchCmd.Annotations = requirePermission("order:read")
+	orderCmd.AddCommand(orderMatchCmd)
+
+	orderCmd.Annotations = requirePermission("order:read")
+	rootCmd.AddCommand(orderCmd)
+}
+
+var orderCmd = &cobra.Command{
+	Use:   "order",
+	Short: "Manage orders",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var orderTransitionCmd = &cobra.Command{
+	Use:   "transition <wjid> <status>",
+	Short: "Move an order to another status",
+	Long: `Moves an order to another status if the order lifecycle allows it:
+
+  New -> Pending -> In Progress (or Ongoing) -> Completed but Unpaid -> Completed and Paid -> Archived
+
+Orders may be Declined from New or Pending and Cancelled at any point before
+they are completed, both of which require a closing reason. The status may be
+given as its number or its label, ex: 6, in_progress or "In Progress".`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		RunOrderTransition(newOrderController(cfg, mc, getOrderTenant(cfg, mc)), args[0], args[1])
+	},
+}
+
+var orderClosingReasonsCmd = &cobra.Command{
+	Use:   "closing-reasons",
+	Short: "List the reasons an order can be declined or cancelled with",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		for reason := int8(1); int(reason) <= len(o_ds.OrderClosingReasonLabels); reason++ {
+			fmt.Printf("%v\t%v\n", reason, o_ds.OrderClosingReasonLabels[reason])
+		}
+	},
+}
+
+var orderAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Report every order which is in a state the order lifecycle does not allow",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		tenant := getOrderTenant(cfg, mc)
+
+		RunOrderAudit(newOrderController(cfg, mc, tenant), tenant)
+	},
+}
+
+var orderMatchCmd = &cobra.Command{
+	Use:   "match <wjid>",
+	Short: "Rank the associates who can take an order",
+	Long: `Lists the active associates who have every skill set of the order, the
+insurance linked to those skill sets, the required vehicle types and who are
+not away, ranked by their score, the number of open orders they have and how
+close they are to the customer.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		RunOrderMatch(newOrderController(cfg, mc, getOrderTenant(cfg, mc)), args[0])
+	},
+}
+
+func getOrderTenant(cfg *config.Conf, mc *mongo.Client) *tenant_ds.Tenant {
+	tenantStorer := tenant_ds.NewDatastore(cfg, slog.Default(), mc)
+	tenant, err := tenantStorer.GetBySchemaName(context.Background(), cfg.PostgresDB.DatabaseLondonSchemaName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if tenant == nil {
+		log.Fatal("tenant does not exist")
+	}
+	return tenant
+}
+
+func newOrderController(cfg *config.Conf, mc *mongo.Client, tenant *tenant_ds.Tenant) o_c.OrderController {
+	defaultLogger := slog.Default()
+	return o_c.NewController(
+		cfg,
+		defaultLogger,
+		o_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		a_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		aal_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		c_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		ss_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		ti_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+	)
+}
+
+func RunOrderTransition(ctrl o_c.OrderController, wjidStr string, statusStr string) {
+	wjid, err := strconv.ParseUint(wjidStr, 10, 64)
+	if err != nil {
+		log.Fatal("invalid wjid:", err)
+	}
+	status, err := lifecycle.ParseStatus(statusStr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	closure := &lifecycle.Closure{
+		Reason:        orderClosingReason,
+		ReasonOther:   orderClosingReasonOther,
+		ReasonComment: orderClosingReasonComment,
+	}
+	o, err := ctrl.Transition(context.Background(), wjid, status, closure)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("order %v is now %v\n", o.WJID, o_ds.OrderStatusLabels[o.Status])
+	if !o.LatestPendingTaskID.IsZero() {
+		fmt.Printf("next task: %v, due %v\n", o.LatestPendingTaskTitle, o.LatestPendingTaskDueDate.Format("2006-01-02"))
+	}
+}
+
+func RunOrderAudit(ctrl o_c.OrderController, tenant *tenant_ds.Tenant) {
+	violations, err := ctrl.AuditStatuses(context.Background(), tenant.ID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, v := range violations {
+		label, ok := o_ds.OrderStatusLabels[v.Status]
+		if !ok {
+			label = fmt.Sprint(v.Status)
+		}
+		fmt.Printf("%v\t%v\t%v\n", v.WJID, label, strings.Join(v.Problems, ", "))
+	}
+	fmt.Printf("%v order(s) in a state the order lifecycle does not allow\n", len(violations))
+}
+
+func RunOrderMatch(ctrl o_c.OrderController, wjidStr string) {
+	wjid, err := strconv.ParseUint(wjidStr, 10, 64)
+	if err != nil {
+		log.Fatal("invalid wjid:", err)
+	}
+
+	o, candidates, err := ctrl.Match(context.Background(), wjid, orderMatchVehicleTypes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	total := len(candidates)
+	if orderMatchLimit > 0 && total > orderMatchLimit {
+		candidates = candidates[:orderMatchLimit]
+	}
+	for i, c := range candidates {
+		distance := "-"
+		if c.DistanceKm >= 0 {
+			distance = fmt.Sprintf("%.1f km", c.DistanceKm)
+		}
+		fmt.Printf("%v\t%.3f\t%v\t%v\tscore %v\t%v open order(s)\t%v\n", i+1, c.Rank, c.AssociateID.Hex(), c.AssociateName, c.Score, c.OpenOrders, distance)
+	}
+	fmt.Printf("%v associate(s) can take order %v\n", total, o.WJID)
+}
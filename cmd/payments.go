@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	o_c "github.com/over55/workery-cli/app/order/controller"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	"github.com/over55/workery-cli/app/order/ledger"
+	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go payments record 1234 --amount=150 --method=cash --paid-to=associate
+// $ go run main.go payments record 1234 --amount=99.95 --method=e-transfer --paid-to=organization --paid-at=2024-03-01
+// $ go run main.go payments audit
+// $ go run main.go payments audit --fix
+
+var (
+	paymentAmount float64
+	paymentMethod string
+	paymentPaidTo string
+	paymentPaidAt string
+	paymentOver   bool
+	paymentFix    bool
+)
+
+func init() {
+	paymentsRecordCmd.Flags().Float64VarP(&paymentAmount, "amount", "a", 0, "Amount which was paid")
+	paymentsRecordCmd.MarkFlagRequired("amount")
+	paymentsRecordCmd.Flags().StringVarP(&paymentMethod, "method", "m", "", "How it was paid, ex: cash, cheque, e-transfer, debit, credit")
+	paymentsRecordCmd.MarkFlagRequired("method")
+	paymentsRecordCmd.Flags().StringVarP(&paymentPaidTo, "paid-to", "t", "", "Who was paid, either associate or organization")
+	paymentsRecordCmd.MarkFlagRequired("paid-to")
+	paymentsRecordCmd.Flags().StringVarP(&paymentPaidAt, "paid-at", "d", "", "Date it was paid in YYYY-MM-DD format, defaults to now")
+	paymentsRecordCmd.Flags().BoolVar(&paymentOver, "allow-overpayment", false, "Record the payment even if it is more than the amount due or the order has no invoice total yet")
+	paymentsRecordCmd.Annotations = requirePermission("invoice:update")
+	paymentsCmd.AddCommand(paymentsRecordCmd)
+
+	paymentsAuditCmd.Flags().BoolVarP(&paymentFix, "fix", "f", false, "Recalculate the stored amounts which do not match")
+	paymentsAuditCmd.Annotations = requirePermission("invoice:read")
+	paymentsCmd.AddCommand(paymentsAuditCmd)
+
+	paymentsCmd.Annotations = requirePermission("invoice:read")
+	rootCmd.AddCommand(paymentsCmd)
+}
+
+var paymentsCmd = &cobra.Command{
+	Use:   "payments",
+	Short: "Manage the deposits and payments of orders",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var paymentsRecordCmd = &cobra.Command{
+	Use:   "record <wjid>",
+	Short: "Record a deposit on an order and recalculate its amount due",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		RunPaymentsRecord(newOrderController(cfg, mc, getOrderTenant(cfg, mc)), args[0])
+	},
+}
+
+var paymentsAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Report every order whose deposit, amount due or balance owing does not match its deposits",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		tenant := getOrderTenant(cfg, mc)
+
+		RunPaymentsAudit(newOrderController(cfg, mc, tenant), tenant)
+	},
+}
+
+func RunPaymentsRecord(ctrl o_c.OrderController, wjidStr string) {
+	wjid, err := strconv.ParseUint(wjidStr, 10, 64)
+	if err != nil {
+		log.Fatal("invalid wjid:", err)
+	}
+	method, err := ledger.ParsePaymentMethod(paymentMethod)
+	if err != nil {
+		log.Fatal(err)
+	}
+	paidTo, err := ledger.ParsePaidTo(paymentPaidTo)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var paidAt time.Time
+	if paymentPaidAt != "" {
+		paidAt, err = time.Parse("2006-01-02", paymentPaidAt)
+		if err != nil {
+			log.Fatal("invalid paid at:", err)
+		}
+	}
+
+	o, b, err := ctrl.RecordPayment(context.Background(), wjid, &o_ds.OrderDeposit{
+		Amount:        paymentAmount,
+		DepositMethod: method,
+		PaidTo:        paidTo,
+		PaidAt:        paidAt,
+	}, paymentOver)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("order %v: %.2f deposited, %.2f due, %.2f service fee owing\n", o.WJID, b.DepositsPaid, b.AmountDue, b.BalanceOwing)
+}
+
+func RunPaymentsAudit(ctrl o_c.OrderController, tenant *tenant_ds.Tenant) {
+	divergences, err := ctrl.AuditLedger(context.Background(), tenant.ID, !paymentFix)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, d := range divergences {
+		fmt.Printf("%v\t%v\n", d.WJID, strings.Join(d.Problems, ", "))
+	}
+	if !paymentFix {
+		fmt.Printf("%v order(s) with amounts which do not match their deposits, no changes were saved\n", len(divergences))
+		return
+	}
+	fmt.Printf("%v order(s) recalculated\n", len(divergences))
+}
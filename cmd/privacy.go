@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	s3storage "github.com/over55/workery-cli/adapter/storage/s3"
+	as_ds "github.com/over55/workery-cli/app/activitysheet/datastore"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+	att_ds "github.com/over55/workery-cli/app/attachment/datastore"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	com_ds "github.com/over55/workery-cli/app/comment/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	"github.com/over55/workery-cli/app/privacy"
+	privacy_c "github.com/over55/workery-cli/app/privacy/controller"
+	s_ds "github.com/over55/workery-cli/app/staff/datastore"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go privacy erase customer 1234 --dry-run
+// $ go run main.go privacy erase customer 1234 --yes --output=receipt.json
+// $ go run main.go privacy verify receipt.json
+
+var (
+	privacyEraseDryRun bool
+	privacyEraseYes    bool
+	privacyEraseOutput string
+)
+
+func init() {
+	privacyEraseCmd.Flags().BoolVarP(&privacyEraseDryRun, "dry-run", "d", false, "Report what would be erased without changing anything")
+	privacyEraseCmd.Flags().BoolVarP(&privacyEraseYes, "yes", "y", false, "Confirm the erasure, which cannot be undone")
+	privacyEraseCmd.Flags().StringVarP(&privacyEraseOutput, "output", "o", "", "File to write the receipt to, defaults to stdout")
+	privacyCmd.AddCommand(privacyEraseCmd)
+
+	privacyVerifyCmd.Annotations = requirePermission("system:read")
+	privacyCmd.AddCommand(privacyVerifyCmd)
+
+	rootCmd.AddCommand(privacyCmd)
+}
+
+var privacyCmd = &cobra.Command{
+	Use:   "privacy",
+	Short: "Erase a person on request and verify erasure receipts",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var privacyEraseCmd = &cobra.Command{
+	Use:       "erase [customer|associate|staff] [id]",
+	Short:     "Replace the personal information of a person with tombstones everywhere it was copied",
+	ValidArgs: privacy_c.Kinds,
+	Args:      cobra.ExactArgs(2),
+	Long: `Replaces the names, emails, phones, addresses, birthdate, gender and
+emergency contacts of the person and of their login account with tombstones,
+refreshes the copies held by orders, task items, activity sheets and comments,
+tombstones their details on invoices, deletes their attachments and avatar
+from the bucket and archives them. Unlike deleting the customer, which also
+deletes their orders, the orders and invoice amounts are kept for accounting.
+Documents in the trash are erased and counted the same as the others. The
+audit log values of every changed document are redacted, and all of those of
+the person and their login account, so the command can be run again to finish
+an erasure which failed part way.
+
+A receipt listing what was erased, deleted and kept is written signed with
+WORKERY_PRIVACY_RECEIPT_SIGNING_KEY. The person may be given by ID or public
+ID. Run with --dry-run first, the erasure itself needs --yes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		kind, ref := args[0], args[1]
+		if !isPrivacyKind(kind) {
+			log.Fatalf("unsupported kind: %v, must be one of %v", kind, strings.Join(privacy_c.Kinds, ", "))
+		}
+		if !privacyEraseDryRun && !privacyEraseYes {
+			log.Fatal("erasure cannot be undone, run with --dry-run to review it and then with --yes")
+		}
+
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		tenant := getOrderTenant(cfg, mc)
+		defaultLogger := slog.Default()
+		ctrl := privacy_c.NewController(
+			cfg,
+			defaultLogger,
+			s3storage.NewStorage(cfg, defaultLogger),
+			c_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			a_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			s_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			user_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			o_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			ti_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			as_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			com_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			aal_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			att_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+			auditlog_ds.NewDatastore(cfg, defaultLogger, mc),
+			newResyncController(cfg, mc, tenant),
+		)
+		receipt, err := ctrl.Erase(context.Background(), kind, ref, privacyEraseDryRun)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := writeReceipt(receipt); err != nil {
+			log.Fatal(err)
+		}
+
+		verb := "erased"
+		if receipt.DryRun {
+			verb = "would be erased"
+		}
+		collections := make([]string, 0, len(receipt.Documents))
+		for collectionName := range receipt.Documents {
+			collections = append(collections, collectionName)
+		}
+		sort.Strings(collections)
+		for _, collectionName := range collections {
+			fmt.Fprintf(os.Stderr, "%v: %v document(s) %v\n", collectionName, receipt.Documents[collectionName], verb)
+		}
+		fmt.Fprintf(os.Stderr, "%v file(s) %v from the bucket\n", receipt.DeletedObjects, verb)
+		if !receipt.DryRun {
+			fmt.Fprintf(os.Stderr, "%v audit log entries redacted\n", receipt.RedactedAuditLogs)
+			fmt.Fprintf(os.Stderr, "receipt %v signed\n", receipt.ID)
+		}
+	},
+}
+
+var privacyVerifyCmd = &cobra.Command{
+	Use:   "verify [receipt.json]",
+	Short: "Verify an erasure receipt has not been altered since it was signed",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		b, err := os.ReadFile(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		receipt := &privacy.Receipt{}
+		if err := json.Unmarshal(b, receipt); err != nil {
+			log.Fatal(err)
+		}
+		cfg := config.New()
+		if err := privacy.Verify(receipt, cfg.Privacy.ReceiptSigningKey); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("receipt %v is valid, %v %v was erased at %v by %v\n",
+			receipt.ID, receipt.Kind, receipt.SubjectPublicID, receipt.ErasedAt.Format("2006-01-02 15:04:05 MST"), receipt.ErasedBy)
+	},
+}
+
+func isPrivacyKind(kind string) bool {
+	for _, k := range privacy_c.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func writeReceipt(receipt *privacy.Receipt) error {
+	w := io.Writer(os.Stdout)
+	if privacyEraseOutput != "" {
+		f, err := os.Create(privacyEraseOutput)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(receipt)
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	as_ds "github.com/over55/workery-cli/app/activitysheet/datastore"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	com_ds "github.com/over55/workery-cli/app/comment/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	resync_c "github.com/over55/workery-cli/app/resync/controller"
+	rt_ds "github.com/over55/workery-cli/app/resync/datastore"
+	sf_ds "github.com/over55/workery-cli/app/servicefee/datastore"
+	ss_ds "github.com/over55/workery-cli/app/skillset/datastore"
+	tag_ds "github.com/over55/workery-cli/app/tag/datastore"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go resync associate --id="64d0f8c2a1b2c3d4e5f60718" --dry-run
+// $ go run main.go resync servicefee --id="64d0f8c2a1b2c3d4e5f60718"
+// $ go run main.go resync audit
+// $ go run main.go resync audit --fix
+
+var (
+	resyncDocumentID string
+	resyncDryRun     bool
+	resyncFix        bool
+)
+
+func init() {
+	for _, c := range []*cobra.Command{resyncCustomerCmd, resyncAssociateCmd, resyncServiceFeeCmd, resyncSkillSetCmd, resyncTagCmd} {
+		c.Flags().StringVarP(&resyncDocumentID, "id", "i", "", "ID of the record whose copies should be refreshed")
+		c.MarkFlagRequired("id")
+		c.Flags().BoolVarP(&resyncDryRun, "dry-run", "d", false, "Report the drift without saving any changes")
+		resyncCmd.AddCommand(c)
+	}
+
+	resyncAuditCmd.Flags().BoolVarP(&resyncFix, "fix", "f", false, "Fix the drift which was found")
+	resyncCmd.AddCommand(resyncAuditCmd)
+
+	rootCmd.AddCommand(resyncCmd)
+}
+
+var resyncCmd = &cobra.Command{
+	Use:   "resync",
+	Short: "Refresh the customer, associate, service fee, skill set and tag copies embedded in other documents",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var resyncCustomerCmd = &cobra.Command{
+	Use:   "customer",
+	Short: "Refresh the copies of a customer in their orders, task items and comments",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		RunResync(newResyncController(cfg, mc, getResyncTenant(cfg, mc)), "customer")
+	},
+}
+
+var resyncAssociateCmd = &cobra.Command{
+	Use:   "associate",
+	Short: "Refresh the copies of an associate in their orders, task items, activity sheets and comments",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		RunResync(newResyncController(cfg, mc, getResyncTenant(cfg, mc)), "associate")
+	},
+}
+
+var resyncServiceFeeCmd = &cobra.Command{
+	Use:   "servicefee",
+	Short: "Refresh the copies of a service fee in associates, orders and task items",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		RunResync(newResyncController(cfg, mc, getResyncTenant(cfg, mc)), "servicefee")
+	},
+}
+
+var resyncSkillSetCmd = &cobra.Command{
+	Use:   "skillset",
+	Short: "Refresh the copies of a skill set in associates, orders and task items",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		RunResync(newResyncController(cfg, mc, getResyncTenant(cfg, mc)), "skillset")
+	},
+}
+
+var resyncTagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Refresh the copies of a tag in customers, associates, orders and task items",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		RunResync(newResyncController(cfg, mc, getResyncTenant(cfg, mc)), "tag")
+	},
+}
+
+var resyncAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Report every denormalized copy which has drifted from its source",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		tenant := getResyncTenant(cfg, mc)
+
+		RunResyncAudit(newResyncController(cfg, mc, tenant), tenant)
+	},
+}
+
+func getResyncTenant(cfg *config.Conf, mc *mongo.Client) *tenant_ds.Tenant {
+	tenantStorer := tenant_ds.NewDatastore(cfg, slog.Default(), mc)
+	tenant, err := tenantStorer.GetBySchemaName(context.Background(), cfg.PostgresDB.DatabaseLondonSchemaName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if tenant == nil {
+		log.Fatal("tenant does not exist")
+	}
+	return tenant
+}
+
+// newResyncController returns a controller whose storers are constrained to
+// the tenant.
+func newResyncController(cfg *config.Conf, mc *mongo.Client, tenant *tenant_ds.Tenant) resync_c.ResyncController {
+	defaultLogger := slog.Default()
+	return resync_c.NewController(
+		cfg,
+		defaultLogger,
+		mc,
+		c_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		a_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		o_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		ti_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		as_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		com_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		sf_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		ss_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		tag_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		rt_ds.NewDatastore(cfg, defaultLogger, mc),
+	)
+}
+
+// newCrossTenantResyncController returns a controller whose storers operate
+// across every tenant.
+func newCrossTenantResyncController(cfg *config.Conf, mc *mongo.Client) resync_c.ResyncController {
+	defaultLogger := slog.Default()
+	return resync_c.NewController(
+		cfg,
+		defaultLogger,
+		mc,
+		c_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants(),
+		a_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants(),
+		o_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants(),
+		ti_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants(),
+		as_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants(),
+		com_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants(),
+		sf_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants(),
+		ss_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants(),
+		tag_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants(),
+		rt_ds.NewDatastore(cfg, defaultLogger, mc),
+	)
+}
+
+func RunResync(ctrl resync_c.ResyncController, kind string) {
+	ctx := context.Background()
+
+	id, err := primitive.ObjectIDFromHex(resyncDocumentID)
+	if err != nil {
+		log.Fatal("invalid id:", err)
+	}
+
+	var report *resync_c.ResyncReport
+	switch kind {
+	case "customer":
+		report, err = ctrl.ResyncCustomer(ctx, id, resyncDryRun)
+	case "associate":
+		report, err = ctrl.ResyncAssociate(ctx, id, resyncDryRun)
+	case "servicefee":
+		report, err = ctrl.ResyncServiceFee(ctx, id, resyncDryRun)
+	case "skillset":
+		report, err = ctrl.ResyncSkillSet(ctx, id, resyncDryRun)
+	case "tag":
+		report, err = ctrl.ResyncTag(ctx, id, resyncDryRun)
+	default:
+		log.Fatalf("unsupported record type: %v\n", kind)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	printResyncReport(report)
+}
+
+func RunResyncAudit(ctrl resync_c.ResyncController, tenant *tenant_ds.Tenant) {
+	report, err := ctrl.Audit(context.Background(), tenant.ID, !resyncFix)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printResyncReport(report)
+}
+
+func printResyncReport(report *resync_c.ResyncReport) {
+	for _, d := range report.Drifts {
+		fmt.Printf("%s\t%s\tfrom %s %s\t%s\n",
+			d.CollectionName,
+			d.DocumentID.Hex(),
+			d.SourceCollectionName,
+			d.SourceID.Hex(),
+			strings.Join(d.Fields, ", "))
+	}
+	if report.DryRun {
+		fmt.Printf("%v document(s) out of sync, no changes were saved\n", len(report.Drifts))
+		return
+	}
+	fmt.Printf("%v document(s) resynced\n", len(report.Drifts))
+}
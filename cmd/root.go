@@ -3,10 +3,13 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"os/user"
 
 	// homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	// "github.com/spf13/viper"
+
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
 )
 
 var (
@@ -19,13 +22,13 @@ var (
 // applicationIP                     string
 // applicationPort                   string
 // applicationSigningKey             string
-// invoicebuilderPdfTemplateFilePath string
-// invoicebuilderData                string
 // hasAutoMigrations                 bool
 )
 
 // Initialize function will be called when every command gets called.
 func init() {
+	rootCmd.PersistentFlags().StringVar(&operatorRef, "operator", os.Getenv("WORKERY_OPERATOR"), "ID, public ID or email of the user running the command, their permissions are checked")
+
 	// // Get our environment variables which will used to configure our application and save across all the sub-commands.
 	// rootCmd.PersistentFlags().StringVar(&databaseHost, "dbHost", os.Getenv("WORKERY_DB_HOST"), "The address of database.")
 	// rootCmd.PersistentFlags().StringVar(&databasePort, "dbPort", os.Getenv("WORKERY_DB_PORT"), "The port of database.")
@@ -36,8 +39,6 @@ func init() {
 	// rootCmd.PersistentFlags().StringVar(&applicationIP, "applicationIP", os.Getenv("WORKERY_APP_IP"), "The ip address to bind this server to.")
 	// rootCmd.PersistentFlags().StringVar(&applicationPort, "applicationPort", os.Getenv("WORKERY_APP_PORT"), "The port to bind this server to.")
 	// rootCmd.PersistentFlags().StringVar(&applicationSigningKey, "appSignKey", os.Getenv("WORKERY_APP_SIGNING_KEY"), "The signing key.")
-	// rootCmd.PersistentFlags().StringVar(&invoicebuilderPdfTemplateFilePath, "invoicebuilderPdfTemplateFilePath", os.Getenv("WORKERY_INVOICEBUILDER_PDF_TEMPLATE_FILE_PATH"), "The invoice builder pdf file location")
-	// rootCmd.PersistentFlags().StringVar(&invoicebuilderData, "invoicebuilderData", os.Getenv("WORKERY_INVOICEBUILDER_DATA"), "The invoice builder save location")
 	//
 	// // Set the auto-migration code.
 	// var b bool = false
@@ -52,6 +53,17 @@ var rootCmd = &cobra.Command{
 	Use:   "workery-cli",
 	Short: "",
 	Long:  ``,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Attribute any document changes made by this process to the
+		// operating system user and the command being executed.
+		var userName string
+		if u, err := user.Current(); err == nil {
+			userName = u.Username
+		}
+		auditlog_ds.SetDefaultActor(userName, cmd.CommandPath())
+
+		authorizeOperator(cmd)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Do nothing.
 	},
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	sf_ds "github.com/over55/workery-cli/app/servicefee/datastore"
+	st_b "github.com/over55/workery-cli/app/statement/builder"
+	st_c "github.com/over55/workery-cli/app/statement/controller"
+	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go statement generate --from=2024-03-01 --to=2024-03-31
+// $ go run main.go statement generate --associate=65f1c2a9e4b0a1b2c3d4e5f6 --format=pdf --output=statement.pdf
+// $ go run main.go statement generate --format=json --dry-run
+
+var (
+	statementAssociateID string
+	statementFrom        string
+	statementTo          string
+	statementFormat      string
+	statementOutput      string
+	statementDryRun      bool
+)
+
+func init() {
+	statementGenerateCmd.Flags().StringVarP(&statementAssociateID, "associate", "a", "", "ID of the associate, defaults to every associate")
+	statementGenerateCmd.Flags().StringVarP(&statementFrom, "from", "f", "", "First day of the period in YYYY-MM-DD format, defaults to the first day of last month")
+	statementGenerateCmd.Flags().StringVarP(&statementTo, "to", "t", "", "Last day of the period in YYYY-MM-DD format, defaults to the last day of last month")
+	statementGenerateCmd.Flags().StringVarP(&statementFormat, "format", "m", st_b.FormatCSV, "Output format, either csv, json or pdf")
+	statementGenerateCmd.Flags().StringVarP(&statementOutput, "output", "o", "", "File to write the statements to, defaults to stdout")
+	statementGenerateCmd.Flags().BoolVarP(&statementDryRun, "dry-run", "d", false, "Compute the statements without saving the service fees and balances")
+	statementGenerateCmd.Annotations = requirePermission("report:export")
+	statementCmd.AddCommand(statementGenerateCmd)
+
+	statementCmd.Annotations = requirePermission("report:read")
+	rootCmd.AddCommand(statementCmd)
+}
+
+var statementCmd = &cobra.Command{
+	Use:   "statement",
+	Short: "Compute service fees and produce associate statements",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var statementGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Compute the service fees of completed orders and output the statements of a period",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+
+		RunStatementGenerate(newStatementController(cfg, mc, getOrderTenant(cfg, mc)))
+	},
+}
+
+func newStatementController(cfg *config.Conf, mc *mongo.Client, tenant *tenant_ds.Tenant) st_c.StatementController {
+	defaultLogger := slog.Default()
+	return st_c.NewController(
+		cfg,
+		defaultLogger,
+		a_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		o_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		sf_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+	)
+}
+
+func RunStatementGenerate(ctrl st_c.StatementController) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 1, 0)
+	if statementFrom != "" {
+		t, err := time.ParseInLocation("2006-01-02", statementFrom, time.Local)
+		if err != nil {
+			log.Fatal("invalid from:", err)
+		}
+		from = t
+	}
+	if statementTo != "" {
+		t, err := time.ParseInLocation("2006-01-02", statementTo, time.Local)
+		if err != nil {
+			log.Fatal("invalid to:", err)
+		}
+		to = t.AddDate(0, 0, 1) // Include the last day.
+	}
+	if !to.After(from) {
+		log.Fatal("the period must end after it starts")
+	}
+
+	ctx := context.Background()
+	var statements []*st_c.Statement
+	if statementAssociateID != "" {
+		associateID, err := primitive.ObjectIDFromHex(statementAssociateID)
+		if err != nil {
+			log.Fatal("invalid associate:", err)
+		}
+		s, err := ctrl.Generate(ctx, associateID, from, to, statementDryRun)
+		if err != nil {
+			log.Fatal(err)
+		}
+		statements = append(statements, s)
+	} else {
+		ss, err := ctrl.GenerateAll(ctx, from, to, statementDryRun)
+		if err != nil {
+			log.Fatal(err)
+		}
+		statements = ss
+	}
+
+	content, err := st_b.Build(statementFormat, statements)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if statementOutput == "" {
+		os.Stdout.Write(content)
+		return
+	}
+	if err := os.WriteFile(statementOutput, content, 0644); err != nil {
+		log.Fatal(err)
+	}
+	if statementDryRun {
+		fmt.Printf("%v statement(s) written to %v, no changes were saved\n", len(statements), statementOutput)
+		return
+	}
+	fmt.Printf("%v statement(s) written to %v\n", len(statements), statementOutput)
+}
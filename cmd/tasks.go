@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	auditlog_ds "github.com/over55/workery-cli/app/auditlog/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	ti_c "github.com/over55/workery-cli/app/taskitem/controller"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	"github.com/over55/workery-cli/app/taskitem/digest"
+	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	"github.com/over55/workery-cli/config"
+	"github.com/over55/workery-cli/provider/notifier"
+)
+
+// ex:
+// $ go run main.go tasks sync --dry-run
+// $ go run main.go tasks sync
+// $ go run main.go tasks digest --format=markdown
+// $ go run main.go tasks digest --format=html --postponed=2 --send --to=coordinators@example.com
+
+var (
+	tasksDryRun          bool
+	tasksDigestFormat    string
+	tasksDigestOutput    string
+	tasksDigestPostponed int
+	tasksDigestSend      bool
+	tasksDigestTo        []string
+)
+
+func init() {
+	tasksSyncCmd.Flags().BoolVarP(&tasksDryRun, "dry-run", "d", false, "Report the changes without saving them")
+	tasksSyncCmd.Annotations = requirePermission("order:update")
+	tasksCmd.AddCommand(tasksSyncCmd)
+
+	tasksDigestCmd.Flags().StringVarP(&tasksDigestFormat, "format", "m", digest.FormatMarkdown, "Output format, either markdown, html or json")
+	tasksDigestCmd.Flags().StringVarP(&tasksDigestOutput, "output", "o", "", "File to write the digest to, defaults to stdout unless sending")
+	tasksDigestCmd.Flags().IntVarP(&tasksDigestPostponed, "postponed", "p", 2, "List task items postponed more than this many times")
+	tasksDigestCmd.Flags().BoolVarP(&tasksDigestSend, "send", "s", false, "Send the digest by email through the SMTP server")
+	tasksDigestCmd.Flags().StringSliceVarP(&tasksDigestTo, "to", "t", nil, "Email address to send the digest to, defaults to WORKERY_TASKITEM_DIGEST_RECIPIENTS")
+	tasksDigestCmd.Annotations = requirePermission("report:read")
+	tasksCmd.AddCommand(tasksDigestCmd)
+
+	tasksCmd.Annotations = requirePermission("order:read")
+	rootCmd.AddCommand(tasksCmd)
+}
+
+var tasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "Manage the follow up task items of orders",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var tasksSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Create, close and link the task items of every order to match its status",
+	Long: `Applies the task item workflow to every order: open task items the order
+has moved past are closed, the task item the order is waiting on is created
+(unless the order already had one of that type) and the latest pending task
+of the order is updated. Task items are otherwise created when an order is
+moved with: order transition.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		RunTasksSync(newTaskItemController(cfg, mc, getOrderTenant(cfg, mc)))
+	},
+}
+
+var tasksDigestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "List the open task items which are overdue, due today or repeatedly postponed",
+	Long: `Groups the open task items by the staff member who created them and by type
+into three sections: overdue, due today and postponed more than --postponed
+times. The digest is written as Markdown, HTML or JSON and can be sent by email
+through the SMTP server configured with the WORKERY_SMTP_* variables, giving up
+after WORKERY_SMTP_TIMEOUT (30s by default).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+
+		var provider notifier.Provider
+		if tasksDigestSend {
+			if len(tasksDigestTo) == 0 && cfg.TaskItem.DigestRecipients != "" {
+				tasksDigestTo = strings.Split(cfg.TaskItem.DigestRecipients, ",")
+			}
+			p, err := notifier.NewSMTPProvider(cfg)
+			if err != nil {
+				log.Fatal(err)
+			}
+			provider = p
+		}
+		RunTasksDigest(newTaskItemController(cfg, mc, getOrderTenant(cfg, mc)), provider, cfg.SMTP.Timeout)
+	},
+}
+
+func newTaskItemController(cfg *config.Conf, mc *mongo.Client, tenant *tenant_ds.Tenant) ti_c.TaskItemController {
+	defaultLogger := slog.Default()
+	return ti_c.NewController(
+		cfg,
+		defaultLogger,
+		o_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		ti_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+		auditlog_ds.NewDatastore(cfg, defaultLogger, mc),
+	)
+}
+
+func RunTasksSync(ctrl ti_c.TaskItemController) {
+	changes, err := ctrl.Sync(context.Background(), tasksDryRun)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var created, closed int
+	for _, c := range changes {
+		next := "-"
+		if c.Result.Created != nil {
+			next = c.Result.Created.Title
+			created++
+		}
+		closed += len(c.Result.Closed)
+		fmt.Printf("%v\t%v\t%v closed\tcreated: %v\n", c.WJID, o_ds.OrderStatusLabels[c.Status], len(c.Result.Closed), next)
+	}
+	if tasksDryRun {
+		fmt.Printf("%v order(s), %v task item(s) would be created and %v closed, no changes were saved\n", len(changes), created, closed)
+		return
+	}
+	fmt.Printf("%v order(s), %v task item(s) created and %v closed\n", len(changes), created, closed)
+}
+
+func RunTasksDigest(ctrl ti_c.TaskItemController, provider notifier.Provider, sendTimeout time.Duration) {
+	d, err := ctrl.Digest(context.Background(), tasksDigestPostponed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	content, err := digest.Render(d, tasksDigestFormat)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if tasksDigestOutput != "" {
+		if err := os.WriteFile(tasksDigestOutput, content, 0644); err != nil {
+			log.Fatal(err)
+		}
+	} else if provider == nil {
+		os.Stdout.Write(content)
+	}
+
+	if provider != nil {
+		var to []string
+		for _, addr := range tasksDigestTo {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				to = append(to, addr)
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+		err := provider.Send(ctx, &notifier.Message{
+			To:          to,
+			Subject:     fmt.Sprintf("Task digest for %v", time.Now().Format("2006-01-02")),
+			Body:        content,
+			ContentType: digest.ContentType(tasksDigestFormat),
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("digest of %v owner(s) sent to %v\n", len(d.Owners), strings.Join(to, ", "))
+	}
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	as_ds "github.com/over55/workery-cli/app/activitysheet/datastore"
+	a_ds "github.com/over55/workery-cli/app/associate/datastore"
+	aal_ds "github.com/over55/workery-cli/app/associateawaylog/datastore"
+	attachment_ds "github.com/over55/workery-cli/app/attachment/datastore"
+	bulletin_ds "github.com/over55/workery-cli/app/bulletin/datastore"
+	com_ds "github.com/over55/workery-cli/app/comment/datastore"
+	c_ds "github.com/over55/workery-cli/app/customer/datastore"
+	hh_ds "github.com/over55/workery-cli/app/howhear/datastore"
+	ir_ds "github.com/over55/workery-cli/app/insurancerequirement/datastore"
+	o_ds "github.com/over55/workery-cli/app/order/datastore"
+	sf_ds "github.com/over55/workery-cli/app/servicefee/datastore"
+	ss_ds "github.com/over55/workery-cli/app/skillset/datastore"
+	s_ds "github.com/over55/workery-cli/app/staff/datastore"
+	tag_ds "github.com/over55/workery-cli/app/tag/datastore"
+	ti_ds "github.com/over55/workery-cli/app/taskitem/datastore"
+	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	vt_ds "github.com/over55/workery-cli/app/vehicletype/datastore"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go trash list --collection="attachments"
+// $ go run main.go trash restore --collection="attachments" --id="64d0f8c2a1b2c3d4e5f60718"
+// $ go run main.go trash purge --older-than=30
+
+var (
+	trashCollection    string
+	trashDocumentID    string
+	trashOlderThanDays int
+)
+
+func init() {
+	trashListCmd.Flags().StringVarP(&trashCollection, "collection", "c", "", "Only show the trash of this collection, ex: attachments")
+	trashCmd.AddCommand(trashListCmd)
+
+	trashRestoreCmd.Flags().StringVarP(&trashCollection, "collection", "c", "", "Name of the collection the document belongs to, ex: attachments")
+	trashRestoreCmd.MarkFlagRequired("collection")
+	trashRestoreCmd.Flags().StringVarP(&trashDocumentID, "id", "i", "", "ID of the document to restore")
+	trashRestoreCmd.MarkFlagRequired("id")
+	trashCmd.AddCommand(trashRestoreCmd)
+
+	trashPurgeCmd.Flags().StringVarP(&trashCollection, "collection", "c", "", "Only purge the trash of this collection, ex: attachments")
+	trashPurgeCmd.Flags().IntVarP(&trashOlderThanDays, "older-than", "o", 30, "Purge documents which were deleted more than this many days ago")
+	trashCmd.AddCommand(trashPurgeCmd)
+
+	rootCmd.AddCommand(trashCmd)
+}
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage deleted documents",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the documents in the trash",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		RunTrashList(newTrashBins(cfg, mc))
+	},
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a document from the trash",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		RunTrashRestore(newTrashBins(cfg, mc))
+	},
+}
+
+var trashPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently delete old documents from the trash",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		RunTrashPurge(newTrashBins(cfg, mc))
+	},
+}
+
+// trashItem is the subset of fields shared by every document in the trash.
+type trashItem struct {
+	ID                primitive.ObjectID `bson:"_id"`
+	TenantID          primitive.ObjectID `bson:"tenant_id"`
+	DeletedAt         time.Time          `bson:"deleted_at"`
+	DeletedByUserName string             `bson:"deleted_by_user_name"`
+}
+
+type trashableStorer[T any] interface {
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	PermanentlyDeleteByID(ctx context.Context, id primitive.ObjectID) error
+	ListDeletedBefore(ctx context.Context, deletedBefore time.Time) ([]*T, error)
+}
+
+// trashBin provides the trash operations of a single collection regardless
+// of the type of document it stores.
+type trashBin struct {
+	list    func(ctx context.Context, deletedBefore time.Time) ([]*trashItem, error)
+	restore func(ctx context.Context, id primitive.ObjectID) error
+	purge   func(ctx context.Context, id primitive.ObjectID) error
+}
+
+func newTrashBin[T any](s trashableStorer[T]) *trashBin {
+	return &trashBin{
+		list: func(ctx context.Context, deletedBefore time.Time) ([]*trashItem, error) {
+			docs, err := s.ListDeletedBefore(ctx, deletedBefore)
+			if err != nil {
+				return nil, err
+			}
+			items := make([]*trashItem, 0, len(docs))
+			for _, doc := range docs {
+				raw, err := bson.Marshal(doc)
+				if err != nil {
+					return nil, err
+				}
+				item := &trashItem{}
+				if err := bson.Unmarshal(raw, item); err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			}
+			return items, nil
+		},
+		restore: s.RestoreByID,
+		purge:   s.PermanentlyDeleteByID,
+	}
+}
+
+// newTrashBins returns the trash of every collection. The trash is managed
+// across all tenants so the storers are not bound to one.
+func newTrashBins(cfg *config.Conf, mc *mongo.Client) map[string]*trashBin {
+	defaultLogger := slog.Default()
+	return map[string]*trashBin{
+		"activity_sheets":         newTrashBin[as_ds.ActivitySheet](as_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"associates":              newTrashBin[a_ds.Associate](a_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"associate_away_log":      newTrashBin[aal_ds.AssociateAwayLog](aal_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"attachments":             newTrashBin[attachment_ds.Attachment](attachment_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"bulletins":               newTrashBin[bulletin_ds.Bulletin](bulletin_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"comments":                newTrashBin[com_ds.Comment](com_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"customers":               newTrashBin[c_ds.Customer](c_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"how_hear_about_us_items": newTrashBin[hh_ds.HowHearAboutUsItem](hh_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"insurance_requirements":  newTrashBin[ir_ds.InsuranceRequirement](ir_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"orders":                  newTrashBin[o_ds.Order](o_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"service_fees":            newTrashBin[sf_ds.ServiceFee](sf_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"skill_sets":              newTrashBin[ss_ds.SkillSet](ss_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"staff":                   newTrashBin[s_ds.Staff](s_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"tags":                    newTrashBin[tag_ds.Tag](tag_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"task_items":              newTrashBin[ti_ds.TaskItem](ti_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"tenants":                 newTrashBin[tenant_ds.Tenant](tenant_ds.NewDatastore(cfg, defaultLogger, mc)),
+		"users":                   newTrashBin[user_ds.User](user_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+		"vehicle_types":           newTrashBin[vt_ds.VehicleType](vt_ds.NewDatastore(cfg, defaultLogger, mc).AllTenants()),
+	}
+}
+
+// selectTrashBins returns the names of the collections to operate on which is
+// either the one given by the `--collection` flag or all of them.
+func selectTrashBins(bins map[string]*trashBin) []string {
+	if trashCollection != "" {
+		if _, ok := bins[trashCollection]; !ok {
+			log.Fatalf("unsupported collection: %v\n", trashCollection)
+		}
+		return []string{trashCollection}
+	}
+	names := make([]string, 0, len(bins))
+	for name := range bins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func RunTrashList(bins map[string]*trashBin) {
+	ctx := context.Background()
+
+	var total int
+	for _, name := range selectTrashBins(bins) {
+		items, err := bins[name].list(ctx, time.Time{})
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, item := range items {
+			fmt.Printf("%s\t%s\tdeleted %s by %s\n",
+				name,
+				item.ID.Hex(),
+				item.DeletedAt.Format("2006-01-02 15:04:05 MST"),
+				item.DeletedByUserName)
+		}
+		total += len(items)
+	}
+	fmt.Printf("%v document(s) in the trash\n", total)
+}
+
+func RunTrashRestore(bins map[string]*trashBin) {
+	ctx := context.Background()
+
+	bin, ok := bins[trashCollection]
+	if !ok {
+		log.Fatalf("unsupported collection: %v\n", trashCollection)
+	}
+	id, err := primitive.ObjectIDFromHex(trashDocumentID)
+	if err != nil {
+		log.Fatal("invalid document id:", err)
+	}
+	if err := bin.restore(ctx, id); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Document successfully restored")
+}
+
+func RunTrashPurge(bins map[string]*trashBin) {
+	ctx := context.Background()
+
+	if trashOlderThanDays < 0 {
+		log.Fatal("older-than must not be negative")
+	}
+	deletedBefore := time.Now().AddDate(0, 0, -trashOlderThanDays)
+
+	var total int
+	for _, name := range selectTrashBins(bins) {
+		items, err := bins[name].list(ctx, deletedBefore)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, item := range items {
+			if err := bins[name].purge(ctx, item.ID); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if len(items) > 0 {
+			log.Printf("purged %v document(s) from %s\n", len(items), name)
+		}
+		total += len(items)
+	}
+	fmt.Printf("%v document(s) permanently deleted\n", total)
+}
@@ -0,0 +1,487 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/term"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	tenant_ds "github.com/over55/workery-cli/app/tenant/datastore"
+	user_c "github.com/over55/workery-cli/app/user/controller"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	"github.com/over55/workery-cli/config"
+	"github.com/over55/workery-cli/provider/password"
+)
+
+// ex:
+// $ go run main.go user create --email=b@b.com --first-name=Bart --last-name=Mika --role=staff
+// $ go run main.go user list --role=management --json
+// $ go run main.go user show b@b.com
+// $ go run main.go user set-role b@b.com executive
+// $ go run main.go user archive 42
+// $ go run main.go user activate 42
+// $ echo "secret" | go run main.go user reset-password b@b.com --password-stdin
+// $ go run main.go user verify-email b@b.com
+// $ go run main.go user unlock b@b.com
+// $ go run main.go user audit-passwords
+//
+// The user is either referenced by its ID, public ID or email. The command
+// exits with 2 for invalid arguments, 3 if the user does not exist, 4 if the
+// email is already taken, 5 if a two-factor code does not match, 6 if the
+// --operator may not run the command and 1 for any other error.
+
+const (
+	userExitError    = 1
+	userExitUsage    = 2
+	userExitNotFound = 3
+	userExitConflict = 4
+	userExitBadCode  = 5
+)
+
+var (
+	userJSON          bool
+	userEmail         string
+	userFirstName     string
+	userLastName      string
+	userRole          string
+	userStatus        string
+	userPasswordStdin bool
+	userAuditAll      bool
+)
+
+func init() {
+	userCmd.PersistentFlags().BoolVarP(&userJSON, "json", "j", false, "Print the result as JSON")
+
+	userCreateCmd.Flags().StringVarP(&userEmail, "email", "e", "", "Email of the user account")
+	userCreateCmd.MarkFlagRequired("email")
+	userCreateCmd.Flags().StringVarP(&userFirstName, "first-name", "f", "", "First name of the user")
+	userCreateCmd.Flags().StringVarP(&userLastName, "last-name", "l", "", "Last name of the user")
+	userCreateCmd.Flags().StringVarP(&userRole, "role", "r", "staff", "Role, either executive, management, staff, associate or customer")
+	userCreateCmd.Flags().BoolVar(&userPasswordStdin, "password-stdin", false, "Read the password from stdin instead of prompting")
+	userCreateCmd.Annotations = requirePermission("user:create")
+	userCmd.AddCommand(userCreateCmd)
+
+	userListCmd.Flags().StringVarP(&userRole, "role", "r", "", "Only list the users with the role")
+	userListCmd.Flags().StringVarP(&userStatus, "status", "s", "", "Only list the users with the status, either active or archived")
+	userListCmd.Annotations = requirePermission("user:read")
+	userCmd.AddCommand(userListCmd)
+
+	userShowCmd.Annotations = requirePermission("user:read")
+	userCmd.AddCommand(userShowCmd)
+	userSetRoleCmd.Annotations = requirePermission("user:admin")
+	userCmd.AddCommand(userSetRoleCmd)
+	userArchiveCmd.Annotations = requirePermission("user:update")
+	userCmd.AddCommand(userArchiveCmd)
+	userActivateCmd.Annotations = requirePermission("user:update")
+	userCmd.AddCommand(userActivateCmd)
+
+	userResetPasswordCmd.Flags().BoolVar(&userPasswordStdin, "password-stdin", false, "Read the password from stdin instead of prompting")
+	userResetPasswordCmd.Annotations = requirePermission("user:update")
+	userCmd.AddCommand(userResetPasswordCmd)
+
+	userVerifyEmailCmd.Annotations = requirePermission("user:update")
+	userCmd.AddCommand(userVerifyEmailCmd)
+	userUnlockCmd.Annotations = requirePermission("user:update")
+	userCmd.AddCommand(userUnlockCmd)
+
+	userAuditPasswordsCmd.Flags().BoolVarP(&userAuditAll, "all", "a", false, "Also list the users whose password hash is up to date")
+	userAuditPasswordsCmd.Annotations = requirePermission("user:read")
+	userCmd.AddCommand(userAuditPasswordsCmd)
+
+	userCmd.Annotations = requirePermission("user:read")
+	rootCmd.AddCommand(userCmd)
+}
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage the accounts users log in with",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var userCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a user account, the password is prompted for",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		role, err := user_c.ParseRole(userRole)
+		if err != nil {
+			exitUser(err)
+		}
+		authorizeOperatorRole(role)
+		plainPassword, err := readUserPassword(userPasswordStdin)
+		if err != nil {
+			exitUser(err)
+		}
+
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		tenant := getOrderTenant(cfg, mc)
+		u, err := newUserController(cfg, mc, tenant).Create(context.Background(), &user_c.CreateRequest{
+			TenantID:  tenant.ID,
+			Email:     userEmail,
+			FirstName: userFirstName,
+			LastName:  userLastName,
+			Role:      role,
+			Password:  plainPassword,
+		})
+		if err != nil {
+			exitUser(err)
+		}
+		printUser(u)
+	},
+}
+
+var userListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the user accounts",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		var role, status int8
+		if userRole != "" {
+			r, err := user_c.ParseRole(userRole)
+			if err != nil {
+				exitUser(err)
+			}
+			role = r
+		}
+		switch strings.ToLower(userStatus) {
+		case "":
+		case "active":
+			status = user_ds.UserStatusActive
+		case "archived":
+			status = user_ds.UserStatusArchived
+		default:
+			exitUser(fmt.Errorf("%w: unsupported status: %v", errUserUsage, userStatus))
+		}
+
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		users, err := newUserController(cfg, mc, getOrderTenant(cfg, mc)).List(context.Background(), role, status)
+		if err != nil {
+			exitUser(err)
+		}
+		printUsers(users)
+	},
+}
+
+var userShowCmd = &cobra.Command{
+	Use:   "show <user>",
+	Short: "Print a user account",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runUserAction(func(ctrl user_c.UserController) (*user_ds.User, error) {
+			return ctrl.Get(context.Background(), args[0])
+		})
+	},
+}
+
+var userSetRoleCmd = &cobra.Command{
+	Use:   "set-role <user> <role>",
+	Short: "Change the role of a user, either executive, management, staff, associate or customer",
+	Long: `Changes the role of a user. The --operator needs the user:admin permission,
+which only executives have by default.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		role, err := user_c.ParseRole(args[1])
+		if err != nil {
+			exitUser(err)
+		}
+		runUserAction(func(ctrl user_c.UserController) (*user_ds.User, error) {
+			return ctrl.SetRole(context.Background(), args[0], role)
+		})
+	},
+}
+
+var userArchiveCmd = &cobra.Command{
+	Use:   "archive <user>",
+	Short: "Archive a user so they can no longer log in",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runUserUpdate(args[0], func(ctrl user_c.UserController) (*user_ds.User, error) {
+			return ctrl.SetStatus(context.Background(), args[0], user_ds.UserStatusArchived)
+		})
+	},
+}
+
+var userActivateCmd = &cobra.Command{
+	Use:   "activate <user>",
+	Short: "Activate an archived user",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runUserUpdate(args[0], func(ctrl user_c.UserController) (*user_ds.User, error) {
+			return ctrl.SetStatus(context.Background(), args[0], user_ds.UserStatusActive)
+		})
+	},
+}
+
+var userResetPasswordCmd = &cobra.Command{
+	Use:   "reset-password <user>",
+	Short: "Set the password of a user, the password is prompted for",
+	Long: `Sets the password of a user. The password is prompted for without being
+echoed, or read from the first line of stdin with --password-stdin. It is never
+accepted as an argument so it does not end up in the shell history.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		plainPassword, err := readUserPassword(userPasswordStdin)
+		if err != nil {
+			exitUser(err)
+		}
+		runUserUpdate(args[0], func(ctrl user_c.UserController) (*user_ds.User, error) {
+			return ctrl.ResetPassword(context.Background(), args[0], plainPassword)
+		})
+	},
+}
+
+var userVerifyEmailCmd = &cobra.Command{
+	Use:   "verify-email <user>",
+	Short: "Mark the email of a user as verified",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runUserUpdate(args[0], func(ctrl user_c.UserController) (*user_ds.User, error) {
+			return ctrl.VerifyEmail(context.Background(), args[0])
+		})
+	},
+}
+
+var userUnlockCmd = &cobra.Command{
+	Use:   "unlock <user>",
+	Short: "Clear the failed login attempts of a user which locked their account",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runUserUpdate(args[0], func(ctrl user_c.UserController) (*user_ds.User, error) {
+			return ctrl.Unlock(context.Background(), args[0])
+		})
+	},
+}
+
+var userAuditPasswordsCmd = &cobra.Command{
+	Use:   "audit-passwords",
+	Short: "List the users whose password hash is a placeholder, legacy or made with outdated parameters",
+	Long: `Inspects the password hash of every user without needing their password.
+Hashes imported from Django (PBKDF2) or made with weaker argon2id parameters
+than WORKERY_PASSWORD_ARGON2_* are listed as rehash, they are replaced the
+next time the user logs in. Placeholder hashes are listed by: credentials list.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+		audits, err := newUserController(cfg, mc, getOrderTenant(cfg, mc)).AuditPasswords(context.Background())
+		if err != nil {
+			exitUser(err)
+		}
+		printPasswordAudits(audits)
+	},
+}
+
+var errUserUsage = errors.New("invalid arguments")
+
+func newUserController(cfg *config.Conf, mc *mongo.Client, tenant *tenant_ds.Tenant) user_c.UserController {
+	defaultLogger := slog.Default()
+	passp, err := password.NewProviderFromConfig(cfg)
+	if err != nil {
+		exitUser(err)
+	}
+	return user_c.NewController(
+		cfg,
+		defaultLogger,
+		passp,
+		user_ds.NewDatastore(cfg, defaultLogger, mc).ForTenant(tenant.ID),
+	)
+}
+
+func withUserController(run func(ctrl user_c.UserController)) {
+	cfg := config.New()
+	mc := mongodb.NewStorage(cfg)
+	run(newUserController(cfg, mc, getOrderTenant(cfg, mc)))
+}
+
+func runUserAction(action func(ctrl user_c.UserController) (*user_ds.User, error)) {
+	withUserController(func(ctrl user_c.UserController) {
+		u, err := action(ctrl)
+		if err != nil {
+			exitUser(err)
+		}
+		printUser(u)
+	})
+}
+
+// runUserUpdate runs the action on the user once the operator is known to be
+// allowed to change the account, so managers cannot take over the account of
+// an executive.
+func runUserUpdate(ref string, action func(ctrl user_c.UserController) (*user_ds.User, error)) {
+	runUserAction(func(ctrl user_c.UserController) (*user_ds.User, error) {
+		u, err := ctrl.Get(context.Background(), ref)
+		if err != nil {
+			return nil, err
+		}
+		authorizeOperatorRole(u.Role)
+		return action(ctrl)
+	})
+}
+
+// exitUser prints the error and exits with the code of its kind.
+func exitUser(err error) {
+	code := userExitError
+	switch {
+	case errors.Is(err, user_c.ErrUserNotFound):
+		code = userExitNotFound
+	case errors.Is(err, user_c.ErrEmailTaken),
+		errors.Is(err, user_c.ErrOTPAlreadyInUse):
+		code = userExitConflict
+	case errors.Is(err, user_c.ErrInvalidOTPCode),
+		errors.Is(err, user_c.ErrOTPNotEnrolled):
+		code = userExitBadCode
+	case errors.Is(err, errUserUsage),
+		errors.Is(err, user_c.ErrInvalidRole),
+		errors.Is(err, user_c.ErrInvalidEmail),
+		errors.Is(err, user_c.ErrInvalidPassword),
+		errors.Is(err, password.ErrWeakPassword):
+		code = userExitUsage
+	}
+	if userJSON {
+		json.NewEncoder(os.Stderr).Encode(map[string]string{"error": err.Error()})
+	} else {
+		fmt.Fprintln(os.Stderr, "error:", err)
+	}
+	os.Exit(code)
+}
+
+// readUserPassword prompts for the password twice without echoing it, or
+// reads the first line of stdin when `fromStdin` is set.
+func readUserPassword(fromStdin bool) (string, error) {
+	if fromStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("%w: stdin is not a terminal, use --password-stdin", errUserUsage)
+	}
+	fmt.Fprint(os.Stderr, "Password: ")
+	first, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprint(os.Stderr, "Confirm password: ")
+	second, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if string(first) != string(second) {
+		return "", fmt.Errorf("%w: passwords do not match", errUserUsage)
+	}
+	return string(first), nil
+}
+
+// redactUser returns a copy of the user without its secrets.
+func redactUser(u *user_ds.User) *user_ds.User {
+	r := *u
+	r.PasswordHash = ""
+	r.Salt = ""
+	r.PrAccessCode = ""
+	r.EmailVerificationCode = ""
+	return &r
+}
+
+func printUser(u *user_ds.User) {
+	if userJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(redactUser(u)); err != nil {
+			exitUser(err)
+		}
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%v\n", u.ID.Hex())
+	fmt.Fprintf(tw, "Public ID:\t%v\n", u.PublicID)
+	fmt.Fprintf(tw, "Email:\t%v\n", u.Email)
+	fmt.Fprintf(tw, "Name:\t%v\n", u.Name)
+	fmt.Fprintf(tw, "Role:\t%v\n", user_ds.UserRoleLabels[u.Role])
+	fmt.Fprintf(tw, "Status:\t%v\n", user_ds.UserStatusLabels[u.Status])
+	fmt.Fprintf(tw, "Email verified:\t%v\n", u.WasEmailVerified)
+	fmt.Fprintf(tw, "Two-factor:\t%v\n", u.OTPEnabled && u.OTPVerified)
+	fmt.Fprintf(tw, "Failed logins:\t%v\n", u.FailedLoginAttempts)
+	if !u.LockedUntil.IsZero() {
+		fmt.Fprintf(tw, "Locked until:\t%v\n", u.LockedUntil.Format("2006-01-02 15:04"))
+	}
+	fmt.Fprintf(tw, "Modified:\t%v by %v\n", u.ModifiedAt.Format("2006-01-02 15:04"), u.ModifiedByUserName)
+	tw.Flush()
+}
+
+func printUsers(users []*user_ds.User) {
+	if userJSON {
+		redacted := make([]*user_ds.User, 0, len(users))
+		for _, u := range users {
+			redacted = append(redacted, redactUser(u))
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(redacted); err != nil {
+			exitUser(err)
+		}
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tPUBLIC ID\tEMAIL\tNAME\tROLE\tSTATUS\tVERIFIED\t")
+	for _, u := range users {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t\n", u.ID.Hex(), u.PublicID, u.Email, u.Name, user_ds.UserRoleLabels[u.Role], user_ds.UserStatusLabels[u.Status], u.WasEmailVerified)
+	}
+	tw.Flush()
+	fmt.Printf("%v user(s)\n", len(users))
+}
+
+func printPasswordAudits(audits []*user_c.PasswordAudit) {
+	listed := make([]*user_c.PasswordAudit, 0, len(audits))
+	for _, a := range audits {
+		if userAuditAll || a.Status != user_c.PasswordAuditOK {
+			listed = append(listed, a)
+		}
+	}
+
+	if userJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(listed); err != nil {
+			exitUser(err)
+		}
+		return
+	}
+
+	counts := make(map[string]int)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tEMAIL\tNAME\tROLE\tALGORITHM\tSTATUS\t")
+	for _, a := range listed {
+		counts[a.Status]++
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\t\n", a.PublicID, a.Email, a.Name, a.Role, a.Algorithm, a.Status)
+	}
+	tw.Flush()
+	fmt.Printf("%v user(s) audited, %v need rehashing, %v placeholder, %v unknown\n",
+		len(audits), counts[user_c.PasswordAuditRehash], counts[user_c.PasswordAuditPlaceholder], counts[user_c.PasswordAuditUnknown])
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	user_c "github.com/over55/workery-cli/app/user/controller"
+	user_ds "github.com/over55/workery-cli/app/user/datastore"
+	"github.com/over55/workery-cli/provider/otp"
+)
+
+// ex:
+// $ go run main.go user 2fa enroll b@b.com
+// $ go run main.go user 2fa verify b@b.com 123456
+// $ go run main.go user 2fa status b@b.com
+// $ go run main.go user 2fa disable b@b.com
+
+var (
+	user2FAIssuer string
+	user2FAForce  bool
+	user2FANoQR   bool
+)
+
+func init() {
+	user2FAEnrollCmd.Flags().StringVarP(&user2FAIssuer, "issuer", "i", "Workery", "Name the authenticator app lists the account under")
+	user2FAEnrollCmd.Flags().BoolVarP(&user2FAForce, "force", "f", false, "Replace the secret of a user who already verified two-factor authentication")
+	user2FAEnrollCmd.Flags().BoolVar(&user2FANoQR, "no-qr", false, "Do not print the QR code")
+	user2FAEnrollCmd.Annotations = requirePermission("user:update")
+	user2FACmd.AddCommand(user2FAEnrollCmd)
+	user2FAVerifyCmd.Annotations = requirePermission("user:update")
+	user2FACmd.AddCommand(user2FAVerifyCmd)
+	user2FADisableCmd.Annotations = requirePermission("user:update")
+	user2FACmd.AddCommand(user2FADisableCmd)
+	user2FAStatusCmd.Annotations = requirePermission("user:read")
+	user2FACmd.AddCommand(user2FAStatusCmd)
+
+	user2FACmd.Annotations = requirePermission("user:read")
+	userCmd.AddCommand(user2FACmd)
+}
+
+var user2FACmd = &cobra.Command{
+	Use:   "2fa",
+	Short: "Manage the two-factor authentication (TOTP) of a user",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var user2FAEnrollCmd = &cobra.Command{
+	Use:   "enroll <user>",
+	Short: "Generate a new secret and recovery codes for a user",
+	Long: `Generates a new TOTP secret and prints it with its otpauth URL and QR code
+for the user to scan with their authenticator app, along with the recovery
+codes. The recovery codes are only stored hashed, this is the only time they
+are shown. Two-factor authentication is enforced once a code is verified.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		withUserController(func(ctrl user_c.UserController) {
+			u, err := ctrl.Get(context.Background(), args[0])
+			if err != nil {
+				exitUser(err)
+			}
+			authorizeOperatorRole(u.Role)
+			e, err := ctrl.EnrollOTP(context.Background(), args[0], user2FAIssuer, user2FAForce)
+			if err != nil {
+				exitUser(err)
+			}
+			printOTPEnrolment(e)
+		})
+	},
+}
+
+var user2FAVerifyCmd = &cobra.Command{
+	Use:   "verify <user> <code>",
+	Short: "Check a code of the authenticator, or use up a recovery code, of a user",
+	Long:  ``,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runUserUpdate(args[0], func(ctrl user_c.UserController) (*user_ds.User, error) {
+			return ctrl.VerifyOTP(context.Background(), args[0], args[1])
+		})
+	},
+}
+
+var user2FADisableCmd = &cobra.Command{
+	Use:   "disable <user>",
+	Short: "Turn off two-factor authentication for a user who lost their authenticator",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runUserUpdate(args[0], func(ctrl user_c.UserController) (*user_ds.User, error) {
+			return ctrl.DisableOTP(context.Background(), args[0])
+		})
+	},
+}
+
+var user2FAStatusCmd = &cobra.Command{
+	Use:   "status <user>",
+	Short: "Print whether a user has two-factor authentication turned on",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		withUserController(func(ctrl user_c.UserController) {
+			u, err := ctrl.Get(context.Background(), args[0])
+			if err != nil {
+				exitUser(err)
+			}
+			printOTPStatus(u)
+		})
+	},
+}
+
+func printOTPEnrolment(e *user_c.OTPEnrolment) {
+	if userJSON {
+		e.User = redactUser(e.User)
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(e); err != nil {
+			exitUser(err)
+		}
+		return
+	}
+
+	fmt.Printf("Secret:   %v\n", e.Secret)
+	fmt.Printf("Auth URL: %v\n", e.AuthURL)
+	if !user2FANoQR {
+		fmt.Println()
+		if err := otp.WriteQRCode(os.Stdout, e.AuthURL); err != nil {
+			exitUser(err)
+		}
+	}
+	fmt.Println()
+	fmt.Println("Recovery codes, each can be used once and they will not be shown again:")
+	for _, code := range e.RecoveryCodes {
+		fmt.Printf("  %v\n", code)
+	}
+	fmt.Println()
+	fmt.Printf("Run: user 2fa verify %v <code>, to turn on two-factor authentication\n", e.User.Email)
+}
+
+func printOTPStatus(u *user_ds.User) {
+	status := struct {
+		Email         string `json:"email"`
+		Enabled       bool   `json:"enabled"`
+		Verified      bool   `json:"verified"`
+		RecoveryCodes int    `json:"recovery_codes"`
+	}{u.Email, u.OTPEnabled, u.OTPVerified, len(u.OTPRecoveryCodeHashes)}
+
+	if userJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(status); err != nil {
+			exitUser(err)
+		}
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Email:\t%v\n", status.Email)
+	fmt.Fprintf(tw, "Enabled:\t%v\n", status.Enabled)
+	fmt.Fprintf(tw, "Verified:\t%v\n", status.Verified)
+	fmt.Fprintf(tw, "Recovery codes left:\t%v\n", status.RecoveryCodes)
+	tw.Flush()
+}
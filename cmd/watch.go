@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/over55/workery-cli/adapter/storage/mongodb"
+	resync_c "github.com/over55/workery-cli/app/resync/controller"
+	"github.com/over55/workery-cli/config"
+)
+
+// ex:
+// $ go run main.go watch
+// $ go run main.go watch --from-now
+
+var (
+	watchFromNow bool
+)
+
+func init() {
+	watchCmd.Flags().BoolVarP(&watchFromNow, "from-now", "n", false, "Discard the saved stream positions and only process new changes")
+	rootCmd.AddCommand(watchCmd)
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Keep embedded copies in sync as their source documents change",
+	Long: `Opens a change stream on the customers, associates, service_fees, skill_sets
+and tags collections and refreshes the copies embedded in orders, task items,
+activity sheets and comments whenever they change. Requires MongoDB to be
+running as a replica set.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New()
+		mc := mongodb.NewStorage(cfg)
+
+		// Changes are streamed from every tenant.
+		RunWatch(newCrossTenantResyncController(cfg, mc))
+	},
+}
+
+func RunWatch(ctrl resync_c.ResyncController) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := ctrl.Watch(ctx, watchFromNow); err != nil {
+		log.Fatal(err)
+	}
+	log.Println("watch stopped")
+}
@@ -4,13 +4,22 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Conf struct {
-	PostgresDB postgresDBConfig
-	DB         mongoDBConfig
-	AWS        awsConfig
-	OldAWS     awsConfig
+	PostgresDB     postgresDBConfig
+	DB             mongoDBConfig
+	AWS            awsConfig
+	OldAWS         awsConfig
+	InvoiceBuilder invoiceBuilderConfig
+	Compliance     complianceConfig
+	TaskItem       taskItemConfig
+	SMTP           smtpConfig
+	Password       passwordConfig
+	Permission     permissionConfig
+	Geocoder       geocoderConfig
+	Privacy        privacyConfig
 }
 
 type mongoDBConfig struct {
@@ -28,6 +37,68 @@ type postgresDBConfig struct {
 	DatabaseLondonSchemaName string
 }
 
+type invoiceBuilderConfig struct {
+	PDFTemplateFilePath string
+}
+
+// complianceConfig holds how many days the dates recorded on an associate
+// stay valid for, the insurance expiry dates are used as they are.
+type complianceConfig struct {
+	WsibValidityDays        int
+	PoliceCheckValidityDays int
+	DuesValidityDays        int
+	ExpiringWithinDays      int
+}
+
+// taskItemConfig holds how many days after it is created each type of task
+// item is due.
+type taskItemConfig struct {
+	AssignedAssociateDueDays       int
+	AssociateAcceptJobDueDays      int
+	UpdateOngoingJobDueDays        int
+	AssociateCompleteJobDueDays    int
+	CustomerReviewAssociateDueDays int
+	DigestRecipients               string
+}
+
+type smtpConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Sender   string
+	Timeout  time.Duration // Timeout bounds sending one message, from dialing to QUIT.
+}
+
+// passwordConfig holds the argon2id parameters new password hashes are made
+// with (hashes made with weaker parameters need rehashing) and the policy new
+// passwords must meet.
+type passwordConfig struct {
+	Argon2Memory           uint32 // KiB
+	Argon2Iterations       uint32
+	Argon2Parallelism      uint8
+	MinLength              int
+	MaxLength              int
+	BreachedListFilePath   string
+	BannedPatternsFilePath string // One regular expression per line.
+}
+
+type permissionConfig struct {
+	OverridesFilePath string
+}
+
+// geocoderConfig holds which geocoder provider fills in the coordinates of
+// addresses and the postal code centroid file the offline provider uses.
+type geocoderConfig struct {
+	Provider           string
+	PostalCodeFilePath string
+}
+
+// privacyConfig holds the key the erasure receipts are signed with.
+type privacyConfig struct {
+	ReceiptSigningKey string
+}
+
 type awsConfig struct {
 	AccessKey      string
 	SecretKey      string
@@ -61,6 +132,34 @@ func New() *Conf {
 	c.OldAWS.Endpoint = getEnv("WORKERY_BACKEND_OLD_AWS_ENDPOINT", true)
 	c.OldAWS.Region = getEnv("WORKERY_BACKEND_OLD_AWS_REGION", true)
 	c.OldAWS.BucketName = getEnv("WORKERY_BACKEND_OLD_AWS_BUCKET_NAME", true)
+	c.InvoiceBuilder.PDFTemplateFilePath = getEnv("WORKERY_INVOICEBUILDER_PDF_TEMPLATE_FILE_PATH", false)
+	c.Compliance.WsibValidityDays = getEnvInt("WORKERY_COMPLIANCE_WSIB_VALIDITY_DAYS", false, 365)
+	c.Compliance.PoliceCheckValidityDays = getEnvInt("WORKERY_COMPLIANCE_POLICE_CHECK_VALIDITY_DAYS", false, 365)
+	c.Compliance.DuesValidityDays = getEnvInt("WORKERY_COMPLIANCE_DUES_VALIDITY_DAYS", false, 365)
+	c.Compliance.ExpiringWithinDays = getEnvInt("WORKERY_COMPLIANCE_EXPIRING_WITHIN_DAYS", false, 30)
+	c.TaskItem.AssignedAssociateDueDays = getEnvInt("WORKERY_TASKITEM_ASSIGNED_ASSOCIATE_DUE_DAYS", false, 1)
+	c.TaskItem.AssociateAcceptJobDueDays = getEnvInt("WORKERY_TASKITEM_ASSOCIATE_ACCEPT_JOB_DUE_DAYS", false, 2)
+	c.TaskItem.UpdateOngoingJobDueDays = getEnvInt("WORKERY_TASKITEM_UPDATE_ONGOING_JOB_DUE_DAYS", false, 30)
+	c.TaskItem.AssociateCompleteJobDueDays = getEnvInt("WORKERY_TASKITEM_ASSOCIATE_COMPLETE_JOB_DUE_DAYS", false, 7)
+	c.TaskItem.CustomerReviewAssociateDueDays = getEnvInt("WORKERY_TASKITEM_CUSTOMER_REVIEW_ASSOCIATE_DUE_DAYS", false, 3)
+	c.TaskItem.DigestRecipients = getEnv("WORKERY_TASKITEM_DIGEST_RECIPIENTS", false)
+	c.SMTP.Host = getEnv("WORKERY_SMTP_HOST", false)
+	c.SMTP.Port = getEnvInt("WORKERY_SMTP_PORT", false, 25)
+	c.SMTP.Username = getEnv("WORKERY_SMTP_USERNAME", false)
+	c.SMTP.Password = getEnv("WORKERY_SMTP_PASSWORD", false)
+	c.SMTP.Sender = getEnv("WORKERY_SMTP_SENDER", false)
+	c.SMTP.Timeout = getEnvDuration("WORKERY_SMTP_TIMEOUT", false, 30*time.Second)
+	c.Password.Argon2Memory = uint32(getEnvInt("WORKERY_PASSWORD_ARGON2_MEMORY", false, 64*1024))
+	c.Password.Argon2Iterations = uint32(getEnvInt("WORKERY_PASSWORD_ARGON2_ITERATIONS", false, 3))
+	c.Password.Argon2Parallelism = uint8(getEnvInt("WORKERY_PASSWORD_ARGON2_PARALLELISM", false, 2))
+	c.Password.MinLength = getEnvInt("WORKERY_PASSWORD_MIN_LENGTH", false, 8)
+	c.Password.MaxLength = getEnvInt("WORKERY_PASSWORD_MAX_LENGTH", false, 128)
+	c.Password.BreachedListFilePath = getEnv("WORKERY_PASSWORD_BREACHED_LIST_FILE_PATH", false)
+	c.Password.BannedPatternsFilePath = getEnv("WORKERY_PASSWORD_BANNED_PATTERNS_FILE_PATH", false)
+	c.Permission.OverridesFilePath = getEnv("WORKERY_PERMISSION_OVERRIDES_FILE_PATH", false)
+	c.Geocoder.Provider = getEnv("WORKERY_GEOCODER_PROVIDER", false)
+	c.Geocoder.PostalCodeFilePath = getEnv("WORKERY_GEOCODER_POSTAL_CODE_FILE_PATH", false)
+	c.Privacy.ReceiptSigningKey = getEnv("WORKERY_PRIVACY_RECEIPT_SIGNING_KEY", false)
 
 	return &c
 }
@@ -84,3 +183,27 @@ func getEnvBool(key string, required bool, defaultValue bool) bool {
 	}
 	return value
 }
+
+func getEnvInt(key string, required bool, defaultValue int) int {
+	valueStr := getEnv(key, required)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		log.Fatalf("Invalid integer value for environment variable %s", key)
+	}
+	return value
+}
+
+func getEnvDuration(key string, required bool, defaultValue time.Duration) time.Duration {
+	valueStr := getEnv(key, required)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		log.Fatalf("Invalid duration value for environment variable %s", key)
+	}
+	return value
+}
@@ -0,0 +1,53 @@
+package geocoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	c "github.com/over55/workery-cli/config"
+)
+
+const (
+	ProviderOffline = "offline"
+
+	// How precise the coordinates of a result are.
+	AccuracyPostalCode = "postal_code"
+	AccuracyFSA        = "forward_sortation_area"
+)
+
+var (
+	ErrNotFound        = errors.New("address could not be geocoded")
+	ErrUnknownProvider = errors.New("unknown geocoder provider")
+)
+
+// Query is the address to find the coordinates of.
+type Query struct {
+	Line       string
+	City       string
+	Region     string
+	PostalCode string
+	Country    string
+}
+
+// Result is the coordinates found for an address.
+type Result struct {
+	Latitude  float64
+	Longitude float64
+	Accuracy  string
+}
+
+// Provider finds the coordinates of addresses. It returns `ErrNotFound` when
+// it has no coordinates for the address.
+type Provider interface {
+	Geocode(ctx context.Context, q *Query) (*Result, error)
+}
+
+// NewProviderFromConfig returns the provider selected in the config.
+func NewProviderFromConfig(appCfg *c.Conf) (Provider, error) {
+	switch appCfg.Geocoder.Provider {
+	case "", ProviderOffline:
+		return NewOfflineProvider(appCfg.Geocoder.PostalCodeFilePath)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, appCfg.Geocoder.Provider)
+}
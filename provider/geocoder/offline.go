@@ -0,0 +1,128 @@
+package geocoder
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var ErrOfflineNotConfigured = errors.New("postal code centroid file path is not configured")
+
+type centroid struct {
+	latitude  float64
+	longitude float64
+}
+
+type offlineProvider struct {
+	postalCodes map[string]centroid
+	fsas        map[string]centroid
+}
+
+// NewOfflineProvider returns a provider which looks up the centroid of the
+// postal code of the address in a local CSV file, falling back to the
+// centroid of its forward sortation area (the first three characters). The
+// file must have a header with `postal_code`, `latitude` and `longitude`
+// columns, any other column is ignored.
+func NewOfflineProvider(filePath string) (Provider, error) {
+	if filePath == "" {
+		return nil, ErrOfflineNotConfigured
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return newOfflineProvider(f)
+}
+
+func newOfflineProvider(r io.Reader) (*offlineProvider, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	col := map[string]int{}
+	for i, name := range header {
+		col[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	pcCol, ok1 := col["postal_code"]
+	latCol, ok2 := col["latitude"]
+	lngCol, ok3 := col["longitude"]
+	if !ok1 || !ok2 || !ok3 {
+		return nil, errors.New("postal code centroid file needs postal_code, latitude and longitude columns")
+	}
+
+	p := &offlineProvider{
+		postalCodes: map[string]centroid{},
+		fsas:        map[string]centroid{},
+	}
+	fsaCounts := map[string]int{}
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(row) <= pcCol || len(row) <= latCol || len(row) <= lngCol {
+			return nil, fmt.Errorf("postal code centroid file line %d is missing columns", line)
+		}
+		pc := postalCodeKey(row[pcCol])
+		lat, err := strconv.ParseFloat(strings.TrimSpace(row[latCol]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("postal code centroid file line %d: %w", line, err)
+		}
+		lng, err := strconv.ParseFloat(strings.TrimSpace(row[lngCol]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("postal code centroid file line %d: %w", line, err)
+		}
+		if len(pc) < 3 {
+			continue
+		}
+		p.postalCodes[pc] = centroid{latitude: lat, longitude: lng}
+
+		// Keep a running average of the forward sortation area.
+		fsa := pc[:3]
+		n := fsaCounts[fsa]
+		prev := p.fsas[fsa]
+		p.fsas[fsa] = centroid{
+			latitude:  (prev.latitude*float64(n) + lat) / float64(n+1),
+			longitude: (prev.longitude*float64(n) + lng) / float64(n+1),
+		}
+		fsaCounts[fsa] = n + 1
+	}
+	return p, nil
+}
+
+func (p *offlineProvider) Geocode(ctx context.Context, q *Query) (*Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	pc := postalCodeKey(q.PostalCode)
+	if ce, ok := p.postalCodes[pc]; ok {
+		return &Result{Latitude: ce.latitude, Longitude: ce.longitude, Accuracy: AccuracyPostalCode}, nil
+	}
+	if len(pc) >= 3 {
+		if ce, ok := p.fsas[pc[:3]]; ok {
+			return &Result{Latitude: ce.latitude, Longitude: ce.longitude, Accuracy: AccuracyFSA}, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func postalCodeKey(s string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' {
+			return -1
+		}
+		return r
+	}, s))
+}
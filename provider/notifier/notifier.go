@@ -0,0 +1,16 @@
+package notifier
+
+import "context"
+
+// Message is a notification to deliver to one or more recipients.
+type Message struct {
+	To          []string
+	Subject     string
+	Body        []byte
+	ContentType string // ex: `text/html`, defaults to `text/plain`.
+}
+
+// Provider delivers notifications, ex: by email.
+type Provider interface {
+	Send(ctx context.Context, m *Message) error
+}
@@ -0,0 +1,140 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	c "github.com/over55/workery-cli/config"
+)
+
+var (
+	ErrSMTPNotConfigured = errors.New("smtp host and sender are not configured")
+	ErrNoRecipients      = errors.New("message has no recipients")
+)
+
+type smtpProvider struct {
+	addr     string
+	host     string
+	username string
+	password string
+	sender   string
+}
+
+// NewSMTPProvider returns a provider which sends messages through the SMTP
+// server of the config. Authentication is only used when a username is set
+// so a local fake server, ex: MailHog, works without credentials.
+func NewSMTPProvider(appCfg *c.Conf) (Provider, error) {
+	if appCfg.SMTP.Host == "" || appCfg.SMTP.Sender == "" {
+		return nil, ErrSMTPNotConfigured
+	}
+	return &smtpProvider{
+		addr:     net.JoinHostPort(appCfg.SMTP.Host, fmt.Sprint(appCfg.SMTP.Port)),
+		host:     appCfg.SMTP.Host,
+		username: appCfg.SMTP.Username,
+		password: appCfg.SMTP.Password,
+		sender:   appCfg.SMTP.Sender,
+	}, nil
+}
+
+func (p *smtpProvider) Send(ctx context.Context, m *Message) error {
+	if len(m.To) == 0 {
+		return ErrNoRecipients
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", p.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Closing the connection unblocks the client if the context is done
+	// before the server answers.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := p.send(conn, m); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	return nil
+}
+
+// send delivers the message over the connection the way `smtp.SendMail`
+// does, upgrading to TLS when the server supports it.
+func (p *smtpProvider) send(conn net.Conn, m *Message) error {
+	client, err := smtp.NewClient(conn, p.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: p.host}); err != nil {
+			return err
+		}
+	}
+	if p.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", p.username, p.password, p.host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(p.sender); err != nil {
+		return err
+	}
+	for _, to := range m.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(p.build(m)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (p *smtpProvider) build(m *Message) []byte {
+	contentType := m.ContentType
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %v\r\n", p.sender)
+	fmt.Fprintf(&buf, "To: %v\r\n", strings.Join(m.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %v\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %v\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: %v; charset=\"utf-8\"\r\n", contentType)
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.Write(bytes.ReplaceAll(bytes.ReplaceAll(m.Body, []byte("\r\n"), []byte("\n")), []byte("\n"), []byte("\r\n")))
+	return buf.Bytes()
+}
@@ -0,0 +1,161 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	c "github.com/over55/workery-cli/config"
+)
+
+// fakeSMTPServer accepts one connection and records the envelope and data
+// of the message sent over it. When `greet` is false the server never
+// answers, like a stalled relay.
+type fakeSMTPServer struct {
+	ln    net.Listener
+	greet bool
+
+	from string
+	rcpt []string
+	data string
+	done chan struct{}
+}
+
+func newFakeSMTPServer(t *testing.T, greet bool) *fakeSMTPServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSMTPServer{ln: ln, greet: greet, done: make(chan struct{})}
+	t.Cleanup(func() { ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	if !s.greet {
+		// Wait for the client to hang up.
+		bufio.NewReader(conn).ReadByte()
+		return
+	}
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP fake")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch verb {
+		case "EHLO":
+			tp.PrintfLine("250 localhost")
+		case "MAIL":
+			s.from = strings.TrimPrefix(line, "MAIL FROM:")
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			s.rcpt = append(s.rcpt, strings.TrimPrefix(line, "RCPT TO:"))
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			b, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.data = string(b)
+			tp.PrintfLine("250 OK")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func newTestSMTPProvider(t *testing.T, addr string) Provider {
+	t.Helper()
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &c.Conf{}
+	cfg.SMTP.Host = host
+	cfg.SMTP.Port, _ = strconv.Atoi(port)
+	cfg.SMTP.Sender = "noreply@workery.test"
+	p, err := NewSMTPProvider(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestSMTPProviderSend(t *testing.T) {
+	s := newFakeSMTPServer(t, true)
+	p := newTestSMTPProvider(t, s.ln.Addr().String())
+
+	err := p.Send(context.Background(), &Message{
+		To:      []string{"alice@workery.test", "bob@workery.test"},
+		Subject: "Invoice ready",
+		Body:    []byte("line one\nline two\n"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-s.done
+
+	if s.from != "<noreply@workery.test>" {
+		t.Errorf("from is %q", s.from)
+	}
+	if got := strings.Join(s.rcpt, ","); got != "<alice@workery.test>,<bob@workery.test>" {
+		t.Errorf("recipients are %q", got)
+	}
+	for _, want := range []string{
+		"To: alice@workery.test, bob@workery.test\n",
+		"Subject: Invoice ready\n",
+		"Content-Type: text/plain; charset=\"utf-8\"\n",
+		"\n\nline one\nline two\n",
+	} {
+		if !strings.Contains(s.data, want) {
+			t.Errorf("data %q does not contain %q", s.data, want)
+		}
+	}
+}
+
+func TestSMTPProviderSendHonoursContext(t *testing.T) {
+	s := newFakeSMTPServer(t, false)
+	p := newTestSMTPProvider(t, s.ln.Addr().String())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := p.Send(ctx, &Message{To: []string{"alice@workery.test"}, Subject: "Stalled"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error is %v, expected %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("send returned after %v", elapsed)
+	}
+	<-s.done
+}
+
+func TestSMTPProviderSendNoRecipients(t *testing.T) {
+	p := newTestSMTPProvider(t, "127.0.0.1:25")
+	if err := p.Send(context.Background(), &Message{}); !errors.Is(err, ErrNoRecipients) {
+		t.Fatalf("error is %v, expected %v", err, ErrNoRecipients)
+	}
+}
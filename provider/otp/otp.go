@@ -0,0 +1,146 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DEVELOPERS NOTE:
+// The codes are the time-based one-time passwords of RFC 6238 with the
+// defaults every authenticator app supports: HMAC-SHA1, 6 digits and a 30
+// second period.
+
+const (
+	Digits = 6
+	Period = 30 * time.Second
+
+	// Skew is how many periods before and after the current one a code is
+	// still accepted for, to allow for clock drift.
+	Skew = 1
+
+	secretLength       = 20
+	recoveryCodeLength = 10
+)
+
+var ErrInvalidSecret = errors.New("the otp secret is not valid base32")
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random base32 encoded secret.
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// AuthURL returns the `otpauth://` URL authenticator apps are enrolled with.
+func AuthURL(issuer, accountName, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(Digits))
+	v.Set("period", fmt.Sprint(int(Period.Seconds())))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + accountName,
+		RawQuery: v.Encode(),
+	}
+	return u.String()
+}
+
+// Code returns the code of the secret for the time.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", ErrInvalidSecret
+	}
+	return hotp(key, uint64(t.Unix()/int64(Period.Seconds()))), nil
+}
+
+// Validate returns true if the code is the code of the secret for the time
+// or for one of the periods of the skew around it.
+func Validate(secret, code string, now time.Time) bool {
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return false
+	}
+	for i := -Skew; i <= Skew; i++ {
+		expected, err := Code(secret, now.Add(time.Duration(i)*Period))
+		if err != nil {
+			return false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func hotp(key []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation as described in RFC 4226 section 5.4.
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", Digits, value%mod)
+}
+
+// GenerateRecoveryCodes returns `n` random one-time recovery codes to show
+// the user once, and their hashes to store in their place.
+func GenerateRecoveryCodes(n int) (codes []string, hashes []string, err error) {
+	for i := 0; i < n; i++ {
+		b := make([]byte, recoveryCodeLength)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(encoding.EncodeToString(b))[:recoveryCodeLength]
+		code = code[:5] + "-" + code[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode returns the hash a recovery code is stored as. The codes
+// are random so a plain SHA-256 is enough.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// ConsumeRecoveryCode returns the hashes without the one of the code, and
+// false if the code is not one of them.
+func ConsumeRecoveryCode(hashes []string, code string) ([]string, bool) {
+	h := HashRecoveryCode(code)
+	for i, stored := range hashes {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(h)) == 1 {
+			remaining := append(make([]string, 0, len(hashes)-1), hashes[:i]...)
+			return append(remaining, hashes[i+1:]...), true
+		}
+	}
+	return hashes, false
+}
@@ -0,0 +1,124 @@
+package otp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the base32 of the SHA-1 key of the RFC 6238 test vectors,
+// the ASCII string "12345678901234567890".
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// rfcVectors are the SHA-1 rows of RFC 6238 appendix B. The RFC lists eight
+// digit codes, our six digit codes are their last six digits.
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},          // 94287082
+	{1111111109, "081804"},  // 07081804
+	{1111111111, "050471"},  // 14050471
+	{1234567890, "005924"},  // 89005924
+	{2000000000, "279037"},  // 69279037
+	{20000000000, "353130"}, // 65353130
+}
+
+func TestCode(t *testing.T) {
+	for _, v := range rfcVectors {
+		got, err := Code(rfcSecret, time.Unix(v.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != v.code {
+			t.Errorf("code at %v is %v, expected %v", v.unix, got, v.code)
+		}
+	}
+
+	// Secrets are accepted in lower case and with padding.
+	if got, _ := Code("gezdgnbvgy3tqojqgezdgnbvgy3tqojq====", time.Unix(59, 0)); got != "287082" {
+		t.Errorf("code of the lower case secret is %v", got)
+	}
+	if _, err := Code("not base32!", time.Unix(59, 0)); !errors.Is(err, ErrInvalidSecret) {
+		t.Errorf("error is %v, expected %v", err, ErrInvalidSecret)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, v := range rfcVectors {
+		now := time.Unix(v.unix, 0)
+		if !Validate(rfcSecret, v.code, now) {
+			t.Errorf("code %v is not valid at %v", v.code, v.unix)
+		}
+		if !Validate(rfcSecret, " "+v.code+"\n", now) {
+			t.Errorf("code %v with spaces is not valid at %v", v.code, v.unix)
+		}
+	}
+
+	// The code of 1111111109 is valid for the periods of the skew around it.
+	const code = "081804"
+	for _, tt := range []struct {
+		name  string
+		unix  int64
+		valid bool
+	}{
+		{"same period", 1111111109, true},
+		{"one period later", 1111111109 + 30, true},
+		{"one period earlier", 1111111109 - 30, true},
+		{"two periods later", 1111111109 + 60, false},
+		{"two periods earlier", 1111111109 - 60, false},
+	} {
+		if got := Validate(rfcSecret, code, time.Unix(tt.unix, 0)); got != tt.valid {
+			t.Errorf("%v: valid is %v, expected %v", tt.name, got, tt.valid)
+		}
+	}
+
+	for _, bad := range []string{"", "08180", "0818044", "94287082", "000000"} {
+		if Validate(rfcSecret, bad, time.Unix(1111111109, 0)) {
+			t.Errorf("code %q is valid", bad)
+		}
+	}
+	if Validate("not base32!", code, time.Unix(1111111109, 0)) {
+		t.Error("code of an invalid secret is valid")
+	}
+}
+
+func TestConsumeRecoveryCode(t *testing.T) {
+	codes, hashes, err := GenerateRecoveryCodes(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 3 || len(hashes) != 3 {
+		t.Fatalf("%v codes and %v hashes, expected 3 of each", len(codes), len(hashes))
+	}
+
+	remaining, ok := ConsumeRecoveryCode(hashes, codes[1])
+	if !ok {
+		t.Fatalf("code %v was not consumed", codes[1])
+	}
+	if len(remaining) != 2 || remaining[0] != hashes[0] || remaining[1] != hashes[2] {
+		t.Errorf("remaining hashes are %v", remaining)
+	}
+	if len(hashes) != 3 || hashes[1] != HashRecoveryCode(codes[1]) {
+		t.Error("consuming a code modified the given hashes")
+	}
+
+	// The same code cannot be consumed twice.
+	if again, ok := ConsumeRecoveryCode(remaining, codes[1]); ok || len(again) != 2 {
+		t.Errorf("code %v was consumed twice, remaining hashes are %v", codes[1], again)
+	}
+
+	// Codes are accepted in upper case and without the dash.
+	remaining, ok = ConsumeRecoveryCode(remaining, " "+strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))+" ")
+	if !ok || len(remaining) != 1 || remaining[0] != hashes[2] {
+		t.Errorf("consumed is %v, remaining hashes are %v", ok, remaining)
+	}
+
+	if _, ok := ConsumeRecoveryCode(remaining, "aaaaa-aaaaa"); ok {
+		t.Error("unknown code was consumed")
+	}
+	if _, ok := ConsumeRecoveryCode(nil, codes[2]); ok {
+		t.Error("code was consumed from no hashes")
+	}
+}
@@ -0,0 +1,42 @@
+package otp
+
+import (
+	"io"
+	"strings"
+
+	"rsc.io/qr"
+)
+
+const quietZone = 2
+
+// WriteQRCode renders the text as a QR code with half block characters, two
+// rows of modules per line, so it can be scanned from the terminal.
+func WriteQRCode(w io.Writer, text string) error {
+	code, err := qr.Encode(text, qr.M)
+	if err != nil {
+		return err
+	}
+
+	// The light modules are drawn, terminals usually have a dark background.
+	light := func(x, y int) bool { return !code.Black(x, y) }
+
+	var sb strings.Builder
+	for y := -quietZone; y < code.Size+quietZone; y += 2 {
+		for x := -quietZone; x < code.Size+quietZone; x++ {
+			top, bottom := light(x, y), light(x, y+1)
+			switch {
+			case top && bottom:
+				sb.WriteString("█")
+			case top:
+				sb.WriteString("▀")
+			case bottom:
+				sb.WriteString("▄")
+			default:
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,81 @@
+package password
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// searchSortedHashes returns true if the upper case SHA-1 hex `hash` is in
+// the file, which must hold one hash per line sorted in ascending order. Only
+// a few lines are read per search so the file can be any size.
+func searchSortedHashes(filePath string, hash string) (bool, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	// Find the first offset whose line sorts at or after the hash. The line of
+	// an offset is the first one starting at or after it, so it never sorts
+	// before the line of a smaller offset.
+	lo, hi := int64(0), info.Size()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		line, err := lineAt(f, mid)
+		if err != nil {
+			return false, err
+		}
+		if line == "" || hashOfLine(line) >= hash {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	line, err := lineAt(f, lo)
+	if err != nil {
+		return false, err
+	}
+	return line != "" && hashOfLine(line) == hash, nil
+}
+
+// lineAt returns the first line starting at or after the offset, without its
+// line ending, or an empty string past the last line.
+func lineAt(f *os.File, offset int64) (string, error) {
+	start := offset
+	if start > 0 {
+		// Start one byte early so a line starting right at the offset is kept.
+		start--
+	}
+	r := bufio.NewReader(io.NewSectionReader(f, start, 1<<62))
+	if offset > 0 {
+		if _, err := r.ReadString('\n'); err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", nil
+			}
+			return "", err
+		}
+	}
+	line, err := r.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// hashOfLine returns the upper case hash of a line, without the `:<count>`
+// suffix of the Have I Been Pwned downloads.
+func hashOfLine(line string) string {
+	if len(line) > 40 {
+		line = line[:40]
+	}
+	return strings.ToUpper(line)
+}
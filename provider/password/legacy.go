@@ -0,0 +1,86 @@
+package password
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"hash"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	AlgorithmArgon2id     = "argon2id"
+	AlgorithmPBKDF2SHA256 = "pbkdf2_sha256"
+	AlgorithmPBKDF2SHA1   = "pbkdf2_sha1"
+	AlgorithmUnknown      = "unknown"
+)
+
+// Algorithm returns the algorithm the encoded hash was made with.
+func Algorithm(encodedHash string) string {
+	switch {
+	case strings.HasPrefix(encodedHash, "$argon2id$"):
+		return AlgorithmArgon2id
+	case strings.HasPrefix(encodedHash, AlgorithmPBKDF2SHA256+"$"):
+		return AlgorithmPBKDF2SHA256
+	case strings.HasPrefix(encodedHash, AlgorithmPBKDF2SHA1+"$"):
+		return AlgorithmPBKDF2SHA1
+	}
+	return AlgorithmUnknown
+}
+
+func isLegacyHash(encodedHash string) bool {
+	a := Algorithm(encodedHash)
+	return a == AlgorithmPBKDF2SHA256 || a == AlgorithmPBKDF2SHA1
+}
+
+// compareLegacyHash checks the password against a hash in the format of the
+// Django password hashers: `<algorithm>$<iterations>$<salt>$<base64 hash>`.
+func compareLegacyHash(password, encodedHash string) (bool, error) {
+	vals := strings.Split(encodedHash, "$")
+	if len(vals) != 4 {
+		return false, ErrInvalidHash
+	}
+	iterations, err := strconv.Atoi(vals[1])
+	if err != nil || iterations <= 0 {
+		return false, ErrInvalidHash
+	}
+	key, err := base64.StdEncoding.DecodeString(vals[3])
+	if err != nil {
+		return false, ErrInvalidHash
+	}
+
+	var h func() hash.Hash
+	switch vals[0] {
+	case AlgorithmPBKDF2SHA256:
+		h = sha256.New
+	case AlgorithmPBKDF2SHA1:
+		h = sha1.New
+	default:
+		return false, ErrInvalidHash
+	}
+
+	otherKey := pbkdf2.Key([]byte(password), []byte(vals[2]), iterations, len(key), h)
+	return subtle.ConstantTimeCompare(key, otherKey) == 1, nil
+}
+
+// RehashOnLogin checks the password against the hash and, when it matches but
+// the hash is legacy or outdated, returns the new hash to save in its place.
+// The new hash is empty when the hash does not need to be replaced.
+func RehashOnLogin(p Provider, password, encodedHash string) (match bool, newHash string, err error) {
+	match, err = p.ComparePasswordAndHash(password, encodedHash)
+	if err != nil || !match {
+		return match, "", err
+	}
+	if !p.NeedsRehash(encodedHash) {
+		return true, "", nil
+	}
+	newHash, err = p.GenerateHashFromPassword(password)
+	if err != nil {
+		return true, "", err
+	}
+	return true, newHash, nil
+}
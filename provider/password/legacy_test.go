@@ -0,0 +1,41 @@
+package password
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompareLegacyHash(t *testing.T) {
+	// The SHA-1 hashes are the RFC 6070 test vectors and the SHA-256 ones
+	// were made the way the Django `PBKDF2PasswordHasher` makes them.
+	for _, tt := range []struct {
+		name     string
+		password string
+		hash     string
+		match    bool
+		err      error
+	}{
+		{"sha1 one iteration", "password", "pbkdf2_sha1$1$salt$DGDID5YfDnHzqbUkr2ASBi/gN6Y=", true, nil},
+		{"sha1 many iterations", "password", "pbkdf2_sha1$4096$salt$SwB5AbdlSJq+rUnZJvch0GWkKcE=", true, nil},
+		{"sha1 wrong password", "Password", "pbkdf2_sha1$4096$salt$SwB5AbdlSJq+rUnZJvch0GWkKcE=", false, nil},
+		{"sha256", "correct horse battery staple", "pbkdf2_sha256$260000$Q1w2E3r4T5y6$rf4QcVg2bNcqEi+mKO/4TlyCDCdTGuEsuUZ6mcyaCEc=", true, nil},
+		{"sha256 unicode", "lètmein", "pbkdf2_sha256$870000$seasalt$wJSpLMQRQz0Dhj/pFpbyjMj71B2gUYp6HJS5AU+32Ac=", true, nil},
+		{"sha256 wrong password", "letmein", "pbkdf2_sha256$870000$seasalt$wJSpLMQRQz0Dhj/pFpbyjMj71B2gUYp6HJS5AU+32Ac=", false, nil},
+		{"sha256 wrong salt", "lètmein", "pbkdf2_sha256$870000$peppersalt$wJSpLMQRQz0Dhj/pFpbyjMj71B2gUYp6HJS5AU+32Ac=", false, nil},
+		{"sha256 wrong iterations", "lètmein", "pbkdf2_sha256$860000$seasalt$wJSpLMQRQz0Dhj/pFpbyjMj71B2gUYp6HJS5AU+32Ac=", false, nil},
+		{"missing field", "password", "pbkdf2_sha1$1$DGDID5YfDnHzqbUkr2ASBi/gN6Y=", false, ErrInvalidHash},
+		{"zero iterations", "password", "pbkdf2_sha1$0$salt$DGDID5YfDnHzqbUkr2ASBi/gN6Y=", false, ErrInvalidHash},
+		{"bad base64", "password", "pbkdf2_sha1$1$salt$not base64", false, ErrInvalidHash},
+		{"unknown algorithm", "password", "pbkdf2_md5$1$salt$DGDID5YfDnHzqbUkr2ASBi/gN6Y=", false, ErrInvalidHash},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := compareLegacyHash(tt.password, tt.hash)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error is %v, expected %v", err, tt.err)
+			}
+			if match != tt.match {
+				t.Errorf("match is %v, expected %v", match, tt.match)
+			}
+		})
+	}
+}
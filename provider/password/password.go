@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"golang.org/x/crypto/argon2"
+
+	c "github.com/over55/workery-cli/config"
 )
 
 var (
@@ -20,6 +22,8 @@ type Provider interface {
 	GenerateHashFromPassword(password string) (string, error)
 	ComparePasswordAndHash(password, hash string) (bool, error)
 	AlgorithmName() string
+	NeedsRehash(hash string) bool
+	ValidatePassword(password string, userInputs ...string) error
 }
 
 type passwordProvider struct {
@@ -28,6 +32,7 @@ type passwordProvider struct {
 	parallelism uint8
 	saltLength  uint32
 	keyLength   uint32
+	policy      *Policy
 }
 
 func NewProvider() Provider {
@@ -41,9 +46,27 @@ func NewProvider() Provider {
 		parallelism: 2,
 		saltLength:  16,
 		keyLength:   32,
+		policy:      &Policy{MinLength: 8, MaxLength: 128},
 	}
 }
 
+// NewProviderFromConfig returns the provider which hashes with the argon2id
+// parameters of the config and enforces its password policy.
+func NewProviderFromConfig(cfg *c.Conf) (Provider, error) {
+	policy, err := NewPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &passwordProvider{
+		memory:      cfg.Password.Argon2Memory,
+		iterations:  cfg.Password.Argon2Iterations,
+		parallelism: cfg.Password.Argon2Parallelism,
+		saltLength:  16,
+		keyLength:   32,
+		policy:      policy,
+	}, nil
+}
+
 // GenerateHashFromPassword function takes the plaintext string and returns an Argon2 hashed string.
 func (p *passwordProvider) GenerateHashFromPassword(password string) (string, error) {
 	// DEVELOPERS NOTE:
@@ -69,6 +92,12 @@ func (p *passwordProvider) GenerateHashFromPassword(password string) (string, er
 // CheckPasswordHash function checks the plaintext string and hash string and returns either true
 // or false depending.
 func (p *passwordProvider) ComparePasswordAndHash(password, encodedHash string) (match bool, err error) {
+	// Users imported from the old Django backend keep their PBKDF2 hashes
+	// until they log in and are rehashed.
+	if isLegacyHash(encodedHash) {
+		return compareLegacyHash(password, encodedHash)
+	}
+
 	// DEVELOPERS NOTE:
 	// The following code was copy and pasted from: "How to Hash and Verify Passwords With Argon2 in Go" via https://www.alexedwards.net/blog/how-to-hash-and-verify-passwords-with-argon2-in-go
 
@@ -93,7 +122,27 @@ func (p *passwordProvider) ComparePasswordAndHash(password, encodedHash string)
 
 // AlgorithmName function returns the algorithm used for hashing.
 func (p *passwordProvider) AlgorithmName() string {
-	return "argon2id"
+	return AlgorithmArgon2id
+}
+
+// NeedsRehash returns true if the hash was not made by argon2id or was made
+// with weaker parameters than the provider uses now.
+func (p *passwordProvider) NeedsRehash(encodedHash string) bool {
+	hp, _, _, err := decodeHash(encodedHash)
+	if err != nil {
+		return true
+	}
+	return hp.memory < p.memory ||
+		hp.iterations < p.iterations ||
+		hp.parallelism < p.parallelism ||
+		hp.saltLength < p.saltLength ||
+		hp.keyLength < p.keyLength
+}
+
+// ValidatePassword returns an error wrapping `ErrWeakPassword` if the password
+// does not meet the policy.
+func (p *passwordProvider) ValidatePassword(password string, userInputs ...string) error {
+	return p.policy.Validate(password, userInputs...)
 }
 
 func generateRandomBytes(n uint32) ([]byte, error) {
@@ -0,0 +1,49 @@
+package password
+
+import "testing"
+
+func TestNeedsRehash(t *testing.T) {
+	p := &passwordProvider{memory: 64 * 1024, iterations: 3, parallelism: 2, saltLength: 16, keyLength: 32}
+
+	// A 16 byte salt and a 32 byte key in unpadded base64.
+	const salt, key = "c2FsdHNhbHRzYWx0c2FsdA", "a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U"
+	for _, tt := range []struct {
+		name string
+		hash string
+		want bool
+	}{
+		{"same parameters", "$argon2id$v=19$m=65536,t=3,p=2$" + salt + "$" + key, false},
+		{"stronger parameters", "$argon2id$v=19$m=131072,t=4,p=4$" + salt + "$" + key, false},
+		{"less memory", "$argon2id$v=19$m=32768,t=3,p=2$" + salt + "$" + key, true},
+		{"fewer iterations", "$argon2id$v=19$m=65536,t=2,p=2$" + salt + "$" + key, true},
+		{"less parallelism", "$argon2id$v=19$m=65536,t=3,p=1$" + salt + "$" + key, true},
+		{"shorter salt", "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHQ$" + key, true},
+		{"shorter key", "$argon2id$v=19$m=65536,t=3,p=2$" + salt + "$a2V5a2V5a2V5a2V5", true},
+		{"older version", "$argon2id$v=16$m=65536,t=3,p=2$" + salt + "$" + key, true},
+		{"legacy", "pbkdf2_sha256$870000$seasalt$wJSpLMQRQz0Dhj/pFpbyjMj71B2gUYp6HJS5AU+32Ac=", true},
+		{"garbage", "not a hash", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.NeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("NeedsRehash is %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRehashOnLogin(t *testing.T) {
+	p := &passwordProvider{memory: 8 * 1024, iterations: 1, parallelism: 1, saltLength: 16, keyLength: 32}
+
+	match, newHash, err := RehashOnLogin(p, "password", "pbkdf2_sha1$1$salt$DGDID5YfDnHzqbUkr2ASBi/gN6Y=")
+	if err != nil || !match {
+		t.Fatalf("match is %v and error is %v", match, err)
+	}
+	if Algorithm(newHash) != AlgorithmArgon2id {
+		t.Fatalf("new hash %q is not argon2id", newHash)
+	}
+
+	match, again, err := RehashOnLogin(p, "password", newHash)
+	if err != nil || !match || again != "" {
+		t.Errorf("match is %v, new hash is %q and error is %v", match, again, err)
+	}
+}
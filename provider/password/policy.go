@@ -0,0 +1,190 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	c "github.com/over55/workery-cli/config"
+)
+
+var ErrWeakPassword = errors.New("password does not meet the policy")
+
+// Policy is what new passwords must meet. The breached list holds either the
+// passwords themselves or the upper case hex of their SHA-1 (the format of
+// the Have I Been Pwned downloads, the `:<count>` suffix is ignored).
+//
+// Breached lists up to `MaxInMemoryBreachedSize` bytes are loaded into
+// `Breached`. Larger lists must hold only SHA-1 hashes sorted in ascending
+// order, like the "ordered by hash" download, and are searched on disk.
+type Policy struct {
+	MinLength        int
+	MaxLength        int
+	Breached         map[string]struct{}
+	BreachedFilePath string
+	BannedPatterns   []*regexp.Regexp
+}
+
+// MaxInMemoryBreachedSize is the size of the largest breached password list
+// which is loaded into memory.
+const MaxInMemoryBreachedSize = 64 << 20
+
+// NewPolicy returns the policy of the config, loading the banned patterns and
+// the breached password list from their files.
+func NewPolicy(cfg *c.Conf) (*Policy, error) {
+	p := &Policy{
+		MinLength: cfg.Password.MinLength,
+		MaxLength: cfg.Password.MaxLength,
+		Breached:  make(map[string]struct{}),
+	}
+	if cfg.Password.BannedPatternsFilePath != "" {
+		if err := p.loadBannedPatterns(cfg.Password.BannedPatternsFilePath); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Password.BreachedListFilePath != "" {
+		if err := p.loadBreached(cfg.Password.BreachedListFilePath); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// loadBannedPatterns reads one regular expression per line, blank lines and
+// lines starting with `#` are skipped. Patterns are not trimmed beyond the
+// line ending so a leading or trailing space can be part of one.
+func (p *Policy) loadBannedPatterns(filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		expr := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(expr) == "" || strings.HasPrefix(expr, "#") {
+			continue
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("invalid banned password pattern %q: %w", expr, err)
+		}
+		p.BannedPatterns = append(p.BannedPatterns, re)
+	}
+	return scanner.Err()
+}
+
+// loadBreached reads the breached password list into memory or, when it is
+// too large for that, checks it is a list of SHA-1 hashes and keeps its path
+// so it can be searched on disk.
+func (p *Policy) loadBreached(filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() > MaxInMemoryBreachedSize {
+		line, err := bufio.NewReader(f).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if !isSHA1Hex(strings.TrimSpace(line)) {
+			return fmt.Errorf("breached password list %v is larger than %v bytes and must hold SHA-1 hashes sorted in ascending order", filePath, MaxInMemoryBreachedSize)
+		}
+		p.BreachedFilePath = filePath
+		return nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if isSHA1Hex(line) {
+			line = strings.ToUpper(line[:40])
+		}
+		p.Breached[line] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+// Validate returns an error wrapping `ErrWeakPassword` which lists every rule
+// the password breaks. The user inputs (their email, name, etc) must not be
+// part of the password.
+func (p *Policy) Validate(password string, userInputs ...string) error {
+	violations := make([]string, 0)
+
+	length := utf8.RuneCountInString(password)
+	if p.MinLength > 0 && length < p.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %v characters", p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %v characters", p.MaxLength))
+	}
+	breached, err := p.isBreached(password)
+	if err != nil {
+		return err
+	}
+	if breached {
+		violations = append(violations, "appears in a list of breached passwords")
+	}
+	for _, re := range p.BannedPatterns {
+		if re.MatchString(password) {
+			violations = append(violations, fmt.Sprintf("matches the banned pattern %q", re.String()))
+		}
+	}
+	lower := strings.ToLower(password)
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		if at := strings.Index(input, "@"); at > 0 {
+			input = input[:at]
+		}
+		if utf8.RuneCountInString(input) >= 3 && strings.Contains(lower, input) {
+			violations = append(violations, "must not contain the name or email of the user")
+			break
+		}
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%w: %v", ErrWeakPassword, strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+func (p *Policy) isBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	if p.BreachedFilePath != "" {
+		return searchSortedHashes(p.BreachedFilePath, hash)
+	}
+	if len(p.Breached) == 0 {
+		return false, nil
+	}
+	if _, ok := p.Breached[password]; ok {
+		return true, nil
+	}
+	_, ok := p.Breached[hash]
+	return ok, nil
+}
+
+func isSHA1Hex(line string) bool {
+	if len(line) < 40 || (len(line) > 40 && line[40] != ':') {
+		return false
+	}
+	_, err := hex.DecodeString(line[:40])
+	return err == nil
+}
@@ -0,0 +1,98 @@
+package password
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	p := &Policy{
+		MinLength: 8,
+		MaxLength: 16,
+		Breached: map[string]struct{}{
+			"trustno1!":                                {},
+			"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8": {}, // SHA-1 of "password"
+		},
+		BannedPatterns: []*regexp.Regexp{regexp.MustCompile(`(?i)workery`)},
+	}
+
+	for _, tt := range []struct {
+		name       string
+		password   string
+		userInputs []string
+		violation  string
+	}{
+		{"valid", "plum-cactus-42", nil, ""},
+		{"too short", "plum42", nil, "at least 8 characters"},
+		{"too long", "plum-cactus-42-gravel", nil, "at most 16 characters"},
+		{"counts runes", "ééééééé", nil, "at least 8 characters"},
+		{"breached", "trustno1!", nil, "breached"},
+		{"breached hash", "password", nil, "breached"},
+		{"banned pattern", "myWorkery2024", nil, "banned pattern"},
+		{"email", "Jdoe-cactus-42", []string{"jdoe@example.com"}, "name or email"},
+		{"name", "plum-alice-42", []string{"Alice"}, "name or email"},
+		{"short inputs ignored", "plum-cactus-42", []string{"al", "", "@x.com"}, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.Validate(tt.password, tt.userInputs...)
+			if tt.violation == "" {
+				if err != nil {
+					t.Fatalf("error is %v, expected none", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrWeakPassword) {
+				t.Fatalf("error is %v, expected %v", err, ErrWeakPassword)
+			}
+			if !strings.Contains(err.Error(), tt.violation) {
+				t.Errorf("error %q does not mention %q", err, tt.violation)
+			}
+		})
+	}
+}
+
+func TestSearchSortedHashes(t *testing.T) {
+	hashes := []string{
+		"0000000CAEF405439D57847A8657218C618160B2:15",
+		"21BD12DC183F740EE76F27B78EB39C8AD972A757:7",
+		"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:10434004",
+		"60B3AF8BFE3735623C7D4A5EF749BB6AC1A4413A:12",
+		"FFFFFFFEE791CBAC0F6305CAF0CEE06BBE131160:2",
+	}
+	for _, ending := range []string{"\n", "\r\n"} {
+		filePath := filepath.Join(t.TempDir(), "breached.txt")
+		if err := os.WriteFile(filePath, []byte(strings.Join(hashes, ending)), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, h := range hashes {
+			if ok, err := searchSortedHashes(filePath, h[:40]); err != nil || !ok {
+				t.Errorf("%q: found is %v and error is %v", h[:40], ok, err)
+			}
+		}
+		for _, h := range []string{
+			"0000000000000000000000000000000000000000",
+			"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD9",
+			"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF",
+		} {
+			if ok, err := searchSortedHashes(filePath, h); err != nil || ok {
+				t.Errorf("%q: found is %v and error is %v", h, ok, err)
+			}
+		}
+	}
+
+	p := &Policy{BreachedFilePath: filepath.Join(t.TempDir(), "breached.txt")}
+	if err := os.WriteFile(p.BreachedFilePath, []byte(strings.Join(hashes, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Validate("hunter22"); !errors.Is(err, ErrWeakPassword) {
+		t.Errorf("error is %v, expected %v", err, ErrWeakPassword)
+	}
+	if err := p.Validate("plum-cactus-42"); err != nil {
+		t.Errorf("error is %v, expected none", err)
+	}
+}